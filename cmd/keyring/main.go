@@ -0,0 +1,178 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"time"
+
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+)
+
+const usage = `usage: keyring <command> [flags]
+
+commands:
+  add                generate a new key that only verifies tokens, restart every server before promoting it
+  promote <key-id>   make the key the active signing key, the previous key retires after -retire-after
+  list               list the keys of the keyring`
+
+func main() {
+	config, err := util.LoadConfig(".", "app")
+	if err != nil {
+		log.Fatal("can not load config:", err)
+	}
+
+	if len(config.TokenKeyringFile) == 0 {
+		log.Fatal("TOKEN_KEYRING_FILE is not set")
+	}
+
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "add":
+		err = add(config)
+	case "promote":
+		promoteFlags := flag.NewFlagSet("promote", flag.ExitOnError)
+		retireAfter := promoteFlags.Duration("retire-after", config.RefreshTokenDuration, "time the previous key keeps verifying tokens")
+		promoteFlags.Parse(os.Args[2:])
+
+		if promoteFlags.NArg() != 1 {
+			fmt.Println(usage)
+			os.Exit(2)
+		}
+		err = promote(config, promoteFlags.Arg(0), *retireAfter)
+	case "list":
+		err = list(config)
+	default:
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// add adds a new key that only verifies tokens, the first time the keyring is created with the key
+// of the configuration so the tokens issued before keep working. The key is promoted once every
+// server has loaded it, so none of them rejects the tokens it signs.
+func add(config util.Config) error {
+	keyring, err := token.LoadKeyring(config.TokenKeyringFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		keyring, err = newKeyring(config)
+	}
+	if err != nil {
+		return err
+	}
+
+	key, err := keyring.NewKey()
+	if err != nil {
+		return fmt.Errorf("cannot add a key to the keyring: %w", err)
+	}
+
+	// Without an active key no server signs tokens yet, so the first key is active at once
+	firstKey := len(keyring.ActiveKeyID) == 0
+	if firstKey {
+		keyring.ActiveKeyID = key.ID
+	}
+
+	err = keyring.Save(config.TokenKeyringFile)
+	if err != nil {
+		return fmt.Errorf("cannot save the keyring: %w", err)
+	}
+
+	if firstKey {
+		log.Printf("key %s is the active %s key\n", key.ID, keyring.TokenType)
+		return nil
+	}
+	log.Printf("key %s was added to verify %s tokens, restart every server and then promote it with: keyring promote %s\n", key.ID, keyring.TokenType, key.ID)
+	return nil
+}
+
+// promote makes a key of the keyring the active one
+func promote(config util.Config, keyID string, retireAfter time.Duration) error {
+	keyring, err := token.LoadKeyring(config.TokenKeyringFile)
+	if err != nil {
+		return err
+	}
+
+	key, err := keyring.Promote(keyID, retireAfter)
+	if err != nil {
+		return fmt.Errorf("cannot promote the key: %w", err)
+	}
+
+	err = keyring.Save(config.TokenKeyringFile)
+	if err != nil {
+		return fmt.Errorf("cannot save the keyring: %w", err)
+	}
+
+	log.Printf("key %s is now the active %s key, restart the servers to use it\n", key.ID, keyring.TokenType)
+	return nil
+}
+
+func newKeyring(config util.Config) (*token.Keyring, error) {
+	keyring := &token.Keyring{TokenType: config.TokenType}
+	if len(keyring.TokenType) == 0 {
+		keyring.TokenType = token.PasetoLocalType
+	}
+
+	key := config.TokenSymmetricKey
+	if len(config.TokenPrivateKeyFile) > 0 {
+		privateKey, err := os.ReadFile(config.TokenPrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read token private key: %w", err)
+		}
+		key = string(privateKey)
+	}
+
+	if len(key) == 0 {
+		return keyring, nil
+	}
+
+	// Check the key before importing it
+	if _, err := token.NewMaker(keyring.TokenType, key); err != nil {
+		return nil, fmt.Errorf("cannot import the current key: %w", err)
+	}
+
+	imported, err := keyring.AddKey(key)
+	if err != nil {
+		return nil, err
+	}
+	keyring.ActiveKeyID = imported.ID
+
+	log.Printf("imported the current key as %s\n", imported.ID)
+	return keyring, nil
+}
+
+func list(config util.Config) error {
+	keyring, err := token.LoadKeyring(config.TokenKeyringFile)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("token type: %s\n", keyring.TokenType)
+	for _, key := range keyring.Keys {
+		status := "verify"
+		if key.ID == keyring.ActiveKeyID {
+			status = "active"
+		}
+		if key.Retired(time.Now()) {
+			status = "retired"
+		}
+
+		retiresAt := "-"
+		if key.RetiresAt != nil {
+			retiresAt = key.RetiresAt.Format(time.RFC3339)
+		}
+
+		fmt.Printf("%s\t%s\tcreated %s\tretires %s\n", key.ID, status, key.CreatedAt.Format(time.RFC3339), retiresAt)
+	}
+	return nil
+}
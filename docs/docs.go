@@ -60,7 +60,7 @@ const docTemplate = `{
                         "JWT": []
                     }
                 ],
-                "description": "Create a new Post",
+                "description": "Create a new Post, the logged user is its author and the co-authors are usernames.\nWithout a slug it is made from the title.\nThe content is rendered to sanitized HTML with a table of contents,\nthe unknown or invalid shortcodes are kept as text and listed in render_warnings.",
                 "consumes": [
                     "application/json"
                 ],
@@ -171,6 +171,46 @@ const docTemplate = `{
                 }
             }
         },
+        "/admin/post/preview": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Render the content of a post without saving it, the default format is markdown.\nThe unknown or invalid shortcodes are returned as warnings.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "post",
+                    "create"
+                ],
+                "summary": "Preview a Post",
+                "parameters": [
+                    {
+                        "description": "post Content",
+                        "name": "post",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.previewPostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.previewPostResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/admin/post/{id}": {
             "get": {
                 "security": [
@@ -214,7 +254,7 @@ const docTemplate = `{
                         "JWT": []
                     }
                 ],
-                "description": "Update a new Post",
+                "description": "Update a Post, authors can only update their own posts while editors can update any post.\nOnly the main author or an editor can change the co-authors.\nA new title makes a new slug unless a slug is sent, the previous slugs redirect to the post.",
                 "consumes": [
                     "application/json"
                 ],
@@ -316,6 +356,32 @@ const docTemplate = `{
                 }
             }
         },
+        "/admin/posts/render-warnings": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Return the posts whose content has unknown or invalid shortcodes with their warnings",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "post",
+                    "list"
+                ],
+                "summary": "List the Render Warnings of the Posts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.ListPostRenderWarningsRow"
+                        }
+                    }
+                }
+            }
+        },
         "/admin/tag-post/{id}": {
             "get": {
                 "security": [
@@ -354,6 +420,91 @@ const docTemplate = `{
                 }
             }
         },
+        "/authors": {
+            "get": {
+                "description": "Recive the public profile of all authors",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author",
+                    "list"
+                ],
+                "summary": "List Authors",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_api.authorResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/authors/{username}": {
+            "get": {
+                "description": "Recive the public profile of an author from its username",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author",
+                    "get"
+                ],
+                "summary": "Get an Author",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.authorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/authors/{username}/posts": {
+            "get": {
+                "description": "Recive the posts publics written or co-written by an author",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "post",
+                    "author",
+                    "list"
+                ],
+                "summary": "List Posts by Author",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.GetPostsByAuthorPublicRow"
+                        }
+                    }
+                }
+            }
+        },
         "/categories": {
             "get": {
                 "description": "Recive all categories",
@@ -385,7 +536,7 @@ const docTemplate = `{
                         "JWT": []
                     }
                 ],
-                "description": "Create a new Category",
+                "description": "Create a new Category, without a slug it is made from the name",
                 "consumes": [
                     "application/json"
                 ],
@@ -453,7 +604,7 @@ const docTemplate = `{
         },
         "/category/{id}": {
             "get": {
-                "description": "Recive the one category information from a id",
+                "description": "Recive the one category information from a id or a slug",
                 "consumes": [
                     "application/json"
                 ],
@@ -468,7 +619,7 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "id",
+                        "description": "id or slug",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -478,7 +629,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.Category"
+                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.LookupCategoryRow"
                         }
                     }
                 }
@@ -489,7 +640,7 @@ const docTemplate = `{
                         "JWT": []
                     }
                 ],
-                "description": "Update the category information",
+                "description": "Update the category information, the slug only changes when it is sent",
                 "consumes": [
                     "application/json"
                 ],
@@ -565,9 +716,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/login": {
+        "/email/verify": {
             "post": {
-                "description": "Login a user and return access token a refresh token",
+                "description": "Confirm an email with the token of a verification link, on an email change the new email takes effect",
                 "consumes": [
                     "application/json"
                 ],
@@ -576,17 +727,17 @@ const docTemplate = `{
                 ],
                 "tags": [
                     "user",
-                    "login"
+                    "email"
                 ],
-                "summary": "Login User",
+                "summary": "Verify Email",
                 "parameters": [
                     {
-                        "description": "User Login",
-                        "name": "user",
+                        "description": "Verification Token",
+                        "name": "token",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_api.loginUserRequest"
+                            "$ref": "#/definitions/internal_api.verifyEmailRequest"
                         }
                     }
                 ],
@@ -594,98 +745,68 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api.loginUserResponse"
+                            "$ref": "#/definitions/internal_api.userResponse"
                         }
                     }
                 }
             }
         },
-        "/post/{id}": {
+        "/highlight/style.css": {
             "get": {
-                "description": "Recive the one post public",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Return the stylesheet of the configured theme for the highlighted code in the posts",
                 "produces": [
-                    "application/json"
+                    "text/css"
                 ],
                 "tags": [
-                    "post",
-                    "get"
-                ],
-                "summary": "Get a Post by Id Public",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "highlight"
                 ],
+                "summary": "Get Default Highlight Theme",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.Post"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/posts": {
+        "/highlight/themes": {
             "get": {
-                "description": "Recive all posts publics",
+                "description": "Return the themes of the highlighted code in the posts and the theme used by default",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "post",
-                    "list"
+                    "highlight"
                 ],
-                "summary": "List all Posts",
+                "summary": "List Highlight Themes",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.ListPostsPublicRow"
+                            "$ref": "#/definitions/internal_api.listHighlightThemesResponse"
                         }
                     }
                 }
             }
         },
-        "/tag": {
-            "post": {
-                "security": [
-                    {
-                        "JWT": []
-                    }
-                ],
-                "description": "Create a new Tag, the image are upload to S3 services",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+        "/highlight/themes/{theme}/style.css": {
+            "get": {
+                "description": "Return the stylesheet of a theme for the highlighted code in the posts",
                 "produces": [
-                    "application/json"
+                    "text/css"
                 ],
                 "tags": [
-                    "tag",
-                    "create"
+                    "highlight"
                 ],
-                "summary": "Create a new Tag",
+                "summary": "Get Highlight Theme",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "This is the tag name",
-                        "name": "name",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "This is a image",
-                        "name": "logo",
-                        "in": "formData",
+                        "description": "Theme name",
+                        "name": "theme",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -693,15 +814,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.Tag"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/tag-post/{id}": {
-            "get": {
-                "description": "Recive the one post by Tag",
+        "/invite": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Send an invitation to an email, the invitee chooses its own username and password",
                 "consumes": [
                     "application/json"
                 ],
@@ -709,32 +835,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "post",
-                    "list"
+                    "user",
+                    "invite",
+                    "create"
                 ],
-                "summary": "Get a Post by tag Public",
+                "summary": "Invite a User",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Invite Data",
+                        "name": "invite",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.createInviteRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.Post"
+                            "$ref": "#/definitions/internal_api.inviteResponse"
                         }
                     }
                 }
             }
         },
-        "/tag/{id}": {
-            "get": {
-                "description": "Recive the one tag information from a id",
+        "/invite/accept": {
+            "post": {
+                "description": "Create the account of an invitation with the chosen username and password",
                 "consumes": [
                     "application/json"
                 ],
@@ -742,46 +871,48 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tag",
-                    "get"
+                    "user",
+                    "invite"
                 ],
-                "summary": "Get a Tag",
+                "summary": "Accept Invite",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Invite Token and User Data",
+                        "name": "invite",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.acceptInviteRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.Tag"
+                            "$ref": "#/definitions/internal_api.userResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/invite/{id}": {
             "delete": {
                 "security": [
                     {
                         "JWT": []
                     }
                 ],
-                "description": "Delete the tag register",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Revoke a pending invitation",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tag",
+                    "user",
+                    "invite",
                     "delete"
                 ],
-                "summary": "Delete Tag",
+                "summary": "Revoke Invite",
                 "parameters": [
                     {
                         "type": "string",
@@ -801,38 +932,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/tags": {
+        "/invites": {
             "get": {
-                "description": "Recive all tags",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "JWT": []
+                    }
                 ],
+                "description": "Recive the invitations that were not accepted, revoked nor expired",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tag",
+                    "user",
+                    "invite",
                     "list"
                 ],
-                "summary": "List Tags",
+                "summary": "List Invites",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.Tag"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_api.inviteResponse"
+                            }
                         }
                     }
                 }
             }
         },
-        "/user": {
+        "/login": {
             "post": {
-                "security": [
-                    {
-                        "JWT": []
-                    }
-                ],
-                "description": "Create a new user",
+                "description": "Login a user and return access token a refresh token.\nWhen the user has two-factor authentication enabled it returns a challenge to complete on /login/2fa instead.\nRepeated failures lock the username and the client IP for a growing time, the response is 429 with a Retry-After header.",
                 "consumes": [
                     "application/json"
                 ],
@@ -841,17 +973,17 @@ const docTemplate = `{
                 ],
                 "tags": [
                     "user",
-                    "create"
+                    "login"
                 ],
-                "summary": "Create a new User",
+                "summary": "Login User",
                 "parameters": [
                     {
-                        "description": "User Data",
+                        "description": "User Login",
                         "name": "user",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_api.createUserRequest"
+                            "$ref": "#/definitions/internal_api.loginUserRequest"
                         }
                     }
                 ],
@@ -859,20 +991,118 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api.userResponse"
+                            "$ref": "#/definitions/internal_api.loginUserResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.loginChallengeResponse"
                         }
                     }
                 }
             }
         },
-        "/user/{id}": {
+        "/login/2fa": {
+            "post": {
+                "description": "Complete a login challenge with a TOTP code or a recovery code and return access token a refresh token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user",
+                    "login"
+                ],
+                "summary": "Login Two-Factor",
+                "parameters": [
+                    {
+                        "description": "Challenge and Code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.loginTOTPRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.loginUserResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login/oidc": {
             "get": {
-                "security": [
+                "description": "Return the URL of the identity provider where the user signs in,\nthe provider redirects back to the frontend with the code and the state for /login/oidc/callback.\nIt sets a cookie that binds the login to the browser, the callback has to be sent with it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oidc",
+                    "login"
+                ],
+                "summary": "Begin Single Sign-On Login",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.beginOIDCLoginResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login/oidc/callback": {
+            "post": {
+                "description": "Exchange the code returned by the identity provider and return access token a refresh token.\nIt requires the cookie set by /login/oidc in the same browser.\nThe user is found by the provider account, then by its verified email, otherwise a new user is created\nwhen the domain of the email is in OIDC_SIGNUP_DOMAINS.\nA user with the same email that has not verified it is not linked, the response is 409 until they log in and verify it.\nWhen the user has two-factor authentication enabled it returns a challenge to complete on /login/2fa instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "oidc",
+                    "login"
+                ],
+                "summary": "Finish Single Sign-On Login",
+                "parameters": [
                     {
-                        "JWT": []
+                        "description": "Code and State",
+                        "name": "login",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.finishOIDCLoginRequest"
+                        }
                     }
                 ],
-                "description": "Recive the one user information from a id",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.loginUserResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.loginChallengeResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login/passkey/begin": {
+            "post": {
+                "description": "Start a passkey login, the options are passed to navigator.credentials.get.\nWithout a username any passkey stored in the authenticator can be used.",
                 "consumes": [
                     "application/json"
                 ],
@@ -880,24 +1110,87 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user",
-                    "get"
+                    "passkey",
+                    "login"
                 ],
-                "summary": "Get a User",
+                "summary": "Begin Passkey Login",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Username",
+                        "name": "user",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.beginPasskeyLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.beginPasskeyLoginResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login/passkey/finish": {
+            "post": {
+                "description": "Verify the response of navigator.credentials.get and return access token a refresh token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passkey",
+                    "login"
+                ],
+                "summary": "Finish Passkey Login",
+                "parameters": [
+                    {
+                        "description": "Challenge and Credential",
+                        "name": "passkey",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.finishPasskeyLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.loginUserResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
                     }
                 ],
+                "description": "Recive the information and the public profile of the logged user, with the recovery codes left when two-factor authentication is enabled",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user",
+                    "me",
+                    "get"
+                ],
+                "summary": "Get Me",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.User"
+                            "$ref": "#/definitions/internal_api.meResponse"
                         }
                     }
                 }
@@ -908,7 +1201,7 @@ const docTemplate = `{
                         "JWT": []
                     }
                 ],
-                "description": "Update the user information",
+                "description": "Update the account and the public profile of the logged user, a new email takes effect once it is confirmed from a link sent to it\nThe verification emails of a user are limited to one a minute, a change during the cooldown is rejected with 429.",
                 "consumes": [
                     "application/json"
                 ],
@@ -917,24 +1210,18 @@ const docTemplate = `{
                 ],
                 "tags": [
                     "user",
+                    "me",
                     "update"
                 ],
-                "summary": "Update User",
+                "summary": "Update Me",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "description": "User Data",
                         "name": "user",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_api.updateUserRequestData"
+                            "$ref": "#/definitions/internal_api.updateMeRequest"
                         }
                     }
                 ],
@@ -942,7 +1229,33 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_api.userResponse"
+                            "$ref": "#/definitions/internal_api.meResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/2fa/totp": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Generate a new TOTP secret for the logged user, the provisioning URI can be shown as a QR code.\nTwo-factor authentication is enabled once a code of the secret is verified.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user",
+                    "2fa"
+                ],
+                "summary": "Enroll TOTP",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.enrollTOTPResponse"
                         }
                     }
                 }
@@ -953,7 +1266,7 @@ const docTemplate = `{
                         "JWT": []
                     }
                 ],
-                "description": "Delete the user register",
+                "description": "Disable two-factor authentication of the logged user, it needs the password and a TOTP code or a recovery code",
                 "consumes": [
                     "application/json"
                 ],
@@ -962,16 +1275,18 @@ const docTemplate = `{
                 ],
                 "tags": [
                     "user",
-                    "delete"
+                    "2fa"
                 ],
-                "summary": "Delete User",
+                "summary": "Disable TOTP",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Password and Code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.disableTOTPRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -984,14 +1299,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users": {
-            "get": {
+        "/me/2fa/totp/verify": {
+            "post": {
                 "security": [
                     {
                         "JWT": []
                     }
                 ],
-                "description": "Recive all users",
+                "description": "Verify a code of the pending TOTP secret and enable two-factor authentication.\nIt returns the recovery codes, they are only shown once.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1000,188 +1315,2345 @@ const docTemplate = `{
                 ],
                 "tags": [
                     "user",
-                    "list"
+                    "2fa"
+                ],
+                "summary": "Verify TOTP",
+                "parameters": [
+                    {
+                        "description": "TOTP Code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.verifyTOTPRequest"
+                        }
+                    }
                 ],
-                "summary": "List Users",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.ListUsersRow"
+                            "$ref": "#/definitions/internal_api.verifyTOTPResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.Category": {
-            "type": "object",
-            "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                }
-            }
         },
-        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.ListPostsPrivateRow": {
-            "type": "object",
-            "properties": {
-                "category_id": {
-                    "type": "string"
-                },
-                "category_name": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "subtitle": {
-                    "type": "string"
-                },
-                "tags": {},
-                "title": {
-                    "type": "string"
-                }
-            }
-        },
-        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.ListPostsPublicRow": {
-            "type": "object",
-            "properties": {
-                "category_id": {
-                    "type": "string"
-                },
-                "category_name": {
-                    "type": "string"
+        "/me/avatar": {
+            "put": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Upload the avatar of the logged user, the image are upload to S3 services and replaces the previous one",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author",
+                    "me",
+                    "update"
+                ],
+                "summary": "Upload Avatar",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "This is a image",
+                        "name": "avatar",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.authorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Delete the avatar of the logged user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "author",
+                    "me",
+                    "delete"
+                ],
+                "summary": "Delete Avatar",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.authorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/email/verification": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Send a new verification link to the email of the logged user, the verification emails of a user are limited to one a minute",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user",
+                    "email"
+                ],
+                "summary": "Resend Email Verification",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/passkeys": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Recive the passkeys of the logged user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passkey",
+                    "list"
+                ],
+                "summary": "List Passkeys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_api.passkeyResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/me/passkeys/register/begin": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Start the registration of a passkey for the logged user, the options are passed to navigator.credentials.create",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passkey",
+                    "create"
+                ],
+                "summary": "Begin Passkey Registration",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.beginPasskeyRegistrationResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/passkeys/register/finish": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Verify the response of navigator.credentials.create and store the new passkey",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passkey",
+                    "create"
+                ],
+                "summary": "Finish Passkey Registration",
+                "parameters": [
+                    {
+                        "description": "Challenge and Credential",
+                        "name": "passkey",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.finishPasskeyRegistrationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.passkeyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/passkeys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Delete a passkey of the logged user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "passkey",
+                    "delete"
+                ],
+                "summary": "Delete Passkey",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/password": {
+            "put": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Change the password of the logged user, every session is revoked and a new one is returned.\nWrong current passwords count as failed logins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user",
+                    "me",
+                    "password"
+                ],
+                "summary": "Change Password",
+                "parameters": [
+                    {
+                        "description": "Current and New Password",
+                        "name": "password",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.changePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.loginUserResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/forgot": {
+            "post": {
+                "description": "Send an email with a link to reset the password.\nThe response is the same whether the email belongs to a user or not.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user",
+                    "password"
+                ],
+                "summary": "Forgot Password",
+                "parameters": [
+                    {
+                        "description": "User Email",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.forgotPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/password/reset": {
+            "post": {
+                "description": "Set a new password with a reset token, every session of the user is revoked",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user",
+                    "password"
+                ],
+                "summary": "Reset Password",
+                "parameters": [
+                    {
+                        "description": "Token and New Password",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.resetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/post/{id}": {
+            "get": {
+                "description": "Recive the one post public",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "post",
+                    "get"
+                ],
+                "summary": "Get a Post by Id Public",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.Post"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts": {
+            "get": {
+                "description": "Recive all posts publics",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "post",
+                    "list"
+                ],
+                "summary": "List all Posts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.ListPostsPublicRow"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/by-slug/{slug}": {
+            "get": {
+                "description": "Recive the one post public by its slug, a previous slug of the post redirects to the current one with 302",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "post",
+                    "get"
+                ],
+                "summary": "Get a Post by Slug Public",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.GetPostBySlugPublicRow"
+                        }
+                    }
+                }
+            }
+        },
+        "/session/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Block one session of the logged user and the sessions rotated from it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "session",
+                    "delete"
+                ],
+                "summary": "Revoke Session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Recive the active sessions of the logged user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "session",
+                    "list"
+                ],
+                "summary": "List Sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_api.sessionResponse"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Block every session of the logged user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "session",
+                    "delete"
+                ],
+                "summary": "Logout Everywhere",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tag": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Create a new Tag, the image are upload to S3 services.\nWithout a slug it is made from the name.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag",
+                    "create"
+                ],
+                "summary": "Create a new Tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "This is the tag name",
+                        "name": "name",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "This is the tag slug",
+                        "name": "slug",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "This is the tag description",
+                        "name": "description",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "This is a image",
+                        "name": "logo",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.Tag"
+                        }
+                    }
+                }
+            }
+        },
+        "/tag-post/{id}": {
+            "get": {
+                "description": "Recive the one post by Tag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "post",
+                    "list"
+                ],
+                "summary": "Get a Post by tag Public",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.Post"
+                        }
+                    }
+                }
+            }
+        },
+        "/tag/{id}": {
+            "get": {
+                "description": "Recive the one tag information from a id or a slug",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag",
+                    "get"
+                ],
+                "summary": "Get a Tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id or slug",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.LookupTagRow"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Update the tag information, the slug only changes when it is sent",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag",
+                    "update"
+                ],
+                "summary": "Update Tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tag Data",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.updateTagRequestData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.Tag"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Delete the tag register",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag",
+                    "delete"
+                ],
+                "summary": "Delete Tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "description": "Recive all tags",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tag",
+                    "list"
+                ],
+                "summary": "List Tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.Tag"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/personal": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Recive the personal access tokens of the logged user that are not revoked",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "token",
+                    "list"
+                ],
+                "summary": "List Personal Access Tokens",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_api.personalAccessTokenResponse"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Create a long-lived token for automation, the token is only returned once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "token",
+                    "create"
+                ],
+                "summary": "Create a Personal Access Token",
+                "parameters": [
+                    {
+                        "description": "Token Data",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.createPersonalAccessTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.createPersonalAccessTokenResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/personal/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Revoke a personal access token of the logged user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "token",
+                    "delete"
+                ],
+                "summary": "Revoke Personal Access Token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/renew_access": {
+            "post": {
+                "description": "Verify a refresh token against its session and return a new access token.\nThe refresh token is rotated: the old one can not be used again and reusing it blocks every session of its chain.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "token",
+                    "login"
+                ],
+                "summary": "Renew Access Token",
+                "parameters": [
+                    {
+                        "description": "Refresh Token",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.renewAccessTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.renewAccessTokenResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Recive the one user information from a id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user",
+                    "get"
+                ],
+                "summary": "Get a User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.userResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Update the user information, a new email takes effect once it is confirmed from a link sent to it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user",
+                    "update"
+                ],
+                "summary": "Update User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User Data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.updateUserRequestData"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_api.userResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Deactivate the user: it can not login anymore, its sessions and personal access tokens are revoked.\nIts posts are transferred to the transfer_to user or left without author. Use the purge endpoint to delete the register.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user",
+                    "delete"
+                ],
+                "summary": "Delete User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "username that receives the posts",
+                        "name": "transfer_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}/enable": {
+            "post": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Reactivate a deleted user, the posts that were transferred are not given back",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user",
+                    "update"
+                ],
+                "summary": "Enable User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}/purge": {
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Delete the register of a deleted user and all its data, it can not be undone",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user",
+                    "delete"
+                ],
+                "summary": "Purge User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{id}/sessions": {
+            "delete": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Block every session of any user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "session",
+                    "user",
+                    "delete"
+                ],
+                "summary": "Block User Sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "Recive all users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user",
+                    "list"
+                ],
+                "summary": "List Users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.ListUsersRow"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.Category": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.GetPostBySlugPublicRow": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/pgtype.Text"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "co_authors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "content": {
+                    "type": "string"
+                },
+                "content_format": {
+                    "type": "string"
+                },
+                "content_html": {
+                    "type": "string"
+                },
+                "content_toc": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "publicated": {
+                    "type": "boolean"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "subtitle": {
+                    "type": "string"
+                },
+                "tags": {},
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.GetPostsByAuthorPublicRow": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/pgtype.Text"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "co_authors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "subtitle": {
+                    "type": "string"
+                },
+                "tags": {},
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.ListPostRenderWarningsRow": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "render_warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.ListPostsPrivateRow": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/pgtype.Text"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "co_authors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_edited_by": {
+                    "$ref": "#/definitions/pgtype.Text"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "subtitle": {
+                    "type": "string"
+                },
+                "tags": {},
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.ListPostsPublicRow": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/pgtype.Text"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "co_authors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "subtitle": {
+                    "type": "string"
+                },
+                "tags": {},
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.ListUsersRow": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "disabled_at": {
+                    "$ref": "#/definitions/pgtype.Timestamptz"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "email_verified_at": {
+                    "$ref": "#/definitions/pgtype.Timestamptz"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.LookupCategoryRow": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.LookupTagRow": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.Post": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "content_format": {
+                    "type": "string"
+                },
+                "content_html": {
+                    "type": "string"
+                },
+                "content_toc": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_edited_by": {
+                    "type": "string"
+                },
+                "publicated": {
+                    "type": "boolean"
+                },
+                "render_version": {
+                    "type": "integer"
+                },
+                "render_warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "subtitle": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.PostsTag": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "post_id": {
+                    "type": "string"
+                },
+                "tag_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.Tag": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_JairoRiver_personal_blog_backend_pkg_render.Heading": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_JairoRiver_personal_blog_backend_pkg_render.Warning": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "shortcode": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.acceptInviteRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "token",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.authorResponse": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "bio": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "social_links": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "username": {
+                    "type": "string"
+                },
+                "website": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.beginOIDCLoginResponse": {
+            "type": "object",
+            "properties": {
+                "authorization_url": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.beginPasskeyLoginRequest": {
+            "type": "object",
+            "properties": {
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.beginPasskeyLoginResponse": {
+            "type": "object",
+            "properties": {
+                "challenge_id": {
+                    "type": "string"
+                },
+                "options": {
+                    "$ref": "#/definitions/protocol.CredentialAssertion"
+                }
+            }
+        },
+        "internal_api.beginPasskeyRegistrationResponse": {
+            "type": "object",
+            "properties": {
+                "challenge_id": {
+                    "type": "string"
+                },
+                "options": {
+                    "$ref": "#/definitions/protocol.CredentialCreation"
+                }
+            }
+        },
+        "internal_api.changePasswordRequest": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.createCategoryRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.createInviteRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "expires_in_days": {
+                    "type": "integer",
+                    "maximum": 30,
+                    "minimum": 1
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "editor",
+                        "author"
+                    ]
+                }
+            }
+        },
+        "internal_api.createPersonalAccessTokenRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_in_days": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "internal_api.createPersonalAccessTokenResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.createPostRequest": {
+            "type": "object",
+            "required": [
+                "category_id",
+                "content",
+                "subtitle",
+                "title"
+            ],
+            "properties": {
+                "category_id": {
+                    "type": "string"
+                },
+                "co_authors": {
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "content": {
+                    "type": "string"
+                },
+                "content_format": {
+                    "description": "ContentFormat is the format of the content, markdown by default",
+                    "type": "string",
+                    "enum": [
+                        "markdown",
+                        "html"
+                    ]
+                },
+                "publicated": {
+                    "type": "boolean"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "subtitle": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.createPostTag": {
+            "type": "object",
+            "required": [
+                "post_id",
+                "tag_id"
+            ],
+            "properties": {
+                "post_id": {
+                    "type": "string"
+                },
+                "tag_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.disableTOTPRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "password"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.enrollTOTPResponse": {
+            "type": "object",
+            "properties": {
+                "provisioning_uri": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.finishOIDCLoginRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "state"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.finishPasskeyLoginRequest": {
+            "type": "object",
+            "required": [
+                "challenge_id",
+                "credential"
+            ],
+            "properties": {
+                "challenge_id": {
+                    "type": "string"
+                },
+                "credential": {
+                    "type": "object"
+                }
+            }
+        },
+        "internal_api.finishPasskeyRegistrationRequest": {
+            "type": "object",
+            "required": [
+                "challenge_id",
+                "credential",
+                "name"
+            ],
+            "properties": {
+                "challenge_id": {
+                    "type": "string"
                 },
+                "credential": {
+                    "type": "object"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "internal_api.forgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.inviteResponse": {
+            "type": "object",
+            "properties": {
                 "created_at": {
                     "type": "string"
                 },
+                "email": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
-                "subtitle": {
+                "invited_by": {
                     "type": "string"
                 },
-                "tags": {},
-                "title": {
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.listHighlightThemesResponse": {
+            "type": "object",
+            "properties": {
+                "default_theme": {
+                    "type": "string"
+                },
+                "themes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "internal_api.loginChallengeResponse": {
+            "type": "object",
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "mfa_required": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "internal_api.loginTOTPRequest": {
+            "type": "object",
+            "required": [
+                "challenge_token",
+                "code"
+            ],
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.loginUserRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.loginUserResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "access_token_expires_at": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "refresh_token_expires_at": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.meResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "disabled": {
+                    "type": "boolean"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "emailVerified": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "profile": {
+                    "$ref": "#/definitions/internal_api.authorResponse"
+                },
+                "recovery_codes_left": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "two_factor_enabled": {
+                    "type": "boolean"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.passkeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.personalAccessTokenResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "internal_api.previewPostRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "content_format": {
+                    "type": "string",
+                    "enum": [
+                        "markdown",
+                        "html"
+                    ]
+                }
+            }
+        },
+        "internal_api.previewPostResponse": {
+            "type": "object",
+            "properties": {
+                "content_html": {
+                    "type": "string"
+                },
+                "content_toc": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_pkg_render.Heading"
+                    }
+                },
+                "render_warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_JairoRiver_personal_blog_backend_pkg_render.Warning"
+                    }
+                }
+            }
+        },
+        "internal_api.renewAccessTokenRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.renewAccessTokenResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "access_token_expires_at": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "refresh_token_expires_at": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.resetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.sessionResponse": {
+            "type": "object",
+            "properties": {
+                "client_ip": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_api.updateCategoryRequestData": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "slug": {
                     "type": "string"
                 }
             }
         },
-        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.ListUsersRow": {
+        "internal_api.updateMeRequest": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "bio": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "display_name": {
+                    "type": "string",
+                    "maxLength": 64
                 },
                 "email": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
+                "social_links": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
-                "username": {
+                "website": {
                     "type": "string"
                 }
             }
         },
-        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.Post": {
+        "internal_api.updatePostRequest": {
             "type": "object",
             "properties": {
                 "category_id": {
                     "type": "string"
                 },
-                "content": {
-                    "type": "string"
+                "co_authors": {
+                    "description": "CoAuthors replaces the co-authors when it is sent, an empty list removes them",
+                    "type": "array",
+                    "maxItems": 10,
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "created_at": {
-                    "type": "string"
+                "content": {
+                    "$ref": "#/definitions/pgtype.Text"
                 },
-                "id": {
-                    "type": "string"
+                "content_format": {
+                    "$ref": "#/definitions/pgtype.Text"
                 },
                 "publicated": {
-                    "type": "boolean"
+                    "$ref": "#/definitions/pgtype.Bool"
+                },
+                "slug": {
+                    "$ref": "#/definitions/pgtype.Text"
                 },
                 "subtitle": {
-                    "type": "string"
+                    "$ref": "#/definitions/pgtype.Text"
                 },
                 "title": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
+                    "$ref": "#/definitions/pgtype.Text"
                 }
             }
         },
-        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.PostsTag": {
+        "internal_api.updateTagRequestData": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "post_id": {
-                    "type": "string"
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
                 },
-                "tag_id": {
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "maxLength": 64
                 },
-                "updated_at": {
+                "slug": {
                     "type": "string"
                 }
             }
         },
-        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.Tag": {
+        "internal_api.updateUserRequestData": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
+                "email": {
                     "type": "string"
                 },
-                "image_url": {
+                "password": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string"
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "editor",
+                        "author"
+                    ]
                 },
-                "updated_at": {
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "github_com_JairoRiver_personal_blog_backend_internal_db_sqlc.User": {
+        "internal_api.userResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "createdAt": {
                     "type": "string"
                 },
+                "disabled": {
+                    "type": "boolean"
+                },
                 "email": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
+                "emailVerified": {
+                    "type": "boolean"
                 },
-                "password": {
+                "id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "role": {
                     "type": "string"
                 },
                 "username": {
@@ -1189,199 +3661,397 @@ const docTemplate = `{
                 }
             }
         },
-        "internal_api.createCategoryRequest": {
+        "internal_api.verifyEmailRequest": {
             "type": "object",
             "required": [
-                "name"
+                "token"
             ],
             "properties": {
-                "name": {
+                "token": {
                     "type": "string"
                 }
             }
         },
-        "internal_api.createPostRequest": {
+        "internal_api.verifyTOTPRequest": {
             "type": "object",
             "required": [
-                "category_id",
-                "content",
-                "subtitle",
-                "title"
+                "code"
             ],
             "properties": {
-                "category_id": {
-                    "type": "string"
-                },
-                "content": {
+                "code": {
                     "type": "string"
-                },
-                "publicated": {
+                }
+            }
+        },
+        "internal_api.verifyTOTPResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "pgtype.Bool": {
+            "type": "object",
+            "properties": {
+                "bool": {
                     "type": "boolean"
                 },
-                "subtitle": {
-                    "type": "string"
-                },
-                "title": {
-                    "type": "string"
+                "valid": {
+                    "type": "boolean"
                 }
             }
         },
-        "internal_api.createPostTag": {
-            "type": "object",
-            "required": [
-                "post_id",
-                "tag_id"
+        "pgtype.InfinityModifier": {
+            "type": "integer",
+            "enum": [
+                1,
+                0,
+                -1
             ],
+            "x-enum-varnames": [
+                "Infinity",
+                "Finite",
+                "NegativeInfinity"
+            ]
+        },
+        "pgtype.Text": {
+            "type": "object",
             "properties": {
-                "post_id": {
+                "string": {
                     "type": "string"
                 },
-                "tag_id": {
-                    "type": "string"
+                "valid": {
+                    "type": "boolean"
                 }
             }
         },
-        "internal_api.createUserRequest": {
+        "pgtype.Timestamptz": {
             "type": "object",
-            "required": [
-                "email",
-                "password",
-                "username"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "infinityModifier": {
+                    "$ref": "#/definitions/pgtype.InfinityModifier"
                 },
-                "password": {
+                "time": {
                     "type": "string"
                 },
-                "username": {
-                    "type": "string"
+                "valid": {
+                    "type": "boolean"
                 }
             }
         },
-        "internal_api.loginUserRequest": {
+        "protocol.AuthenticationExtensions": {
             "type": "object",
-            "required": [
-                "password",
-                "username"
+            "additionalProperties": true
+        },
+        "protocol.AuthenticatorAttachment": {
+            "type": "string",
+            "enum": [
+                "platform",
+                "cross-platform"
             ],
+            "x-enum-varnames": [
+                "Platform",
+                "CrossPlatform"
+            ]
+        },
+        "protocol.AuthenticatorSelection": {
+            "type": "object",
             "properties": {
-                "password": {
-                    "type": "string",
-                    "minLength": 6
+                "authenticatorAttachment": {
+                    "description": "AuthenticatorAttachment If this member is present, eligible authenticators are filtered to only\nauthenticators attached with the specified AuthenticatorAttachment enum.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/protocol.AuthenticatorAttachment"
+                        }
+                    ]
                 },
-                "username": {
-                    "type": "string"
+                "requireResidentKey": {
+                    "description": "RequireResidentKey this member describes the Relying Party's requirements regarding resident\ncredentials. If the parameter is set to true, the authenticator MUST create a client-side-resident\npublic key credential source when creating a public key credential.",
+                    "type": "boolean"
+                },
+                "residentKey": {
+                    "description": "ResidentKey this member describes the Relying Party's requirements regarding resident\ncredentials per Webauthn Level 2.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/protocol.ResidentKeyRequirement"
+                        }
+                    ]
+                },
+                "userVerification": {
+                    "description": "UserVerification This member describes the Relying Party's requirements regarding user verification for\nthe create() operation. Eligible authenticators are filtered to only those capable of satisfying this\nrequirement.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/protocol.UserVerificationRequirement"
+                        }
+                    ]
                 }
             }
         },
-        "internal_api.loginUserResponse": {
+        "protocol.AuthenticatorTransport": {
+            "type": "string",
+            "enum": [
+                "usb",
+                "nfc",
+                "ble",
+                "hybrid",
+                "internal"
+            ],
+            "x-enum-varnames": [
+                "USB",
+                "NFC",
+                "BLE",
+                "Hybrid",
+                "Internal"
+            ]
+        },
+        "protocol.ConveyancePreference": {
+            "type": "string",
+            "enum": [
+                "none",
+                "indirect",
+                "direct",
+                "enterprise"
+            ],
+            "x-enum-varnames": [
+                "PreferNoAttestation",
+                "PreferIndirectAttestation",
+                "PreferDirectAttestation",
+                "PreferEnterpriseAttestation"
+            ]
+        },
+        "protocol.CredentialAssertion": {
             "type": "object",
             "properties": {
-                "access_token": {
-                    "type": "string"
-                },
-                "access_token_expires_at": {
-                    "type": "string"
-                },
-                "refresh_token": {
-                    "type": "string"
-                },
-                "refresh_token_expires_at": {
-                    "type": "string"
-                },
-                "session_id": {
-                    "type": "string"
+                "publicKey": {
+                    "$ref": "#/definitions/protocol.PublicKeyCredentialRequestOptions"
+                }
+            }
+        },
+        "protocol.CredentialCreation": {
+            "type": "object",
+            "properties": {
+                "publicKey": {
+                    "$ref": "#/definitions/protocol.PublicKeyCredentialCreationOptions"
+                }
+            }
+        },
+        "protocol.CredentialDescriptor": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "CredentialID The ID of a credential to allow/disallow.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "user": {
-                    "type": "string"
+                "transports": {
+                    "description": "The authenticator transports that can be used.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/protocol.AuthenticatorTransport"
+                    }
                 },
-                "user_id": {
-                    "type": "string"
+                "type": {
+                    "description": "The valid credential types.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/protocol.CredentialType"
+                        }
+                    ]
                 }
             }
         },
-        "internal_api.updateCategoryRequestData": {
+        "protocol.CredentialParameter": {
             "type": "object",
             "properties": {
-                "name": {
-                    "type": "string"
+                "alg": {
+                    "$ref": "#/definitions/webauthncose.COSEAlgorithmIdentifier"
+                },
+                "type": {
+                    "$ref": "#/definitions/protocol.CredentialType"
                 }
             }
         },
-        "internal_api.updatePostRequest": {
+        "protocol.CredentialType": {
+            "type": "string",
+            "enum": [
+                "public-key"
+            ],
+            "x-enum-varnames": [
+                "PublicKeyCredentialType"
+            ]
+        },
+        "protocol.PublicKeyCredentialCreationOptions": {
             "type": "object",
             "properties": {
-                "category_id": {
-                    "type": "string"
+                "attestation": {
+                    "$ref": "#/definitions/protocol.ConveyancePreference"
                 },
-                "content": {
-                    "$ref": "#/definitions/pgtype.Text"
+                "authenticatorSelection": {
+                    "$ref": "#/definitions/protocol.AuthenticatorSelection"
                 },
-                "publicated": {
-                    "$ref": "#/definitions/pgtype.Bool"
+                "challenge": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "subtitle": {
-                    "$ref": "#/definitions/pgtype.Text"
+                "excludeCredentials": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/protocol.CredentialDescriptor"
+                    }
                 },
-                "title": {
-                    "$ref": "#/definitions/pgtype.Text"
+                "extensions": {
+                    "$ref": "#/definitions/protocol.AuthenticationExtensions"
+                },
+                "pubKeyCredParams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/protocol.CredentialParameter"
+                    }
+                },
+                "rp": {
+                    "$ref": "#/definitions/protocol.RelyingPartyEntity"
+                },
+                "timeout": {
+                    "type": "integer"
+                },
+                "user": {
+                    "$ref": "#/definitions/protocol.UserEntity"
                 }
             }
         },
-        "internal_api.updateUserRequestData": {
+        "protocol.PublicKeyCredentialRequestOptions": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
+                "allowCredentials": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/protocol.CredentialDescriptor"
+                    }
                 },
-                "password": {
-                    "type": "string"
+                "challenge": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 },
-                "username": {
+                "extensions": {
+                    "$ref": "#/definitions/protocol.AuthenticationExtensions"
+                },
+                "rpId": {
                     "type": "string"
+                },
+                "timeout": {
+                    "type": "integer"
+                },
+                "userVerification": {
+                    "$ref": "#/definitions/protocol.UserVerificationRequirement"
                 }
             }
         },
-        "internal_api.userResponse": {
+        "protocol.RelyingPartyEntity": {
             "type": "object",
             "properties": {
-                "createdAt": {
-                    "type": "string"
-                },
-                "email": {
+                "icon": {
+                    "description": "A serialized URL which resolves to an image associated with the entity. For example,\nthis could be a user’s avatar or a Relying Party's logo. This URL MUST be an a priori\nauthenticated URL. Authenticators MUST accept and store a 128-byte minimum length for\nan icon member’s value. Authenticators MAY ignore an icon member’s value if its length\nis greater than 128 bytes. The URL’s scheme MAY be \"data\" to avoid fetches of the URL,\nat the cost of needing more storage.\n\nDeprecated: this has been removed from the specification recommendations.",
                     "type": "string"
                 },
                 "id": {
+                    "description": "A unique identifier for the Relying Party entity, which sets the RP ID.",
                     "type": "string"
                 },
-                "username": {
+                "name": {
+                    "description": "A human-palatable name for the entity. Its function depends on what the PublicKeyCredentialEntity represents:\n\nWhen inherited by PublicKeyCredentialRpEntity it is a human-palatable identifier for the Relying Party,\nintended only for display. For example, \"ACME Corporation\", \"Wonderful Widgets, Inc.\" or \"ОАО Примертех\".\n\nWhen inherited by PublicKeyCredentialUserEntity, it is a human-palatable identifier for a user account. It is\nintended only for display, i.e., aiding the user in determining the difference between user accounts with similar\ndisplayNames. For example, \"alexm\", \"alex.p.mueller@example.com\" or \"+14255551234\".",
                     "type": "string"
                 }
             }
         },
-        "pgtype.Bool": {
-            "type": "object",
-            "properties": {
-                "bool": {
-                    "type": "boolean"
-                },
-                "valid": {
-                    "type": "boolean"
-                }
-            }
+        "protocol.ResidentKeyRequirement": {
+            "type": "string",
+            "enum": [
+                "discouraged",
+                "preferred",
+                "required"
+            ],
+            "x-enum-varnames": [
+                "ResidentKeyRequirementDiscouraged",
+                "ResidentKeyRequirementPreferred",
+                "ResidentKeyRequirementRequired"
+            ]
         },
-        "pgtype.Text": {
+        "protocol.UserEntity": {
             "type": "object",
             "properties": {
-                "string": {
+                "displayName": {
+                    "description": "A human-palatable name for the user account, intended only for display.\nFor example, \"Alex P. Müller\" or \"田中 倫\". The Relying Party SHOULD let\nthe user choose this, and SHOULD NOT restrict the choice more than necessary.",
                     "type": "string"
                 },
-                "valid": {
-                    "type": "boolean"
+                "icon": {
+                    "description": "A serialized URL which resolves to an image associated with the entity. For example,\nthis could be a user’s avatar or a Relying Party's logo. This URL MUST be an a priori\nauthenticated URL. Authenticators MUST accept and store a 128-byte minimum length for\nan icon member’s value. Authenticators MAY ignore an icon member’s value if its length\nis greater than 128 bytes. The URL’s scheme MAY be \"data\" to avoid fetches of the URL,\nat the cost of needing more storage.\n\nDeprecated: this has been removed from the specification recommendations.",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is the user handle of the user account entity. To ensure secure operation,\nauthentication and authorization decisions MUST be made on the basis of this id\nmember, not the displayName nor name members. See Section 6.1 of\n[RFC8266](https://www.w3.org/TR/webauthn/#biblio-rfc8266)."
+                },
+                "name": {
+                    "description": "A human-palatable name for the entity. Its function depends on what the PublicKeyCredentialEntity represents:\n\nWhen inherited by PublicKeyCredentialRpEntity it is a human-palatable identifier for the Relying Party,\nintended only for display. For example, \"ACME Corporation\", \"Wonderful Widgets, Inc.\" or \"ОАО Примертех\".\n\nWhen inherited by PublicKeyCredentialUserEntity, it is a human-palatable identifier for a user account. It is\nintended only for display, i.e., aiding the user in determining the difference between user accounts with similar\ndisplayNames. For example, \"alexm\", \"alex.p.mueller@example.com\" or \"+14255551234\".",
+                    "type": "string"
                 }
             }
+        },
+        "protocol.UserVerificationRequirement": {
+            "type": "string",
+            "enum": [
+                "required",
+                "preferred",
+                "discouraged"
+            ],
+            "x-enum-comments": {
+                "VerificationPreferred": "This is the default"
+            },
+            "x-enum-varnames": [
+                "VerificationRequired",
+                "VerificationPreferred",
+                "VerificationDiscouraged"
+            ]
+        },
+        "webauthncose.COSEAlgorithmIdentifier": {
+            "type": "integer",
+            "enum": [
+                -7,
+                -35,
+                -36,
+                -65535,
+                -257,
+                -258,
+                -259,
+                -37,
+                -38,
+                -39,
+                -8,
+                -47
+            ],
+            "x-enum-varnames": [
+                "AlgES256",
+                "AlgES384",
+                "AlgES512",
+                "AlgRS1",
+                "AlgRS256",
+                "AlgRS384",
+                "AlgRS512",
+                "AlgPS256",
+                "AlgPS384",
+                "AlgPS512",
+                "AlgEdDSA",
+                "AlgES256K"
+            ]
         }
     },
     "securityDefinitions": {
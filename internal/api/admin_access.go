@@ -0,0 +1,102 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+	"time"
+)
+
+// parseAllowedCIDRs parses the CIDR ranges allowed to use the authenticated routes
+func parseAllowedCIDRs(cidrs []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if len(cidr) == 0 {
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid admin allowed CIDR %s: %w", cidr, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// parseTrustedProxies parses the IPs and CIDR ranges of the proxies whose forwarded headers
+// are trusted for the client IP, the allowlist and the login throttle depend on it
+func parseTrustedProxies(proxies []string) ([]string, error) {
+	trusted := make([]string, 0, len(proxies))
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if len(proxy) == 0 {
+			continue
+		}
+
+		if !strings.Contains(proxy, "/") {
+			addr, err := netip.ParseAddr(proxy)
+			if err != nil || len(addr.Zone()) > 0 {
+				return nil, fmt.Errorf("invalid trusted proxy %s", proxy)
+			}
+			trusted = append(trusted, addr.String())
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %s: %w", proxy, err)
+		}
+		trusted = append(trusted, prefix.Masked().String())
+	}
+	return trusted, nil
+}
+
+// newAdminTLSConfig creates the TLS config of the admin listener,
+// it only accepts clients with a certificate signed by the client CA
+func newAdminTLSConfig(certFile string, keyFile string, clientCAFile string) (*tls.Config, error) {
+	if len(certFile) == 0 || len(keyFile) == 0 || len(clientCAFile) == 0 {
+		return nil, errors.New("the admin listener requires a certificate, a key and a client CA")
+	}
+
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load admin certificate: %w", err)
+	}
+
+	clientCA, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read admin client CA: %w", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(clientCA) {
+		return nil, errors.New("the admin client CA file has no PEM certificates")
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// startAdmin serves the admin routes with mutual TLS
+func (server *Server) startAdmin(address string) error {
+	adminServer := &http.Server{
+		Addr:              address,
+		Handler:           server.adminRouter,
+		TLSConfig:         server.adminTLS,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// The certificate is already in the TLS config
+	return adminServer.ListenAndServeTLS("", "")
+}
@@ -3,10 +3,16 @@ package api
 import (
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"net/netip"
+	"slices"
 	"strings"
+	"time"
 
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
 	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
 	"github.com/gin-gonic/gin"
 )
 
@@ -14,10 +20,18 @@ const (
 	authorizationHeaderKey  = "authorization"
 	authorizationTypeBearer = "bearer"
 	authorizationPayloadKey = "authorization_payload"
+	authorizationScopesKey  = "authorization_scopes"
 )
 
-// authMiddleware creates a gin middleware for authorization
-func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+var (
+	errNotAccessToken     = errors.New("the token is not an access token")
+	errIPNotAllowed       = errors.New("the client IP is not allowed on this route")
+	errClientCertRequired = errors.New("a verified client certificate is required on this route")
+)
+
+// authMiddleware creates a gin middleware for authorization,
+// it accepts PASETO access tokens and personal access tokens of users that are not disabled
+func authMiddleware(tokenMaker token.Maker, store db.Store) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 
@@ -42,13 +56,159 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		}
 
 		accessToken := fields[1]
+		if token.IsPersonalAccessToken(accessToken) {
+			payload, scopes, err := verifyPersonalAccessToken(ctx, store, accessToken)
+			if err != nil {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+				return
+			}
+
+			ctx.Set(authorizationPayloadKey, payload)
+			ctx.Set(authorizationScopesKey, scopes)
+			ctx.Next()
+			return
+		}
+
 		payload, err := tokenMaker.VerifyToken(accessToken)
 		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
 			return
 		}
 
+		// Refresh tokens are only accepted by /tokens/renew_access, where their session is checked
+		if payload.Type != token.AccessToken {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(errNotAccessToken))
+			return
+		}
+
+		// The access tokens of a deactivated user stop working right away instead of at their expiration
+		user, err := store.GetUserByUsername(ctx, payload.Username)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(token.ErrInvalidToken))
+				return
+			}
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if user.DisabledAt.Valid {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(errUserDisabled))
+			return
+		}
+
 		ctx.Set(authorizationPayloadKey, payload)
 		ctx.Next()
 	}
 }
+
+// verifyPersonalAccessToken looks up a personal access token and returns the payload of its owner and its scopes
+func verifyPersonalAccessToken(ctx *gin.Context, store db.Store, accessToken string) (*token.Payload, []string, error) {
+	pat, err := store.GetPersonalAccessTokenByHash(ctx, token.HashOpaqueToken(accessToken))
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, nil, token.ErrInvalidToken
+		}
+		return nil, nil, err
+	}
+
+	if pat.RevokedAt.Valid || pat.DisabledAt.Valid {
+		return nil, nil, token.ErrInvalidToken
+	}
+
+	if pat.ExpiresAt.Valid && time.Now().After(pat.ExpiresAt.Time) {
+		return nil, nil, token.ErrExpiredToken
+	}
+
+	err = store.TouchPersonalAccessToken(ctx, pat.ID)
+	if err != nil {
+		log.Println("cannot update personal access token last use:", err)
+	}
+
+	payload := &token.Payload{
+		ID:        pat.ID,
+		Username:  pat.Username,
+		Role:      pat.Role,
+		Type:      token.AccessToken,
+		IssuedAt:  pat.CreatedAt,
+		ExpiredAt: pat.ExpiresAt.Time,
+	}
+	return payload, pat.Scopes, nil
+}
+
+// roleMiddleware creates a gin middleware that only lets through users with at least the required role
+func roleMiddleware(requiredRole string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+		if !util.HasRole(payload.Role, requiredRole) {
+			err := fmt.Errorf("the %s role is required", requiredRole)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// scopeMiddleware creates a gin middleware that only lets through personal access tokens with the required scope,
+// login sessions are only limited by the user role
+func scopeMiddleware(requiredScope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		scopes, ok := ctx.Get(authorizationScopesKey)
+		if ok && !slices.Contains(scopes.([]string), requiredScope) {
+			err := fmt.Errorf("the %s scope is required", requiredScope)
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// sessionMiddleware creates a gin middleware that rejects personal access tokens,
+// it protects the routes that manage the credentials of the user
+func sessionMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if _, ok := ctx.Get(authorizationScopesKey); ok {
+			err := errors.New("personal access tokens can not be used on this route")
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// ipAllowlistMiddleware creates a gin middleware that only lets through clients whose IP
+// is in one of the allowed ranges, without ranges every client is allowed
+func ipAllowlistMiddleware(allowed []netip.Prefix) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if len(allowed) == 0 {
+			ctx.Next()
+			return
+		}
+
+		clientIP, err := netip.ParseAddr(ctx.ClientIP())
+		if err != nil || !slices.ContainsFunc(allowed, func(prefix netip.Prefix) bool {
+			return prefix.Contains(clientIP.Unmap())
+		}) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(errIPNotAllowed))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// clientCertMiddleware creates a gin middleware that only lets through requests with a verified
+// client certificate, it guards the admin listener in case it is served without mutual TLS
+func clientCertMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.Request.TLS == nil || len(ctx.Request.TLS.VerifiedChains) == 0 {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(errClientCertRequired))
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,286 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func okHandler(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{})
+}
+
+// addPersonalAccessToken creates a personal access token of the user in the store and sets it as authorization
+func addPersonalAccessToken(t *testing.T, request *http.Request, store *fakeStore, user db.User, pat db.GetPersonalAccessTokenByHashRow) {
+	accessToken, tokenHash, err := token.NewOpaqueToken(token.PersonalAccessTokenPrefix)
+	require.NoError(t, err)
+
+	pat.ID = uuid.New()
+	pat.UserID = user.ID
+	pat.Username = user.Username
+	pat.Role = user.Role
+	pat.DisabledAt = user.DisabledAt
+	store.putPersonalAccessToken(tokenHash, pat)
+
+	request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+accessToken)
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	store := newFakeStore()
+	server := newTestServer(t, store)
+
+	author := randomUser(t, server, store, util.AuthorRole, util.RandomString(12))
+	editor := randomUser(t, server, store, util.EditorRole, util.RandomString(12))
+	admin := randomUser(t, server, store, util.AdminRole, util.RandomString(12))
+	disabled := randomUser(t, server, store, util.AdminRole, util.RandomString(12))
+	disabled.DisabledAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	store.putUser(disabled)
+	deleted := db.User{Username: util.RandomString(8), Role: util.AdminRole}
+
+	testCases := []struct {
+		name         string
+		setupAuth    func(t *testing.T, request *http.Request)
+		expectedCode int
+	}{
+		{
+			name:         "NoAuthorization",
+			setupAuth:    func(t *testing.T, request *http.Request) {},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "UnsupportedAuthorization",
+			setupAuth: func(t *testing.T, request *http.Request) {
+				request.Header.Set(authorizationHeaderKey, "basic dXNlcjpwYXNz")
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "InvalidAuthorizationFormat",
+			setupAuth: func(t *testing.T, request *http.Request) {
+				request.Header.Set(authorizationHeaderKey, authorizationTypeBearer)
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "InvalidToken",
+			setupAuth: func(t *testing.T, request *http.Request) {
+				request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+util.RandomString(40))
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "RefreshToken",
+			setupAuth: func(t *testing.T, request *http.Request) {
+				addAuthorization(t, request, server.tokenMaker, editor, token.RefreshToken)
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "DisabledUser",
+			setupAuth: func(t *testing.T, request *http.Request) {
+				addAuthorization(t, request, server.tokenMaker, disabled, token.AccessToken)
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "DeletedUser",
+			setupAuth: func(t *testing.T, request *http.Request) {
+				addAuthorization(t, request, server.tokenMaker, deleted, token.AccessToken)
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "MissingRole",
+			setupAuth: func(t *testing.T, request *http.Request) {
+				addAuthorization(t, request, server.tokenMaker, author, token.AccessToken)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "RequiredRole",
+			setupAuth: func(t *testing.T, request *http.Request) {
+				addAuthorization(t, request, server.tokenMaker, editor, token.AccessToken)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "HigherRole",
+			setupAuth: func(t *testing.T, request *http.Request) {
+				addAuthorization(t, request, server.tokenMaker, admin, token.AccessToken)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "PersonalAccessTokenWithScope",
+			setupAuth: func(t *testing.T, request *http.Request) {
+				addPersonalAccessToken(t, request, store, editor, db.GetPersonalAccessTokenByHashRow{Scopes: []string{util.PostsWriteScope}})
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "PersonalAccessTokenWithoutScope",
+			setupAuth: func(t *testing.T, request *http.Request) {
+				addPersonalAccessToken(t, request, store, editor, db.GetPersonalAccessTokenByHashRow{Scopes: []string{util.PostsReadScope}})
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "PersonalAccessTokenMissingRole",
+			setupAuth: func(t *testing.T, request *http.Request) {
+				addPersonalAccessToken(t, request, store, author, db.GetPersonalAccessTokenByHashRow{Scopes: []string{util.PostsWriteScope}})
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "RevokedPersonalAccessToken",
+			setupAuth: func(t *testing.T, request *http.Request) {
+				addPersonalAccessToken(t, request, store, editor, db.GetPersonalAccessTokenByHashRow{
+					Scopes:    []string{util.PostsWriteScope},
+					RevokedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+				})
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "ExpiredPersonalAccessToken",
+			setupAuth: func(t *testing.T, request *http.Request) {
+				addPersonalAccessToken(t, request, store, editor, db.GetPersonalAccessTokenByHashRow{
+					Scopes:    []string{util.PostsWriteScope},
+					ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true},
+				})
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "PersonalAccessTokenOfDisabledUser",
+			setupAuth: func(t *testing.T, request *http.Request) {
+				addPersonalAccessToken(t, request, store, disabled, db.GetPersonalAccessTokenByHashRow{Scopes: []string{util.PostsWriteScope}})
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "UnknownPersonalAccessToken",
+			setupAuth: func(t *testing.T, request *http.Request) {
+				request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+token.PersonalAccessTokenPrefix+util.RandomString(40))
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	router := gin.New()
+	router.GET("/auth", authMiddleware(server.tokenMaker, store), roleMiddleware(util.EditorRole), scopeMiddleware(util.PostsWriteScope), okHandler)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, "/auth", nil)
+			require.NoError(t, err)
+			tc.setupAuth(t, request)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+}
+
+func TestSessionMiddleware(t *testing.T) {
+	store := newFakeStore()
+	server := newTestServer(t, store)
+	user := randomUser(t, server, store, util.AdminRole, util.RandomString(12))
+
+	router := gin.New()
+	router.GET("/session", authMiddleware(server.tokenMaker, store), sessionMiddleware(), okHandler)
+
+	request, err := http.NewRequest(http.MethodGet, "/session", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, user, token.AccessToken)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	// A personal access token can not manage the credentials of its owner
+	request, err = http.NewRequest(http.MethodGet, "/session", nil)
+	require.NoError(t, err)
+	addPersonalAccessToken(t, request, store, user, db.GetPersonalAccessTokenByHashRow{Scopes: []string{util.UsersAdminScope}})
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func TestIPAllowlistMiddleware(t *testing.T) {
+	testCases := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		forwardedFor   string
+		expectedCode   int
+	}{
+		{name: "Allowed", remoteAddr: "10.1.2.3:4000", expectedCode: http.StatusOK},
+		{name: "AllowedMappedIPv4", remoteAddr: "[::ffff:10.1.2.3]:4000", expectedCode: http.StatusOK},
+		{name: "NotAllowed", remoteAddr: "192.0.2.10:4000", expectedCode: http.StatusForbidden},
+		{name: "ForgedForwardedFor", remoteAddr: "192.0.2.10:4000", forwardedFor: "10.1.2.3", expectedCode: http.StatusForbidden},
+		{name: "TrustedProxy", trustedProxies: []string{"192.0.2.0/24"}, remoteAddr: "192.0.2.10:4000", forwardedFor: "10.1.2.3", expectedCode: http.StatusOK},
+		{name: "TrustedProxyNotAllowed", trustedProxies: []string{"192.0.2.0/24"}, remoteAddr: "192.0.2.10:4000", forwardedFor: "198.51.100.7", expectedCode: http.StatusForbidden},
+	}
+
+	allowed := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			trustedProxies, err := parseTrustedProxies(tc.trustedProxies)
+			require.NoError(t, err)
+			router, err := newRouter(trustedProxies)
+			require.NoError(t, err)
+			router.GET("/allowlist", ipAllowlistMiddleware(allowed), okHandler)
+
+			request, err := http.NewRequest(http.MethodGet, "/allowlist", nil)
+			require.NoError(t, err)
+			request.RemoteAddr = tc.remoteAddr
+			if len(tc.forwardedFor) > 0 {
+				request.Header.Set("X-Forwarded-For", tc.forwardedFor)
+			}
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+}
+
+func TestClientCertMiddleware(t *testing.T) {
+	router := gin.New()
+	router.GET("/admin", clientCertMiddleware(), okHandler)
+
+	testCases := []struct {
+		name         string
+		tls          *tls.ConnectionState
+		expectedCode int
+	}{
+		{name: "PlainHTTP", expectedCode: http.StatusForbidden},
+		{name: "NoClientCertificate", tls: &tls.ConnectionState{}, expectedCode: http.StatusForbidden},
+		{name: "VerifiedClientCertificate", tls: &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}, expectedCode: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, "/admin", nil)
+			require.NoError(t, err)
+			request.TLS = tc.tls
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const avatarBucketPath = "avatars"
+
+var (
+	errNotAnImage      = errors.New("the avatar must be an image")
+	errAvatarNotExists = errors.New("the user has no avatar")
+)
+
+type authorResponse struct {
+	Username    string            `json:"username"`
+	DisplayName string            `json:"display_name"`
+	Bio         string            `json:"bio"`
+	Website     string            `json:"website"`
+	SocialLinks map[string]string `json:"social_links"`
+	AvatarURL   string            `json:"avatar_url"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+func newAuthorResponse(author db.GetAuthorByUsernameRow) authorResponse {
+	socialLinks := map[string]string{}
+	if err := json.Unmarshal(author.SocialLinks, &socialLinks); err != nil {
+		log.Printf("invalid social links of author %s: %v\n", author.Username, err)
+	}
+
+	return authorResponse{
+		Username:    author.Username,
+		DisplayName: author.DisplayName,
+		Bio:         author.Bio,
+		Website:     author.Website,
+		SocialLinks: socialLinks,
+		AvatarURL:   author.AvatarUrl,
+		CreatedAt:   author.CreatedAt,
+	}
+}
+
+// listAuthors godoc
+//
+//	@Summary		List Authors
+//	@Description	Recive the public profile of all authors
+//	@Tags			author,list
+//	@Produce		json
+//	@Success		200	{array}	authorResponse
+//	@Router			/authors [get]
+func (server *Server) listAuthors(ctx *gin.Context) {
+	authors, err := server.store.ListAuthors(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]authorResponse, 0, len(authors))
+	for _, author := range authors {
+		response = append(response, newAuthorResponse(db.GetAuthorByUsernameRow(author)))
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// get Author handler
+type getAuthorRequest struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+// getAuthor godoc
+//
+//	@Summary		Get an Author
+//	@Description	Recive the public profile of an author from its username
+//	@Tags			author,get
+//	@Produce		json
+//	@Success		200			{object}	authorResponse
+//
+//	@Param			username	path		string	true	"username"
+//	@Router			/authors/{username} [get]
+func (server *Server) getAuthor(ctx *gin.Context) {
+	var req getAuthorRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	author, err := server.store.GetAuthorByUsername(ctx, req.Username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newAuthorResponse(author))
+}
+
+// upload Avatar handler
+type uploadAvatarRequest struct {
+	Avatar *multipart.FileHeader `form:"avatar" binding:"required"`
+}
+
+// uploadAvatar godoc
+//
+//	@Summary					Upload Avatar
+//	@Description				Upload the avatar of the logged user, the image are upload to S3 services and replaces the previous one
+//	@Tags						author,me,update
+//	@Accept						multipart/form-data
+//	@Produce					json
+//	@Success					200		{object}	authorResponse
+//
+//	@Param						avatar	formData	file	true	"This is a image"
+//
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/me/avatar [put]
+func (server *Server) uploadAvatar(ctx *gin.Context) {
+	var req uploadAvatarRequest
+	if err := ctx.ShouldBindWith(&req, binding.FormMultipart); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	fileContent, err := req.Avatar.Open()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	defer fileContent.Close()
+
+	byteContainer, err := io.ReadAll(fileContent)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !strings.HasPrefix(http.DetectContentType(byteContainer), "image/") {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errNotAnImage))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	author, err := server.store.GetAuthorByUsername(ctx, user.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	objectName := user.Username + util.RandomString(4)
+	avatarURL, err := server.assetStore.UploadImage(ctx, byteContainer, avatarBucketPath, objectName)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	updated, err := server.store.SetUserAvatar(ctx, db.SetUserAvatarParams{
+		ID:        user.ID,
+		AvatarUrl: avatarURL,
+	})
+	if err != nil {
+		deleteErr := server.assetStore.DeleteImage(ctx, avatarBucketPath, objectName)
+		if deleteErr != nil {
+			log.Println("cannot delete uploaded avatar:", deleteErr)
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.deleteAvatarImage(ctx, author.AvatarUrl)
+
+	author.AvatarUrl = updated.AvatarUrl
+	ctx.JSON(http.StatusOK, newAuthorResponse(author))
+}
+
+// deleteAvatar godoc
+//
+//	@Summary					Delete Avatar
+//	@Description				Delete the avatar of the logged user
+//	@Tags						author,me,delete
+//	@Produce					json
+//	@Success					200	{object}	authorResponse
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/me/avatar [delete]
+func (server *Server) deleteAvatar(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	author, err := server.store.GetAuthorByUsername(ctx, user.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if len(author.AvatarUrl) == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(errAvatarNotExists))
+		return
+	}
+
+	_, err = server.store.SetUserAvatar(ctx, db.SetUserAvatarParams{
+		ID:        user.ID,
+		AvatarUrl: "",
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.deleteAvatarImage(ctx, author.AvatarUrl)
+
+	author.AvatarUrl = ""
+	ctx.JSON(http.StatusOK, newAuthorResponse(author))
+}
+
+// deleteAvatarImage removes a replaced avatar from the asset store, a failure only leaves an orphan image
+func (server *Server) deleteAvatarImage(ctx *gin.Context, avatarURL string) {
+	if len(avatarURL) == 0 {
+		return
+	}
+
+	last := avatarURL[strings.LastIndex(avatarURL, "/")+1:]
+	name := strings.Split(last, ".")[0]
+
+	err := server.assetStore.DeleteImage(ctx, avatarBucketPath, name)
+	if err != nil {
+		log.Println("cannot delete avatar:", err)
+	}
+}
@@ -2,9 +2,11 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 
 	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -12,13 +14,15 @@ import (
 
 // createCategory handler
 type createCategoryRequest struct {
-	Name string `json:"name" binding:"required,ascii"`
+	Name        string `json:"name" binding:"required,max=64"`
+	Slug        string `json:"slug"`
+	Description string `json:"description" binding:"max=500"`
 }
 
 // createCategory godoc
 //
 //	@Summary					Create a new Category
-//	@Description				Create a new Category
+//	@Description				Create a new Category, without a slug it is made from the name
 //	@Tags						category,create
 //	@Accept						json
 //	@Produce					json
@@ -37,7 +41,26 @@ func (server *Server) createCategory(ctx *gin.Context) {
 		return
 	}
 
-	category, err := server.store.CreateCategory(ctx, req.Name)
+	slug := req.Slug
+	if len(slug) > 0 {
+		if !util.IsSlug(slug) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidSlug))
+			return
+		}
+	} else {
+		var err error
+		slug, err = server.uniqueCategorySlug(ctx, req.Name, uuid.Nil)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	category, err := server.store.CreateCategory(ctx, db.CreateCategoryParams{
+		Name:        req.Name,
+		Slug:        slug,
+		Description: req.Description,
+	})
 	if err != nil {
 		if db.ErrorCode(err) == db.UniqueViolation {
 			ctx.JSON(http.StatusForbidden, errorResponse(err))
@@ -51,19 +74,19 @@ func (server *Server) createCategory(ctx *gin.Context) {
 
 // getCategory handler
 type getCategoryRequest struct {
-	ID string `uri:"id" binding:"required,uuid"`
+	ID string `uri:"id" binding:"required"`
 }
 
 // getCategory godoc
 //
 //	@Summary		Get a Category
-//	@Description	Recive the one category information from a id
+//	@Description	Recive the one category information from a id or a slug
 //	@Tags			category,get
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	db.Category
+//	@Success		200	{object}	db.LookupCategoryRow
 //
-//	@Param			id	path		string	true	"id"
+//	@Param			id	path		string	true	"id or slug"
 //	@Router			/category/{id} [get]
 func (server *Server) getCategory(ctx *gin.Context) {
 	var req getCategoryRequest
@@ -73,15 +96,13 @@ func (server *Server) getCategory(ctx *gin.Context) {
 		return
 	}
 
-	categoryID, err := uuid.Parse(req.ID)
+	categoryID, slug := lookupKey(req.ID)
+	category, err := server.store.LookupCategory(ctx, db.LookupCategoryParams{
+		ID:   categoryID,
+		Slug: slug,
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	category, err := server.store.GetCategory(ctx, categoryID)
-	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, db.ErrRecordNotFound) {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
 			return
 		}
@@ -116,7 +137,9 @@ func (server *Server) listCategories(ctx *gin.Context) {
 
 // updateCategory handler
 type updateCategoryRequestData struct {
-	Name string `json:"name" binding:"omitempty,ascii"`
+	Name        string  `json:"name" binding:"omitempty,max=64"`
+	Slug        string  `json:"slug"`
+	Description *string `json:"description" binding:"omitempty,max=500"`
 }
 type updateCategoryRequestID struct {
 	ID string `uri:"id" binding:"required,uuid"`
@@ -125,7 +148,7 @@ type updateCategoryRequestID struct {
 // updateCategory godoc
 //
 //	@Summary					Update Category
-//	@Description				Update the category information
+//	@Description				Update the category information, the slug only changes when it is sent
 //	@Tags						category,update
 //	@Accept						json
 //	@Produce					json
@@ -163,6 +186,18 @@ func (server *Server) updateCategory(ctx *gin.Context) {
 		arg.Name = pgtype.Text{String: reqData.Name, Valid: true}
 	}
 
+	if len(reqData.Slug) > 0 {
+		if !util.IsSlug(reqData.Slug) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidSlug))
+			return
+		}
+		arg.Slug = pgtype.Text{String: reqData.Slug, Valid: true}
+	}
+
+	if reqData.Description != nil {
+		arg.Description = pgtype.Text{String: *reqData.Description, Valid: true}
+	}
+
 	category, err := server.store.UpdateCategory(ctx, arg)
 
 	if err != nil {
@@ -0,0 +1,230 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/mail"
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	emailVerificationDuration = 48 * time.Hour
+	// emailVerificationInterval is the minimum time between two verification emails to the same user
+	emailVerificationInterval = time.Minute
+)
+
+var (
+	errInvalidEmailVerification = errors.New("invalid or expired email verification")
+	errEmailAlreadyVerified     = errors.New("the email is already verified")
+	errEmailInUse               = errors.New("the email is already used by another account")
+	errEmailNotVerified         = errors.New("verify your email before publishing posts")
+	errTooManyEmails            = errors.New("an email was sent recently, try again later")
+)
+
+// sendEmailVerification sends a link to confirm that the user owns an email,
+// the email of the user is set to it once the link is opened
+func (server *Server) sendEmailVerification(ctx *gin.Context, userID uuid.UUID, username string, email string) error {
+	verificationToken, tokenHash, err := token.NewOpaqueToken("")
+	if err != nil {
+		return err
+	}
+
+	_, err = server.store.CreateEmailVerification(ctx, db.CreateEmailVerificationParams{
+		UserID:    userID,
+		Email:     email,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(emailVerificationDuration),
+	})
+	if err != nil {
+		return err
+	}
+
+	verifyURL := server.config.FrontendURL + "/verify-email?token=" + url.QueryEscape(verificationToken)
+	server.sendMail(mail.Message{
+		To:      email,
+		Subject: "Confirm your email",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nOpen this link to confirm that %s is your email:\n\n%s\n\n"+
+				"The link expires in %s. If you did not expect this email, you can ignore it.\n",
+			username, email, verifyURL, emailVerificationDuration,
+		),
+	})
+	return nil
+}
+
+// checkEmailCooldown returns errTooManyEmails when a verification email was sent to the user
+// less than emailVerificationInterval ago, so the requests can not flood a mailbox
+func (server *Server) checkEmailCooldown(ctx *gin.Context, userID uuid.UUID) error {
+	recent, err := server.store.CountRecentEmailVerifications(ctx, db.CountRecentEmailVerificationsParams{
+		UserID:       userID,
+		CreatedAfter: time.Now().Add(-emailVerificationInterval),
+	})
+	if err != nil {
+		return err
+	}
+	if recent > 0 {
+		return errTooManyEmails
+	}
+	return nil
+}
+
+// requestEmailChange sends a verification link to the new email and a notice to the old one,
+// the email does not change until the link is opened. It returns errTooManyEmails during the
+// cooldown of the user.
+func (server *Server) requestEmailChange(ctx *gin.Context, userID uuid.UUID, username string, oldEmail string, newEmail string) error {
+	err := server.checkEmailCooldown(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = server.store.GetUserByEmail(ctx, newEmail)
+	if err == nil {
+		return errEmailInUse
+	}
+	if !errors.Is(err, db.ErrRecordNotFound) {
+		return err
+	}
+
+	err = server.sendEmailVerification(ctx, userID, username, newEmail)
+	if err != nil {
+		return err
+	}
+
+	server.sendMail(mail.Message{
+		To:      oldEmail,
+		Subject: "Your email is about to change",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nA change of the email of your account to %s was requested. "+
+				"It will take effect once the new address is confirmed.\n\n"+
+				"If you did not ask for it, change your password and contact an administrator.\n",
+			username, newEmail,
+		),
+	})
+	return nil
+}
+
+// ensureEmailVerified responds with 403 and returns false when the logged user has not verified its email
+func (server *Server) ensureEmailVerified(ctx *gin.Context) bool {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	if !user.EmailVerifiedAt.Valid {
+		ctx.JSON(http.StatusForbidden, errorResponse(errEmailNotVerified))
+		return false
+	}
+	return true
+}
+
+// verifyEmail handler
+type verifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// verifyEmail godoc
+//
+//	@Summary		Verify Email
+//	@Description	Confirm an email with the token of a verification link, on an email change the new email takes effect
+//	@Tags			user,email
+//	@Accept			json
+//	@Produce		json
+//	@Success		200		{object}	userResponse
+//
+//	@Param			token	body		verifyEmailRequest	true	"Verification Token"
+//	@Router			/email/verify [post]
+func (server *Server) verifyEmail(ctx *gin.Context) {
+	var req verifyEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	verification, err := server.store.GetEmailVerificationByHash(ctx, token.HashOpaqueToken(req.Token))
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidEmailVerification))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if verification.UsedAt.Valid || time.Now().After(verification.ExpiresAt) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidEmailVerification))
+		return
+	}
+
+	result, err := server.store.VerifyEmailTx(ctx, db.VerifyEmailTxParams{
+		VerificationID: verification.ID,
+		UserID:         verification.UserID,
+		Email:          verification.Email,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidEmailVerification))
+			return
+		}
+		if db.ErrorCode(err) == db.UniqueViolation {
+			ctx.JSON(http.StatusForbidden, errorResponse(errEmailInUse))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(result.User))
+}
+
+// resendEmailVerification godoc
+//
+//	@Summary					Resend Email Verification
+//	@Description				Send a new verification link to the email of the logged user, the verification emails of a user are limited to one a minute
+//	@Tags						user,email
+//	@Produce					json
+//	@Success					200	{object}	string
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/me/email/verification [post]
+func (server *Server) resendEmailVerification(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if user.EmailVerifiedAt.Valid {
+		ctx.JSON(http.StatusConflict, errorResponse(errEmailAlreadyVerified))
+		return
+	}
+
+	err = server.checkEmailCooldown(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, errTooManyEmails) {
+			ctx.JSON(http.StatusTooManyRequests, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = server.sendEmailVerification(ctx, user.ID, user.Username, user.Email)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user.Email)
+}
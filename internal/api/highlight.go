@@ -0,0 +1,92 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/JairoRiver/personal_blog_backend/pkg/render"
+	"github.com/gin-gonic/gin"
+)
+
+// highlightThemeMaxAge is how many seconds the clients cache the stylesheets of the themes
+const highlightThemeMaxAge = "86400"
+
+type listHighlightThemesResponse struct {
+	DefaultTheme string   `json:"default_theme"`
+	Themes       []string `json:"themes"`
+}
+
+// listHighlightThemes godoc
+//
+//	@Summary		List Highlight Themes
+//	@Description	Return the themes of the highlighted code in the posts and the theme used by default
+//	@Tags			highlight
+//	@Produce		json
+//	@Success		200	{object}	listHighlightThemesResponse
+//	@Router			/highlight/themes [get]
+func (server *Server) listHighlightThemes(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, listHighlightThemesResponse{
+		DefaultTheme: server.highlightTheme(),
+		Themes:       render.Themes(),
+	})
+}
+
+// getHighlightTheme handler
+type getHighlightThemeRequest struct {
+	Theme string `uri:"theme" binding:"required"`
+}
+
+// getHighlightTheme godoc
+//
+//	@Summary		Get Highlight Theme
+//	@Description	Return the stylesheet of a theme for the highlighted code in the posts
+//	@Tags			highlight
+//	@Produce		text/css
+//	@Success		200		{string}	string
+//
+//	@Param			theme	path		string	true	"Theme name"
+//	@Router			/highlight/themes/{theme}/style.css [get]
+func (server *Server) getHighlightTheme(ctx *gin.Context) {
+	var req getHighlightThemeRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	server.writeHighlightTheme(ctx, req.Theme)
+}
+
+// getDefaultHighlightTheme godoc
+//
+//	@Summary		Get Default Highlight Theme
+//	@Description	Return the stylesheet of the configured theme for the highlighted code in the posts
+//	@Tags			highlight
+//	@Produce		text/css
+//	@Success		200	{string}	string
+//	@Router			/highlight/style.css [get]
+func (server *Server) getDefaultHighlightTheme(ctx *gin.Context) {
+	server.writeHighlightTheme(ctx, server.highlightTheme())
+}
+
+func (server *Server) writeHighlightTheme(ctx *gin.Context, theme string) {
+	css, err := render.ThemeCSS(theme)
+	if err != nil {
+		if errors.Is(err, render.ErrUnknownTheme) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Header("Cache-Control", "public, max-age="+highlightThemeMaxAge)
+	ctx.Data(http.StatusOK, "text/css; charset=utf-8", []byte(css))
+}
+
+// highlightTheme returns the configured theme of the highlighted code
+func (server *Server) highlightTheme() string {
+	if len(server.config.HighlightTheme) == 0 {
+		return render.DefaultTheme
+	}
+	return server.config.HighlightTheme
+}
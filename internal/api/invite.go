@@ -0,0 +1,280 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/mail"
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const defaultInviteDays = 7
+
+var errInvalidInvite = errors.New("invalid or expired invite")
+
+// createInvite handler
+type createInviteRequest struct {
+	Email         string `json:"email" binding:"required,email"`
+	Role          string `json:"role" binding:"omitempty,oneof=admin editor author"`
+	ExpiresInDays int    `json:"expires_in_days" binding:"omitempty,min=1,max=30"`
+}
+
+type inviteResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	InvitedBy string    `json:"invited_by"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// createInvite godoc
+//
+//	@Summary					Invite a User
+//	@Description				Send an invitation to an email, the invitee chooses its own username and password
+//	@Tags						user,invite,create
+//	@Accept						json
+//	@Produce					json
+//	@Success					200		{object}	inviteResponse
+//
+//	@Param						invite	body		createInviteRequest	true	"Invite Data"
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/invite [post]
+func (server *Server) createInvite(ctx *gin.Context) {
+	var req createInviteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, err := server.store.GetUserByEmail(ctx, req.Email)
+	if err == nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(errEmailInUse))
+		return
+	}
+	if !errors.Is(err, db.ErrRecordNotFound) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	inviter, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	role := req.Role
+	if len(role) == 0 {
+		role = util.AuthorRole
+	}
+
+	days := req.ExpiresInDays
+	if days == 0 {
+		days = defaultInviteDays
+	}
+
+	inviteToken, tokenHash, err := token.NewOpaqueToken("")
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	invite, err := server.store.CreateInvite(ctx, db.CreateInviteParams{
+		Email:     req.Email,
+		Role:      role,
+		TokenHash: tokenHash,
+		InvitedBy: pgtype.UUID{Bytes: inviter.ID, Valid: true},
+		ExpiresAt: time.Now().AddDate(0, 0, days),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	acceptURL := server.config.FrontendURL + "/accept-invite?token=" + url.QueryEscape(inviteToken)
+	server.sendMail(mail.Message{
+		To:      invite.Email,
+		Subject: fmt.Sprintf("You are invited to %s", appName),
+		Body: fmt.Sprintf(
+			"Hi,\n\n%s invited you to join %s as %s. Open this link to choose your username and password:\n\n%s\n\n"+
+				"The invitation expires on %s.\n",
+			inviter.Username, appName, invite.Role, acceptURL, invite.ExpiresAt.Format(time.RFC1123),
+		),
+	})
+
+	rsp := inviteResponse{
+		ID:        invite.ID,
+		Email:     invite.Email,
+		Role:      invite.Role,
+		InvitedBy: inviter.Username,
+		ExpiresAt: invite.ExpiresAt,
+		CreatedAt: invite.CreatedAt,
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// listInvites godoc
+//
+//	@Summary					List Invites
+//	@Description				Recive the invitations that were not accepted, revoked nor expired
+//	@Tags						user,invite,list
+//	@Produce					json
+//	@Success					200	{array}	inviteResponse
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/invites [get]
+func (server *Server) listInvites(ctx *gin.Context) {
+	invites, err := server.store.ListPendingInvites(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]inviteResponse, 0, len(invites))
+	for _, invite := range invites {
+		response = append(response, inviteResponse{
+			ID:        invite.ID,
+			Email:     invite.Email,
+			Role:      invite.Role,
+			InvitedBy: invite.InvitedBy.String,
+			ExpiresAt: invite.ExpiresAt,
+			CreatedAt: invite.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// revoke Invite handler
+type revokeInviteRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// revokeInvite godoc
+//
+//	@Summary					Revoke Invite
+//	@Description				Revoke a pending invitation
+//	@Tags						user,invite,delete
+//	@Produce					json
+//	@Param						id	path		string	true	"id"
+//	@Success					200	{object}	uuid.UUID
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/invite/{id} [delete]
+func (server *Server) revokeInvite(ctx *gin.Context) {
+	var req revokeInviteRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	inviteID, err := uuid.Parse(req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	revoked, err := server.store.RevokeInvite(ctx, inviteID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if revoked == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(db.ErrRecordNotFound))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, inviteID)
+}
+
+// acceptInvite handler
+type acceptInviteRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Username string `json:"username" binding:"required,alphanum"`
+	Password string `json:"password" binding:"required"`
+}
+
+// acceptInvite godoc
+//
+//	@Summary		Accept Invite
+//	@Description	Create the account of an invitation with the chosen username and password
+//	@Tags			user,invite
+//	@Accept			json
+//	@Produce		json
+//	@Success		200		{object}	userResponse
+//
+//	@Param			invite	body		acceptInviteRequest	true	"Invite Token and User Data"
+//	@Router			/invite/accept [post]
+func (server *Server) acceptInvite(ctx *gin.Context) {
+	var req acceptInviteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	invite, err := server.store.GetInviteByHash(ctx, token.HashOpaqueToken(req.Token))
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidInvite))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if invite.AcceptedAt.Valid || invite.RevokedAt.Valid || time.Now().After(invite.ExpiresAt) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidInvite))
+		return
+	}
+
+	hashedPassword, err := server.hashNewPassword(req.Password)
+	if err != nil {
+		if errors.Is(err, util.ErrWeakPassword) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.AcceptInviteTx(ctx, db.AcceptInviteTxParams{
+		InviteID: invite.ID,
+		User: db.CreateUserParams{
+			Username: req.Username,
+			Email:    invite.Email,
+			Password: hashedPassword,
+			Role:     invite.Role,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidInvite))
+			return
+		}
+		if db.ErrorCode(err) == db.UniqueViolation {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(result.User))
+}
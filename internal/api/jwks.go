@@ -0,0 +1,32 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/gin-gonic/gin"
+)
+
+var errNoPublicKeys = errors.New("the configured token type has no public keys")
+
+// getJWKS returns the public keys that verify the tokens issued by the server,
+// so other services can check them without holding the signing key.
+// It is only available when the token type is asymmetric.
+func (server *Server) getJWKS(ctx *gin.Context) {
+	maker, ok := server.tokenMaker.(token.PublicKeyMaker)
+	if !ok {
+		ctx.JSON(http.StatusNotFound, errorResponse(errNoPublicKeys))
+		return
+	}
+
+	// A keyring of symmetric keys has no public keys either
+	keys := maker.PublicKeys()
+	if len(keys.Keys) == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(errNoPublicKeys))
+		return
+	}
+
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, keys)
+}
@@ -6,14 +6,14 @@ import (
 	"time"
 
 	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
-	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type loginUserRequest struct {
 	Username string `json:"username" binding:"required,alphanum"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required"`
 }
 
 type loginUserResponse struct {
@@ -29,11 +29,14 @@ type loginUserResponse struct {
 // loginUser godoc
 //
 //	@Summary		Login User
-//	@Description	Login a user and return access token a refresh token
+//	@Description	Login a user and return access token a refresh token.
+//	@Description	When the user has two-factor authentication enabled it returns a challenge to complete on /login/2fa instead.
+//	@Description	Repeated failures lock the username and the client IP for a growing time, the response is 429 with a Retry-After header.
 //	@Tags			user,login
 //	@Accept			json
 //	@Produce		json
 //	@Success		200		{object}	loginUserResponse
+//	@Success		202		{object}	loginChallengeResponse
 //
 //	@Param			user	body		loginUserRequest	true	"User Login"
 //	@Router			/login [post]
@@ -44,62 +47,112 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		return
 	}
 
+	if !server.checkLoginLock(ctx, req.Username) {
+		return
+	}
+
 	user, err := server.store.GetUserByUsername(ctx, req.Username)
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Unknown usernames are checked against a dummy hash so they take as long as wrong passwords
+	userFound := err == nil
+	passwordHash := server.dummyPasswordHash
+	if userFound {
+		passwordHash = user.Password
+	}
+
+	err = server.passwordHasher.Check(req.Password, passwordHash)
+	if !userFound || err != nil {
+		if err := server.recordLoginFailure(ctx, req.Username); err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidCredentials))
+		return
+	}
+
+	if user.DisabledAt.Valid {
+		ctx.JSON(http.StatusForbidden, errorResponse(errUserDisabled))
+		return
+	}
+
+	server.rehashPassword(ctx, user.ID, req.Password, user.Password)
+
+	if user.TotpEnabledAt.Valid {
+		server.createMFAChallenge(ctx, user.ID)
+		return
+	}
+
+	rsp, err := server.createLoginSession(ctx, user.ID, user.Username, user.Role)
 	if err != nil {
-		if errors.Is(err, db.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		if errors.Is(err, errUserDisabled) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
 			return
 		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
+	ctx.JSON(http.StatusOK, rsp)
+}
 
-	err = util.CheckPassword(req.Password, user.Password)
+// createLoginSession creates the access token, the refresh token and the session of a user
+// that completed the login, it returns errUserDisabled if the user was disabled
+func (server *Server) createLoginSession(ctx *gin.Context, userID uuid.UUID, username string, role string) (loginUserResponse, error) {
+	user, err := server.store.GetUser(ctx, userID)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
+		return loginUserResponse{}, err
+	}
+	if user.DisabledAt.Valid {
+		return loginUserResponse{}, errUserDisabled
 	}
 
+	server.resetLoginAttempts(ctx, username)
+
 	accessToken, accessPayload, err := server.tokenMaker.CreateToken(
-		user.Username,
+		username,
+		role,
+		token.AccessToken,
 		server.config.AccessTokenDuration,
 	)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return loginUserResponse{}, err
 	}
 
 	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(
-		user.Username,
+		username,
+		role,
+		token.RefreshToken,
 		server.config.RefreshTokenDuration,
 	)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return loginUserResponse{}, err
 	}
 
 	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
 		ID:           refreshPayload.ID,
-		Username:     user.Username,
+		Username:     username,
 		RefreshToken: refreshToken,
 		UserAgent:    ctx.Request.UserAgent(),
 		ClientIp:     ctx.ClientIP(),
 		IsBlocked:    false,
 		ExpiresAt:    refreshPayload.ExpiredAt,
+		FamilyID:     refreshPayload.ID,
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return loginUserResponse{}, err
 	}
 
 	rsp := loginUserResponse{
 		AccessToken:           accessToken,
 		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
-		User:                  user.Username,
-		UserID:                user.ID,
+		User:                  username,
+		UserID:                userID,
 		SessionID:             session.ID,
 		RefreshToken:          refreshToken,
 		RefreshTokenExpiresAt: refreshPayload.ExpiredAt,
 	}
-	ctx.JSON(http.StatusOK, rsp)
+	return rsp, nil
 }
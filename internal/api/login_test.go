@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginUserThrottle(t *testing.T) {
+	store := newFakeStore()
+	server := newTestServer(t, store)
+	password := util.RandomString(12)
+	user := randomUser(t, server, store, util.AuthorRole, password)
+
+	loginWith := func(password string) int {
+		recorder := serveJSON(t, server.router, http.MethodPost, "/v1/login", gin.H{
+			"username": user.Username,
+			"password": password,
+		})
+		if recorder.Code == http.StatusTooManyRequests {
+			retryAfter, err := strconv.Atoi(recorder.Header().Get("Retry-After"))
+			require.NoError(t, err)
+			require.Positive(t, retryAfter)
+		}
+		return recorder.Code
+	}
+
+	for i := 0; i < usernameFreeLoginAttempts; i++ {
+		require.Equal(t, http.StatusUnauthorized, loginWith(util.RandomString(12)))
+	}
+
+	// The next failure locks the username, even the right password is rejected until the lock ends
+	require.Equal(t, http.StatusUnauthorized, loginWith(util.RandomString(12)))
+	require.Equal(t, http.StatusTooManyRequests, loginWith(password))
+	require.Equal(t, http.StatusTooManyRequests, loginWith(util.RandomString(12)))
+}
+
+func TestLoginUserUnknownUsername(t *testing.T) {
+	store := newFakeStore()
+	server := newTestServer(t, store)
+
+	recorder := serveJSON(t, server.router, http.MethodPost, "/v1/login", gin.H{
+		"username": util.RandomString(8),
+		"password": util.RandomString(12),
+	})
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
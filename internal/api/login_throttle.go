@@ -0,0 +1,147 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/netip"
+	"time"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	loginScopeUsername = "username"
+	loginScopeIP       = "ip"
+
+	// Failed logins allowed before the backoff starts, a client IP may be shared by many users
+	usernameFreeLoginAttempts = 5
+	ipFreeLoginAttempts       = 20
+
+	loginBackoffBase = time.Second
+	loginMaxLockout  = 15 * time.Minute
+	// loginAttemptWindow is the time without failures after which the counter starts again
+	loginAttemptWindow = time.Hour
+	// loginIPv6PrefixBits groups the IPv6 clients by network, a single host usually owns a whole /64
+	loginIPv6PrefixBits = 64
+)
+
+var (
+	errInvalidCredentials   = errors.New("invalid credentials")
+	errTooManyLoginAttempts = errors.New("too many failed login attempts, try again later")
+)
+
+// loginLockout returns how long a login is locked after a number of consecutive failures,
+// it doubles with every failure past the free attempts
+func loginLockout(failures int32, freeAttempts int32) time.Duration {
+	extra := failures - freeAttempts
+	if extra <= 0 {
+		return 0
+	}
+
+	lockout := time.Duration(math.Pow(2, float64(extra-1))) * loginBackoffBase
+	if lockout <= 0 || lockout > loginMaxLockout {
+		return loginMaxLockout
+	}
+	return lockout
+}
+
+// loginClientIP returns the key of the client IP for the throttle. ClientIP only reads the
+// forwarded headers sent by the TRUSTED_PROXIES, so a client can not pick its own key, and the
+// IPv6 addresses are reduced to their network so rotating them does not skip the lock.
+func loginClientIP(ctx *gin.Context) string {
+	addr, err := netip.ParseAddr(ctx.ClientIP())
+	if err != nil {
+		return ctx.ClientIP()
+	}
+
+	addr = addr.Unmap()
+	if addr.Is4() {
+		return addr.String()
+	}
+
+	prefix, err := addr.WithZone("").Prefix(loginIPv6PrefixBits)
+	if err != nil {
+		return addr.String()
+	}
+	return prefix.String()
+}
+
+// checkLoginLock responds with 429 and returns false when the username or the client IP is locked
+func (server *Server) checkLoginLock(ctx *gin.Context, username string) bool {
+	lockedUntil, err := server.store.GetLoginLock(ctx, db.GetLoginLockParams{
+		Username: username,
+		ClientIp: loginClientIP(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return true
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	retryAfter := int(math.Ceil(time.Until(lockedUntil.Time).Seconds()))
+	ctx.Header("Retry-After", fmt.Sprint(retryAfter))
+	ctx.JSON(http.StatusTooManyRequests, errorResponse(errTooManyLoginAttempts))
+	return false
+}
+
+// recordLoginFailure counts a failed login for the username and the client IP and locks them
+// once they run out of free attempts
+func (server *Server) recordLoginFailure(ctx *gin.Context, username string) error {
+	keys := []struct {
+		scope        string
+		key          string
+		freeAttempts int32
+	}{
+		{scope: loginScopeUsername, key: username, freeAttempts: usernameFreeLoginAttempts},
+		{scope: loginScopeIP, key: loginClientIP(ctx), freeAttempts: ipFreeLoginAttempts},
+	}
+
+	for _, k := range keys {
+		attempt, err := server.store.RecordLoginFailure(ctx, db.RecordLoginFailureParams{
+			Scope:       k.scope,
+			Key:         k.key,
+			ResetBefore: time.Now().Add(-loginAttemptWindow),
+		})
+		if err != nil {
+			return err
+		}
+
+		lockout := loginLockout(attempt.Failures, k.freeAttempts)
+		if lockout == 0 {
+			continue
+		}
+
+		if lockout == loginMaxLockout {
+			log.Printf("login locked for %s %s after %d failed attempts\n", k.scope, k.key, attempt.Failures)
+		}
+
+		err = server.store.LockLogin(ctx, db.LockLoginParams{
+			Scope:       k.scope,
+			Key:         k.key,
+			LockedUntil: pgtype.Timestamptz{Time: time.Now().Add(lockout), Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resetLoginAttempts clears the failures of a username after a successful login. The failures
+// of the client IP are kept, a valid account must not reset the guesses made against others.
+func (server *Server) resetLoginAttempts(ctx *gin.Context, username string) {
+	err := server.store.ResetLoginAttempts(ctx, db.ResetLoginAttemptsParams{
+		Scope: loginScopeUsername,
+		Key:   username,
+	})
+	if err != nil {
+		log.Println("cannot reset login attempts:", err)
+	}
+}
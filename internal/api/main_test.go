@@ -0,0 +1,80 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// newTestServer creates a server for the tests, the passwords are hashed with cheap parameters
+func newTestServer(t *testing.T, store db.Store) *Server {
+	config := util.Config{
+		Environment:          util.TestEnvironment,
+		TokenSymmetricKey:    util.RandomString(32),
+		AccessTokenDuration:  time.Minute,
+		RefreshTokenDuration: time.Hour,
+		Argon2Memory:         64,
+		Argon2Iterations:     1,
+		Argon2Parallelism:    1,
+	}
+
+	server, err := NewServer(config, store)
+	require.NoError(t, err)
+	return server
+}
+
+// randomUser creates a user with the role and the password in the store
+func randomUser(t *testing.T, server *Server, store *fakeStore, role string, password string) db.User {
+	hashedPassword, err := server.passwordHasher.Hash(password)
+	require.NoError(t, err)
+
+	user := db.User{
+		ID:        uuid.New(),
+		Username:  util.RandomString(8),
+		Email:     util.RandomString(8) + "@example.com",
+		Password:  hashedPassword,
+		Role:      role,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	store.putUser(user)
+	return user
+}
+
+// addAuthorization sets the authorization header with a new token of the user
+func addAuthorization(t *testing.T, request *http.Request, tokenMaker token.Maker, user db.User, tokenType token.Type) {
+	accessToken, _, err := tokenMaker.CreateToken(user.Username, user.Role, tokenType, time.Minute)
+	require.NoError(t, err)
+
+	request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+accessToken)
+}
+
+// serveJSON sends a request with a JSON body to the handler and returns the recorded response
+func serveJSON(t *testing.T, handler http.Handler, method string, url string, body any) *httptest.ResponseRecorder {
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(method, url, bytes.NewReader(data))
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
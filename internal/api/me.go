@@ -0,0 +1,264 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/mail"
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var errWrongPassword = errors.New("the current password is not correct")
+
+// meResponse includes the unused recovery codes so the user notices before running out of them
+type meResponse struct {
+	userResponse
+	TwoFactorEnabled  bool           `json:"two_factor_enabled"`
+	RecoveryCodesLeft int64          `json:"recovery_codes_left"`
+	Profile           authorResponse `json:"profile"`
+}
+
+// getMeResponse reads the account, the two-factor authentication status and the public profile of a user
+func (server *Server) getMeResponse(ctx *gin.Context, userID uuid.UUID, username string) (meResponse, error) {
+	user, err := server.store.GetUser(ctx, userID)
+	if err != nil {
+		return meResponse{}, err
+	}
+
+	author, err := server.store.GetAuthorByUsername(ctx, username)
+	if err != nil {
+		return meResponse{}, err
+	}
+
+	rsp := meResponse{
+		userResponse:     getUserResponse(user),
+		TwoFactorEnabled: user.TotpEnabledAt.Valid,
+		Profile:          newAuthorResponse(author),
+	}
+
+	if rsp.TwoFactorEnabled {
+		rsp.RecoveryCodesLeft, err = server.store.CountRecoveryCodes(ctx, userID)
+		if err != nil {
+			return meResponse{}, err
+		}
+	}
+	return rsp, nil
+}
+
+// getMe godoc
+//
+//	@Summary					Get Me
+//	@Description				Recive the information and the public profile of the logged user, with the recovery codes left when two-factor authentication is enabled
+//	@Tags						user,me,get
+//	@Produce					json
+//	@Success					200	{object}	meResponse
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/me [get]
+func (server *Server) getMe(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	account, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := server.getMeResponse(ctx, account.ID, account.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// updateMe handler, the profile fields are only changed when they are sent and an empty string clears them
+type updateMeRequest struct {
+	Email       string            `json:"email" binding:"omitempty,email"`
+	DisplayName *string           `json:"display_name" binding:"omitempty,max=64"`
+	Bio         *string           `json:"bio" binding:"omitempty,max=2000"`
+	Website     *string           `json:"website" binding:"omitempty,eq=|url"`
+	SocialLinks map[string]string `json:"social_links" binding:"omitempty,max=10,dive,keys,alphanum,max=32,endkeys,url"`
+}
+
+// updateMe godoc
+//
+//	@Summary					Update Me
+//	@Description				Update the account and the public profile of the logged user, a new email takes effect once it is confirmed from a link sent to it
+//	@Description				The verification emails of a user are limited to one a minute, a change during the cooldown is rejected with 429.
+//	@Tags						user,me,update
+//	@Accept						json
+//	@Produce					json
+//	@Param						user	body		updateMeRequest	true	"User Data"
+//	@Success					200		{object}	meResponse
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/me [put]
+func (server *Server) updateMe(ctx *gin.Context) {
+	var req updateMeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	account, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	//The new email takes effect once it is verified
+	if len(req.Email) > 0 && req.Email != account.Email {
+		err = server.requestEmailChange(ctx, account.ID, account.Username, account.Email, req.Email)
+		if err != nil {
+			if errors.Is(err, errEmailInUse) {
+				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				return
+			}
+			if errors.Is(err, errTooManyEmails) {
+				ctx.JSON(http.StatusTooManyRequests, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	arg := db.UpdateAuthorProfileParams{
+		ID: account.ID,
+	}
+	if req.DisplayName != nil {
+		arg.DisplayName = pgtype.Text{String: *req.DisplayName, Valid: true}
+	}
+	if req.Bio != nil {
+		arg.Bio = pgtype.Text{String: *req.Bio, Valid: true}
+	}
+	if req.Website != nil {
+		arg.Website = pgtype.Text{String: *req.Website, Valid: true}
+	}
+	if req.SocialLinks != nil {
+		arg.SocialLinks, err = json.Marshal(req.SocialLinks)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	_, err = server.store.UpdateAuthorProfile(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := server.getMeResponse(ctx, account.ID, account.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// changePassword handler
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// changePassword godoc
+//
+//	@Summary					Change Password
+//	@Description				Change the password of the logged user, every session is revoked and a new one is returned.
+//	@Description				Wrong current passwords count as failed logins.
+//	@Tags						user,me,password
+//	@Accept						json
+//	@Produce					json
+//	@Param						password	body		changePasswordRequest	true	"Current and New Password"
+//	@Success					200			{object}	loginUserResponse
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/me/password [put]
+func (server *Server) changePassword(ctx *gin.Context) {
+	var req changePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if !server.checkLoginLock(ctx, authPayload.Username) {
+		return
+	}
+
+	user, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = server.passwordHasher.Check(req.CurrentPassword, user.Password)
+	if err != nil {
+		if err := server.recordLoginFailure(ctx, user.Username); err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errWrongPassword))
+		return
+	}
+
+	hashedPassword, err := server.hashNewPassword(req.NewPassword)
+	if err != nil {
+		if errors.Is(err, util.ErrWeakPassword) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = server.store.ChangePasswordTx(ctx, db.ChangePasswordTxParams{
+		UserID:         user.ID,
+		Username:       user.Username,
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.sendMail(mail.Message{
+		To:      user.Email,
+		Subject: "Your password was changed",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nThe password of your account was changed on %s and every other session was closed.\n\n"+
+				"If you did not do it, reset your password and contact an administrator.\n",
+			user.Username, time.Now().Format(time.RFC1123),
+		),
+	})
+
+	rsp, err := server.createLoginSession(ctx, user.ID, user.Username, user.Role)
+	if err != nil {
+		if errors.Is(err, errUserDisabled) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
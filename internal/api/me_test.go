@@ -0,0 +1,95 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMeRecoveryCodes(t *testing.T) {
+	store := newFakeStore()
+	server := newTestServer(t, store)
+
+	user := randomUser(t, server, store, util.AuthorRole, util.RandomString(12))
+	withTOTP := randomUser(t, server, store, util.AuthorRole, util.RandomString(12))
+	withTOTP.TotpEnabledAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	store.putUser(withTOTP)
+	store.recoveryCodes[withTOTP.ID] = 7
+
+	getMe := func(t *testing.T, user db.User) meResponse {
+		request, err := http.NewRequest(http.MethodGet, "/v1/me", nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server.tokenMaker, user, token.AccessToken)
+
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var rsp meResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		return rsp
+	}
+
+	rsp := getMe(t, user)
+	require.False(t, rsp.TwoFactorEnabled)
+	require.Zero(t, rsp.RecoveryCodesLeft)
+
+	rsp = getMe(t, withTOTP)
+	require.True(t, rsp.TwoFactorEnabled)
+	require.Equal(t, int64(7), rsp.RecoveryCodesLeft)
+}
+
+func TestEmailVerificationCooldown(t *testing.T) {
+	store := newFakeStore()
+	server := newTestServer(t, store)
+	user := randomUser(t, server, store, util.AuthorRole, util.RandomString(12))
+	other := randomUser(t, server, store, util.AuthorRole, util.RandomString(12))
+
+	send := func(user db.User, method string, url string, body any) int {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		request, err := http.NewRequest(method, url, bytes.NewReader(data))
+		require.NoError(t, err)
+		addAuthorization(t, request, server.tokenMaker, user, token.AccessToken)
+
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+	changeEmail := func(user db.User) int {
+		return send(user, http.MethodPut, "/v1/me", gin.H{"email": util.RandomString(8) + "@example.com"})
+	}
+
+	require.Equal(t, http.StatusOK, changeEmail(user))
+	require.Len(t, store.verifications, 1)
+
+	// Every way to send a verification email shares the cooldown of the user
+	require.Equal(t, http.StatusTooManyRequests, changeEmail(user))
+	require.Equal(t, http.StatusTooManyRequests, send(user, http.MethodPost, "/v1/me/email/verification", nil))
+	require.Len(t, store.verifications, 1)
+
+	// The profile can still be updated during the cooldown
+	require.Equal(t, http.StatusOK, send(user, http.MethodPut, "/v1/me", gin.H{"bio": util.RandomString(20)}))
+
+	// The cooldown is per user
+	require.Equal(t, http.StatusOK, send(other, http.MethodPost, "/v1/me/email/verification", nil))
+	require.Equal(t, http.StatusTooManyRequests, changeEmail(other))
+
+	// Once it is over a new email can be sent
+	store.mu.Lock()
+	for i := range store.verifications {
+		store.verifications[i].CreatedAt = time.Now().Add(-emailVerificationInterval)
+	}
+	store.mu.Unlock()
+	require.Equal(t, http.StatusOK, changeEmail(user))
+}
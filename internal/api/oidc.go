@@ -0,0 +1,344 @@
+package api
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/oidc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// oidcUsernameMaxLength keeps the usernames derived from emails short
+	oidcUsernameMaxLength = 32
+	// oidcUsernameAttempts is how many random suffixes are tried when the derived username is taken
+	oidcUsernameAttempts = 5
+
+	// oidcLoginCookie binds a login to the browser that started it, so a login started by someone
+	// else can not be finished in the browser of the victim
+	oidcLoginCookie     = "oidc_login"
+	oidcLoginCookiePath = "/v1/login/oidc"
+)
+
+var (
+	errOIDCDisabled         = errors.New("single sign-on is not configured")
+	errInvalidOIDCLogin     = errors.New("invalid or expired single sign-on login")
+	errOIDCEmailNotVerified = errors.New("the email of the identity provider account is not verified")
+	errOIDCSignupDisabled   = errors.New("there is no user for the identity provider account and the sign up is not allowed for its email")
+	errOIDCLinkUnverified   = errors.New("a user with the email of the identity provider account exists but has not verified it, log in with the password and verify the email first")
+)
+
+// oidcMiddleware creates a gin middleware that rejects the single sign-on routes when the provider is not configured
+func oidcMiddleware(provider *oidc.Provider) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if provider == nil {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, errorResponse(errOIDCDisabled))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+type beginOIDCLoginResponse struct {
+	AuthorizationURL string    `json:"authorization_url"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+// beginOIDCLogin godoc
+//
+//	@Summary		Begin Single Sign-On Login
+//	@Description	Return the URL of the identity provider where the user signs in,
+//	@Description	the provider redirects back to the frontend with the code and the state for /login/oidc/callback.
+//	@Description	It sets a cookie that binds the login to the browser, the callback has to be sent with it.
+//	@Tags			oidc,login
+//	@Produce		json
+//	@Success		200	{object}	beginOIDCLoginResponse
+//	@Router			/login/oidc [get]
+func (server *Server) beginOIDCLogin(ctx *gin.Context) {
+	request, err := server.oidc.BeginLogin()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	stateHash := token.HashOpaqueToken(request.State)
+	login, err := server.store.CreateOIDCLogin(ctx, db.CreateOIDCLoginParams{
+		StateHash:    stateHash,
+		Nonce:        request.Nonce,
+		CodeVerifier: request.CodeVerifier,
+		ExpiresAt:    time.Now().Add(oidc.LoginTimeout),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	setOIDCLoginCookie(ctx, stateHash, int(oidc.LoginTimeout.Seconds()))
+	ctx.JSON(http.StatusOK, beginOIDCLoginResponse{AuthorizationURL: request.URL, ExpiresAt: login.ExpiresAt})
+}
+
+// setOIDCLoginCookie sets the cookie with the state hash of the login, a negative maxAge removes it
+func setOIDCLoginCookie(ctx *gin.Context, stateHash string, maxAge int) {
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     oidcLoginCookie,
+		Value:    stateHash,
+		Path:     oidcLoginCookiePath,
+		MaxAge:   maxAge,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// finishOIDCLogin handler
+type finishOIDCLoginRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+// finishOIDCLogin godoc
+//
+//	@Summary		Finish Single Sign-On Login
+//	@Description	Exchange the code returned by the identity provider and return access token a refresh token.
+//	@Description	It requires the cookie set by /login/oidc in the same browser.
+//	@Description	The user is found by the provider account, then by its verified email, otherwise a new user is created
+//	@Description	when the domain of the email is in OIDC_SIGNUP_DOMAINS.
+//	@Description	A user with the same email that has not verified it is not linked, the response is 409 until they log in and verify it.
+//	@Description	When the user has two-factor authentication enabled it returns a challenge to complete on /login/2fa instead.
+//	@Tags			oidc,login
+//	@Accept			json
+//	@Produce		json
+//	@Success		200		{object}	loginUserResponse
+//	@Success		202		{object}	loginChallengeResponse
+//
+//	@Param			login	body		finishOIDCLoginRequest	true	"Code and State"
+//	@Router			/login/oidc/callback [post]
+func (server *Server) finishOIDCLogin(ctx *gin.Context) {
+	var req finishOIDCLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	stateHash := token.HashOpaqueToken(req.State)
+	cookie, err := ctx.Cookie(oidcLoginCookie)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie), []byte(stateHash)) != 1 {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidOIDCLogin))
+		return
+	}
+	setOIDCLoginCookie(ctx, "", -1)
+
+	login, err := server.store.ConsumeOIDCLogin(ctx, stateHash)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidOIDCLogin))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if time.Now().After(login.ExpiresAt) {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidOIDCLogin))
+		return
+	}
+
+	identity, err := server.oidc.FinishLogin(ctx, req.Code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if !identity.EmailVerified {
+		ctx.JSON(http.StatusForbidden, errorResponse(errOIDCEmailNotVerified))
+		return
+	}
+
+	username, err := server.findOIDCUser(ctx, identity)
+	if err != nil {
+		if errors.Is(err, errOIDCSignupDisabled) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		if errors.Is(err, errOIDCLinkUnverified) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		if db.ErrorCode(err) == db.UniqueViolation {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUserByUsername(ctx, username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if user.DisabledAt.Valid {
+		ctx.JSON(http.StatusForbidden, errorResponse(errUserDisabled))
+		return
+	}
+
+	if user.TotpEnabledAt.Valid {
+		server.createMFAChallenge(ctx, user.ID)
+		return
+	}
+
+	rsp, err := server.createLoginSession(ctx, user.ID, user.Username, user.Role)
+	if err != nil {
+		if errors.Is(err, errUserDisabled) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// findOIDCUser returns the username of the user linked to the identity, a user with the same verified email
+// is linked to it and when there is none a new user is created if the sign up is allowed for the email.
+// A user that has not verified the email is not linked, whoever registered the address first could
+// otherwise take over the account that owns it at the provider, or the other way around.
+func (server *Server) findOIDCUser(ctx *gin.Context, identity oidc.Identity) (string, error) {
+	linked, err := server.store.GetUserIdentity(ctx, db.GetUserIdentityParams{
+		Issuer:  server.oidc.Issuer(),
+		Subject: identity.Subject,
+	})
+	if err == nil {
+		user, err := server.store.GetUser(ctx, linked.UserID)
+		if err != nil {
+			return "", err
+		}
+		return user.Username, nil
+	}
+	if !errors.Is(err, db.ErrRecordNotFound) {
+		return "", err
+	}
+
+	user, err := server.store.GetUserByEmail(ctx, identity.Email)
+	if err == nil {
+		if !user.EmailVerifiedAt.Valid {
+			return "", errOIDCLinkUnverified
+		}
+
+		_, err = server.store.CreateUserIdentity(ctx, db.CreateUserIdentityParams{
+			UserID:  user.ID,
+			Issuer:  server.oidc.Issuer(),
+			Subject: identity.Subject,
+		})
+		// A concurrent login of the same identity already linked it
+		if err != nil && db.ErrorCode(err) != db.UniqueViolation {
+			return "", err
+		}
+		return user.Username, nil
+	}
+	if !errors.Is(err, db.ErrRecordNotFound) {
+		return "", err
+	}
+
+	if !server.oidcSignupAllowed(identity.Email) {
+		return "", errOIDCSignupDisabled
+	}
+	return server.provisionOIDCUser(ctx, identity)
+}
+
+// oidcSignupAllowed reports whether a new user can be created for the email,
+// only the domains listed in OIDC_SIGNUP_DOMAINS can sign up
+func (server *Server) oidcSignupAllowed(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+
+	domain := email[at+1:]
+	if len(domain) == 0 {
+		return false
+	}
+	for _, allowed := range server.config.OIDCSignupDomains {
+		if strings.EqualFold(strings.TrimSpace(allowed), domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// provisionOIDCUser creates the user of an identity, the password can not be guessed
+// so the user can only sign in with the provider until they reset it
+func (server *Server) provisionOIDCUser(ctx *gin.Context, identity oidc.Identity) (string, error) {
+	password, _, err := token.NewOpaqueToken("")
+	if err != nil {
+		return "", err
+	}
+
+	hashedPassword, err := server.passwordHasher.Hash(password)
+	if err != nil {
+		return "", err
+	}
+
+	role := server.config.OIDCDefaultRole
+	if len(role) == 0 {
+		role = util.AuthorRole
+	}
+
+	arg := db.ProvisionOIDCUserTxParams{
+		User: db.CreateUserParams{
+			Email:    identity.Email,
+			Password: hashedPassword,
+			Role:     role,
+		},
+		DisplayName: pgtype.Text{String: identity.Name, Valid: len(identity.Name) > 0},
+		Issuer:      server.oidc.Issuer(),
+		Subject:     identity.Subject,
+	}
+
+	username := oidcUsername(identity.Email)
+	for attempt := 0; ; attempt++ {
+		arg.User.Username = username
+		if attempt > 0 {
+			arg.User.Username += util.RandomString(4)
+		}
+
+		user, err := server.store.ProvisionOIDCUserTx(ctx, arg)
+		if err == nil {
+			return user.Username, nil
+		}
+		if db.ErrorCode(err) != db.UniqueViolation || attempt+1 == oidcUsernameAttempts {
+			return "", err
+		}
+	}
+}
+
+// oidcUsername derives a username from the local part of an email,
+// keeping only the characters allowed in usernames
+func oidcUsername(email string) string {
+	localPart, _, _ := strings.Cut(email, "@")
+
+	var username strings.Builder
+	for _, r := range strings.ToLower(localPart) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			username.WriteRune(r)
+		}
+		if username.Len() == oidcUsernameMaxLength {
+			break
+		}
+	}
+
+	if username.Len() == 0 {
+		return "user"
+	}
+	return username.String()
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JairoRiver/personal_blog_backend/pkg/oidc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestOIDCProvider discovers a provider that only serves its discovery document,
+// enough for the tests that do not complete a login with it
+func newTestOIDCProvider(t *testing.T) *oidc.Provider {
+	var issuer string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                 issuer,
+			"authorization_endpoint": issuer + "/authorize",
+			"token_endpoint":         issuer + "/token",
+			"jwks_uri":               issuer + "/keys",
+		})
+	}))
+	t.Cleanup(server.Close)
+	issuer = server.URL
+
+	provider, err := oidc.New(context.Background(), oidc.Config{IssuerURL: issuer, ClientID: util.RandomString(8)})
+	require.NoError(t, err)
+	return provider
+}
+
+func TestFindOIDCUserLinksVerifiedEmail(t *testing.T) {
+	store := newFakeStore()
+	server := newTestServer(t, store)
+	server.oidc = newTestOIDCProvider(t)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	unverified := randomUser(t, server, store, util.AuthorRole, util.RandomString(12))
+	verified := randomUser(t, server, store, util.AuthorRole, util.RandomString(12))
+	verified.EmailVerifiedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	store.putUser(verified)
+
+	// The user registered the email but never proved they own it
+	identity := oidc.Identity{Subject: util.RandomString(12), Email: unverified.Email, EmailVerified: true}
+	_, err := server.findOIDCUser(ctx, identity)
+	require.ErrorIs(t, err, errOIDCLinkUnverified)
+	require.Empty(t, store.identities)
+
+	identity = oidc.Identity{Subject: util.RandomString(12), Email: verified.Email, EmailVerified: true}
+	username, err := server.findOIDCUser(ctx, identity)
+	require.NoError(t, err)
+	require.Equal(t, verified.Username, username)
+	require.Len(t, store.identities, 1)
+
+	// The linked identity is found without the email
+	identity.Email = util.RandomString(8) + "@example.com"
+	username, err = server.findOIDCUser(ctx, identity)
+	require.NoError(t, err)
+	require.Equal(t, verified.Username, username)
+}
@@ -0,0 +1,494 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/passkey"
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	registrationCeremony = "registration"
+	loginCeremony        = "login"
+)
+
+var (
+	errPasskeysDisabled      = errors.New("passkeys are not configured")
+	errInvalidPasskeySession = errors.New("invalid or expired passkey challenge")
+)
+
+type passkeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func newPasskeyResponse(credential db.WebauthnCredential) passkeyResponse {
+	response := passkeyResponse{
+		ID:        credential.ID,
+		Name:      credential.Name,
+		CreatedAt: credential.CreatedAt,
+	}
+	if credential.LastUsedAt.Valid {
+		response.LastUsedAt = &credential.LastUsedAt.Time
+	}
+	return response
+}
+
+// passkeysMiddleware creates a gin middleware that rejects the passkey routes when passkeys are not configured
+func passkeysMiddleware(passkeys *passkey.WebAuthn) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if passkeys == nil {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, errorResponse(errPasskeysDisabled))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// loadPasskeyUser returns a user with its registered passkeys
+func (server *Server) loadPasskeyUser(ctx *gin.Context, userID uuid.UUID, username string) (*passkey.User, error) {
+	rows, err := server.store.ListWebAuthnCredentials(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &passkey.User{
+		ID:          userID,
+		Name:        username,
+		Credentials: make([]webauthn.Credential, 0, len(rows)),
+	}
+	for _, row := range rows {
+		var credential webauthn.Credential
+		if err := json.Unmarshal(row.Credential, &credential); err != nil {
+			return nil, err
+		}
+		user.Credentials = append(user.Credentials, credential)
+	}
+	return user, nil
+}
+
+// consumePasskeySession returns the session data of a ceremony, it can only be used once
+func (server *Server) consumePasskeySession(ctx *gin.Context, challengeID string, ceremony string) (db.WebauthnSession, error) {
+	id, err := uuid.Parse(challengeID)
+	if err != nil {
+		return db.WebauthnSession{}, errInvalidPasskeySession
+	}
+
+	session, err := server.store.ConsumeWebAuthnSession(ctx, db.ConsumeWebAuthnSessionParams{
+		ID:       id,
+		Ceremony: ceremony,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return session, errInvalidPasskeySession
+		}
+		return session, err
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		return session, errInvalidPasskeySession
+	}
+	return session, nil
+}
+
+type beginPasskeyRegistrationResponse struct {
+	ChallengeID uuid.UUID                    `json:"challenge_id"`
+	Options     *protocol.CredentialCreation `json:"options"`
+}
+
+// beginPasskeyRegistration godoc
+//
+//	@Summary					Begin Passkey Registration
+//	@Description				Start the registration of a passkey for the logged user, the options are passed to navigator.credentials.create
+//	@Tags						passkey,create
+//	@Produce					json
+//	@Success					200	{object}	beginPasskeyRegistrationResponse
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/me/passkeys/register/begin [post]
+func (server *Server) beginPasskeyRegistration(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	account, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	user, err := server.loadPasskeyUser(ctx, account.ID, account.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	options, sessionData, err := server.passkeys.BeginRegistration(user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	session, err := server.store.CreateWebAuthnSession(ctx, db.CreateWebAuthnSessionParams{
+		UserID:      pgtype.UUID{Bytes: account.ID, Valid: true},
+		Ceremony:    registrationCeremony,
+		SessionData: sessionData,
+		ExpiresAt:   time.Now().Add(passkey.CeremonyTimeout),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, beginPasskeyRegistrationResponse{ChallengeID: session.ID, Options: options})
+}
+
+// finishPasskeyRegistration handler
+type finishPasskeyRegistrationRequest struct {
+	ChallengeID string          `json:"challenge_id" binding:"required,uuid"`
+	Name        string          `json:"name" binding:"required,max=64"`
+	Credential  json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
+}
+
+// finishPasskeyRegistration godoc
+//
+//	@Summary					Finish Passkey Registration
+//	@Description				Verify the response of navigator.credentials.create and store the new passkey
+//	@Tags						passkey,create
+//	@Accept						json
+//	@Produce					json
+//	@Success					200		{object}	passkeyResponse
+//
+//	@Param						passkey	body		finishPasskeyRegistrationRequest	true	"Challenge and Credential"
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/me/passkeys/register/finish [post]
+func (server *Server) finishPasskeyRegistration(ctx *gin.Context) {
+	var req finishPasskeyRegistrationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	account, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	session, err := server.consumePasskeySession(ctx, req.ChallengeID, registrationCeremony)
+	if err != nil {
+		if errors.Is(err, errInvalidPasskeySession) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if session.UserID.Bytes != account.ID {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidPasskeySession))
+		return
+	}
+
+	user, err := server.loadPasskeyUser(ctx, account.ID, account.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	credential, err := server.passkeys.FinishRegistration(user, session.SessionData, req.Credential)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	credentialData, err := json.Marshal(credential)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	stored, err := server.store.CreateWebAuthnCredential(ctx, db.CreateWebAuthnCredentialParams{
+		UserID:       account.ID,
+		Name:         req.Name,
+		CredentialID: credential.ID,
+		Credential:   credentialData,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPasskeyResponse(stored))
+}
+
+// listPasskeys godoc
+//
+//	@Summary					List Passkeys
+//	@Description				Recive the passkeys of the logged user
+//	@Tags						passkey,list
+//	@Produce					json
+//	@Success					200	{array}	passkeyResponse
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/me/passkeys [get]
+func (server *Server) listPasskeys(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	account, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	credentials, err := server.store.ListWebAuthnCredentials(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]passkeyResponse, 0, len(credentials))
+	for _, credential := range credentials {
+		response = append(response, newPasskeyResponse(credential))
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// delete Passkey handler
+type deletePasskeyRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// deletePasskey godoc
+//
+//	@Summary					Delete Passkey
+//	@Description				Delete a passkey of the logged user
+//	@Tags						passkey,delete
+//	@Produce					json
+//	@Param						id	path		string	true	"id"
+//	@Success					200	{object}	uuid.UUID
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/me/passkeys/{id} [delete]
+func (server *Server) deletePasskey(ctx *gin.Context) {
+	var req deletePasskeyRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	passkeyID, err := uuid.Parse(req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	account, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	deleted, err := server.store.DeleteWebAuthnCredential(ctx, db.DeleteWebAuthnCredentialParams{
+		ID:     passkeyID,
+		UserID: account.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if deleted == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(db.ErrRecordNotFound))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, passkeyID)
+}
+
+// beginPasskeyLogin handler
+type beginPasskeyLoginRequest struct {
+	Username string `json:"username" binding:"omitempty,alphanum"`
+}
+
+type beginPasskeyLoginResponse struct {
+	ChallengeID uuid.UUID                     `json:"challenge_id"`
+	Options     *protocol.CredentialAssertion `json:"options"`
+}
+
+// beginPasskeyLogin godoc
+//
+//	@Summary		Begin Passkey Login
+//	@Description	Start a passkey login, the options are passed to navigator.credentials.get.
+//	@Description	Without a username any passkey stored in the authenticator can be used.
+//	@Tags			passkey,login
+//	@Accept			json
+//	@Produce		json
+//	@Success		200		{object}	beginPasskeyLoginResponse
+//
+//	@Param			user	body		beginPasskeyLoginRequest	false	"Username"
+//	@Router			/login/passkey/begin [post]
+func (server *Server) beginPasskeyLogin(ctx *gin.Context) {
+	var req beginPasskeyLoginRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	// Unknown usernames and users without passkeys get a discoverable login,
+	// so the response does not reveal which usernames exist
+	var user *passkey.User
+	var userID pgtype.UUID
+	if len(req.Username) > 0 {
+		account, err := server.store.GetUserByUsername(ctx, req.Username)
+		if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		if err == nil {
+			found, err := server.loadPasskeyUser(ctx, account.ID, account.Username)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+
+			if len(found.Credentials) > 0 {
+				user = found
+				userID = pgtype.UUID{Bytes: account.ID, Valid: true}
+			}
+		}
+	}
+
+	options, sessionData, err := server.passkeys.BeginLogin(user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	session, err := server.store.CreateWebAuthnSession(ctx, db.CreateWebAuthnSessionParams{
+		UserID:      userID,
+		Ceremony:    loginCeremony,
+		SessionData: sessionData,
+		ExpiresAt:   time.Now().Add(passkey.CeremonyTimeout),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, beginPasskeyLoginResponse{ChallengeID: session.ID, Options: options})
+}
+
+// finishPasskeyLogin handler
+type finishPasskeyLoginRequest struct {
+	ChallengeID string          `json:"challenge_id" binding:"required,uuid"`
+	Credential  json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
+}
+
+// finishPasskeyLogin godoc
+//
+//	@Summary		Finish Passkey Login
+//	@Description	Verify the response of navigator.credentials.get and return access token a refresh token
+//	@Tags			passkey,login
+//	@Accept			json
+//	@Produce		json
+//	@Success		200		{object}	loginUserResponse
+//
+//	@Param			passkey	body		finishPasskeyLoginRequest	true	"Challenge and Credential"
+//	@Router			/login/passkey/finish [post]
+func (server *Server) finishPasskeyLogin(ctx *gin.Context) {
+	var req finishPasskeyLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	session, err := server.consumePasskeySession(ctx, req.ChallengeID, loginCeremony)
+	if err != nil {
+		if errors.Is(err, errInvalidPasskeySession) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	var stored db.WebauthnCredential
+	var account db.GetUserRow
+	findUser := func(credentialID, userHandle []byte) (*passkey.User, error) {
+		var err error
+		stored, err = server.store.GetWebAuthnCredentialByCredentialID(ctx, credentialID)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				return nil, passkey.ErrUnknownCredential
+			}
+			return nil, err
+		}
+
+		account, err = server.store.GetUser(ctx, stored.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		return server.loadPasskeyUser(ctx, account.ID, account.Username)
+	}
+
+	_, credential, err := server.passkeys.FinishLogin(session.SessionData, req.Credential, findUser)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	credentialData, err := json.Marshal(credential)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = server.store.UpdateWebAuthnCredentialUse(ctx, db.UpdateWebAuthnCredentialUseParams{
+		ID:         stored.ID,
+		Credential: credentialData,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp, err := server.createLoginSession(ctx, account.ID, account.Username, account.Role)
+	if err != nil {
+		if errors.Is(err, errUserDisabled) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"log"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// hashNewPassword checks a password that is about to be set against the password policy and hashes it,
+// the policy errors wrap util.ErrWeakPassword
+func (server *Server) hashNewPassword(password string) (string, error) {
+	err := server.passwordPolicy.Validate(password)
+	if err != nil {
+		return "", err
+	}
+
+	return server.passwordHasher.Hash(password)
+}
+
+// rehashPassword upgrades a bcrypt hash or an argon2id hash with outdated parameters after a successful login,
+// a failure is only logged because the old hash keeps working. The hash is only replaced while it is still
+// the one that was checked, so a password changed by a concurrent request is not overwritten with the old one.
+func (server *Server) rehashPassword(ctx *gin.Context, userID uuid.UUID, password string, hashedPassword string) {
+	if !server.passwordHasher.NeedsRehash(hashedPassword) {
+		return
+	}
+
+	newHash, err := server.passwordHasher.Hash(password)
+	if err != nil {
+		log.Println("cannot rehash password:", err)
+		return
+	}
+
+	_, err = server.store.RehashUserPassword(ctx, db.RehashUserPasswordParams{
+		ID:          userID,
+		OldPassword: hashedPassword,
+		NewPassword: newHash,
+	})
+	if err != nil {
+		log.Println("cannot update rehashed password:", err)
+	}
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/mail"
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultResetTokenDuration = time.Hour
+	// passwordResetInterval is the minimum time between two reset emails to the same user
+	passwordResetInterval = time.Minute
+)
+
+var errInvalidResetToken = errors.New("invalid or expired reset token")
+
+// forgotPassword handler
+type forgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// forgotPassword godoc
+//
+//	@Summary		Forgot Password
+//	@Description	Send an email with a link to reset the password.
+//	@Description	The response is the same whether the email belongs to a user or not.
+//	@Tags			user,password
+//	@Accept			json
+//	@Produce		json
+//	@Success		200		{object}	string
+//
+//	@Param			user	body		forgotPasswordRequest	true	"User Email"
+//	@Router			/password/forgot [post]
+func (server *Server) forgotPassword(ctx *gin.Context) {
+	var req forgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	const rsp = "if the email belongs to an account, a reset link was sent to it"
+
+	user, err := server.store.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusOK, rsp)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if user.DisabledAt.Valid {
+		ctx.JSON(http.StatusOK, rsp)
+		return
+	}
+
+	recent, err := server.store.CountRecentPasswordResetTokens(ctx, db.CountRecentPasswordResetTokensParams{
+		UserID:       user.ID,
+		CreatedAfter: time.Now().Add(-passwordResetInterval),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if recent > 0 {
+		ctx.JSON(http.StatusOK, rsp)
+		return
+	}
+
+	resetToken, tokenHash, err := token.NewOpaqueToken("")
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	duration := server.config.ResetTokenDuration
+	if duration == 0 {
+		duration = defaultResetTokenDuration
+	}
+
+	_, err = server.store.CreatePasswordResetToken(ctx, db.CreatePasswordResetTokenParams{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(duration),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	resetURL := server.config.FrontendURL + "/reset-password?token=" + url.QueryEscape(resetToken)
+	server.sendMail(mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nSomeone asked to reset the password of your account. Open this link to choose a new one:\n\n%s\n\n"+
+				"The link expires in %s. If you did not ask for it, you can ignore this email.\n",
+			user.Username, resetURL, duration,
+		),
+	})
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// resetPassword handler
+type resetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// resetPassword godoc
+//
+//	@Summary		Reset Password
+//	@Description	Set a new password with a reset token, every session of the user is revoked
+//	@Tags			user,password
+//	@Accept			json
+//	@Produce		json
+//	@Success		200		{object}	string
+//
+//	@Param			user	body		resetPasswordRequest	true	"Token and New Password"
+//	@Router			/password/reset [post]
+func (server *Server) resetPassword(ctx *gin.Context) {
+	var req resetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	resetToken, err := server.store.GetPasswordResetTokenByHash(ctx, token.HashOpaqueToken(req.Token))
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidResetToken))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if resetToken.UsedAt.Valid || time.Now().After(resetToken.ExpiresAt) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidResetToken))
+		return
+	}
+
+	hashedPassword, err := server.hashNewPassword(req.Password)
+	if err != nil {
+		if errors.Is(err, util.ErrWeakPassword) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = server.store.ResetPasswordTx(ctx, db.ResetPasswordTxParams{
+		TokenID:        resetToken.ID,
+		UserID:         resetToken.UserID,
+		Username:       resetToken.Username,
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidResetToken))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	log.Printf("password of user %s was reset, its sessions were revoked\n", resetToken.Username)
+	ctx.JSON(http.StatusOK, resetToken.Username)
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestRehashPassword(t *testing.T) {
+	store := newFakeStore()
+	server := newTestServer(t, store)
+	password := util.RandomString(12)
+	user := randomUser(t, server, store, util.AuthorRole, password)
+
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+	user.Password = string(bcryptHash)
+	store.putUser(user)
+
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	// The password was changed after it was checked, the new one is kept
+	changedHash, err := server.passwordHasher.Hash(util.RandomString(12))
+	require.NoError(t, err)
+	changed := user
+	changed.Password = changedHash
+	store.putUser(changed)
+
+	server.rehashPassword(ctx, user.ID, password, user.Password)
+	require.Equal(t, changedHash, store.user(user.ID).Password)
+
+	// The checked hash is still stored, it is upgraded
+	store.putUser(user)
+	server.rehashPassword(ctx, user.ID, password, user.Password)
+
+	rehashed := store.user(user.ID).Password
+	require.NotEqual(t, user.Password, rehashed)
+	require.False(t, server.passwordHasher.NeedsRehash(rehashed))
+	require.NoError(t, server.passwordHasher.Check(password, rehashed))
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// createPersonalAccessToken handler
+type createPersonalAccessTokenRequest struct {
+	Name          string   `json:"name" binding:"required"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1"`
+}
+
+type personalAccessTokenResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type createPersonalAccessTokenResponse struct {
+	personalAccessTokenResponse
+	Token string `json:"token"`
+}
+
+func newPersonalAccessTokenResponse(pat db.PersonalAccessToken) personalAccessTokenResponse {
+	response := personalAccessTokenResponse{
+		ID:        pat.ID,
+		Name:      pat.Name,
+		Scopes:    pat.Scopes,
+		CreatedAt: pat.CreatedAt,
+	}
+	if pat.LastUsedAt.Valid {
+		response.LastUsedAt = &pat.LastUsedAt.Time
+	}
+	if pat.ExpiresAt.Valid {
+		response.ExpiresAt = &pat.ExpiresAt.Time
+	}
+	return response
+}
+
+// createPersonalAccessToken godoc
+//
+//	@Summary					Create a Personal Access Token
+//	@Description				Create a long-lived token for automation, the token is only returned once
+//	@Tags						token,create
+//	@Accept						json
+//	@Produce					json
+//	@Success					200		{object}	createPersonalAccessTokenResponse
+//
+//	@Param						token	body		createPersonalAccessTokenRequest	true	"Token Data"
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/tokens/personal [post]
+func (server *Server) createPersonalAccessToken(ctx *gin.Context) {
+	var req createPersonalAccessTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	for _, scope := range req.Scopes {
+		if !util.IsSupportedScope(scope) {
+			err := fmt.Errorf("unsupported scope %s", scope)
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	plainToken, tokenHash, err := token.NewOpaqueToken(token.PersonalAccessTokenPrefix)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	arg := db.CreatePersonalAccessTokenParams{
+		UserID:    user.ID,
+		Name:      req.Name,
+		TokenHash: tokenHash,
+		Scopes:    req.Scopes,
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		arg.ExpiresAt = pgtype.Timestamptz{Time: expiresAt, Valid: true}
+	}
+
+	pat, err := server.store.CreatePersonalAccessToken(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := createPersonalAccessTokenResponse{
+		personalAccessTokenResponse: newPersonalAccessTokenResponse(pat),
+		Token:                       plainToken,
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// listPersonalAccessTokens godoc
+//
+//	@Summary					List Personal Access Tokens
+//	@Description				Recive the personal access tokens of the logged user that are not revoked
+//	@Tags						token,list
+//	@Produce					json
+//	@Success					200	{array}	personalAccessTokenResponse
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/tokens/personal [get]
+func (server *Server) listPersonalAccessTokens(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	pats, err := server.store.ListPersonalAccessTokens(ctx, user.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]personalAccessTokenResponse, 0, len(pats))
+	for _, pat := range pats {
+		response = append(response, newPersonalAccessTokenResponse(pat))
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// revoke Personal Access Token handler
+type revokePersonalAccessTokenRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// revokePersonalAccessToken godoc
+//
+//	@Summary					Revoke Personal Access Token
+//	@Description				Revoke a personal access token of the logged user
+//	@Tags						token,delete
+//	@Produce					json
+//	@Param						id	path		string	true	"id"
+//	@Success					200	{object}	uuid.UUID
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/tokens/personal/{id} [delete]
+func (server *Server) revokePersonalAccessToken(ctx *gin.Context) {
+	var req revokePersonalAccessTokenRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	patID, err := uuid.Parse(req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	revoked, err := server.store.RevokePersonalAccessToken(ctx, db.RevokePersonalAccessTokenParams{
+		ID:     patID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if revoked == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(db.ErrRecordNotFound))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, patID)
+}
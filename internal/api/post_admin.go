@@ -2,9 +2,13 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 
 	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/render"
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -12,17 +16,24 @@ import (
 
 // createPost handler
 type createPostRequest struct {
-	Title      string `json:"title" binding:"required"`
-	Subtitle   string `json:"subtitle" binding:"required"`
-	Content    string `json:"content" binding:"required"`
-	Publicated bool   `json:"publicated"`
-	CategoryId string `json:"category_id" binding:"required,uuid"`
+	Title    string `json:"title" binding:"required"`
+	Slug     string `json:"slug"`
+	Subtitle string `json:"subtitle" binding:"required"`
+	Content  string `json:"content" binding:"required"`
+	// ContentFormat is the format of the content, markdown by default
+	ContentFormat string   `json:"content_format" binding:"omitempty,oneof=markdown html"`
+	Publicated    bool     `json:"publicated"`
+	CategoryId    string   `json:"category_id" binding:"required,uuid"`
+	CoAuthors     []string `json:"co_authors" binding:"omitempty,max=10,dive,alphanum"`
 }
 
 // createPost godoc
 //
 //	@Summary					Create a new Post
-//	@Description				Create a new Post
+//	@Description				Create a new Post, the logged user is its author and the co-authors are usernames.
+//	@Description				Without a slug it is made from the title.
+//	@Description				The content is rendered to sanitized HTML with a table of contents,
+//	@Description				the unknown or invalid shortcodes are kept as text and listed in render_warnings.
 //	@Tags						post,create
 //	@Accept						json
 //	@Produce					json
@@ -47,15 +58,70 @@ func (server *Server) createPost(ctx *gin.Context) {
 		return
 	}
 
-	arg := db.CreatePostParams{
-		CategoryID: category_id,
-		Title:      req.Title,
-		Subtitle:   req.Subtitle,
-		Content:    req.Content,
-		Publicated: req.Publicated,
+	if req.Publicated && !server.ensureEmailVerified(ctx) {
+		return
 	}
 
-	post, err := server.store.CreatePost(ctx, arg)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	author, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slug := req.Slug
+	if len(slug) > 0 {
+		if !util.IsSlug(slug) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidSlug))
+			return
+		}
+	} else {
+		slug, err = server.uniquePostSlug(ctx, req.Title, uuid.Nil)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	if len(req.ContentFormat) == 0 {
+		req.ContentFormat = render.FormatMarkdown
+	}
+
+	rendered, err := server.renderPost(ctx, req.ContentFormat, req.Content)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	coAuthorIDs, err := server.resolveCoAuthors(ctx, author.ID, req.CoAuthors)
+	if err != nil {
+		if errors.Is(err, errUnknownCoAuthor) || errors.Is(err, errAuthorIsCoAuthor) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	arg := db.CreatePostTxParams{
+		Post: db.CreatePostParams{
+			CategoryID:     category_id,
+			Title:          req.Title,
+			Slug:           slug,
+			Subtitle:       req.Subtitle,
+			Content:        req.Content,
+			ContentFormat:  req.ContentFormat,
+			ContentHtml:    rendered.HTML,
+			ContentToc:     rendered.TOC,
+			RenderVersion:  render.Version,
+			RenderWarnings: rendered.Warnings,
+			Publicated:     req.Publicated,
+			AuthorID:       pgtype.UUID{Bytes: author.ID, Valid: true},
+		},
+		CoAuthorIDs: coAuthorIDs,
+	}
+
+	post, err := server.store.CreatePostTx(ctx, arg)
 	if err != nil {
 		if db.ErrorCode(err) == db.UniqueViolation {
 			ctx.JSON(http.StatusForbidden, errorResponse(err))
@@ -247,17 +313,23 @@ func (server *Server) listPostsPrivate(ctx *gin.Context) {
 
 // updatePost handler
 type updatePostRequest struct {
-	Title      pgtype.Text `json:"title"`
-	Subtitle   pgtype.Text `json:"subtitle"`
-	Content    pgtype.Text `json:"content"`
-	Publicated pgtype.Bool `json:"publicated"`
-	CategoryId pgtype.UUID `json:"category_id"`
+	Title         pgtype.Text `json:"title"`
+	Slug          pgtype.Text `json:"slug"`
+	Subtitle      pgtype.Text `json:"subtitle"`
+	Content       pgtype.Text `json:"content"`
+	ContentFormat pgtype.Text `json:"content_format"`
+	Publicated    pgtype.Bool `json:"publicated"`
+	CategoryId    pgtype.UUID `json:"category_id"`
+	// CoAuthors replaces the co-authors when it is sent, an empty list removes them
+	CoAuthors *[]string `json:"co_authors" binding:"omitempty,max=10,dive,alphanum"`
 }
 
 // updatePost godoc
 //
 //	@Summary					Update a Post
-//	@Description				Update a new Post
+//	@Description				Update a Post, authors can only update their own posts while editors can update any post.
+//	@Description				Only the main author or an editor can change the co-authors.
+//	@Description				A new title makes a new slug unless a slug is sent, the previous slugs redirect to the post.
 //	@Tags						post,update
 //	@Accept						json
 //	@Produce					json
@@ -289,27 +361,82 @@ func (server *Server) updatePost(ctx *gin.Context) {
 		return
 	}
 
+	editor, ok := server.ensureCanEditPost(ctx, post_id)
+	if !ok {
+		return
+	}
+
 	// Validate the input parameters
 	arg := db.UpdatePostParams{
-		ID: post_id,
+		ID:           post_id,
+		LastEditedBy: pgtype.UUID{Bytes: editor.ID, Valid: true},
 	}
 	// Title
 	if req.Title.Valid {
 		arg.Title = req.Title
 	}
 
+	// Slug
+	if req.Slug.Valid {
+		if !util.IsSlug(req.Slug.String) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidSlug))
+			return
+		}
+		arg.Slug = req.Slug
+	} else if req.Title.Valid {
+		slug, err := server.uniquePostSlug(ctx, req.Title.String, post_id)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		arg.Slug = pgtype.Text{String: slug, Valid: true}
+	}
+
 	// Subtitle
 	if req.Subtitle.Valid {
 		arg.Subtitle = req.Subtitle
 	}
 
-	// Content
-	if req.Content.Valid {
+	// Content, it is rendered again when the content or its format change
+	if req.Content.Valid || req.ContentFormat.Valid {
+		if req.ContentFormat.Valid && !render.IsSupportedFormat(req.ContentFormat.String) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(render.ErrUnsupportedFormat))
+			return
+		}
+
+		if !req.Content.Valid || !req.ContentFormat.Valid {
+			post, err := server.store.GetPostByIdPrivate(ctx, post_id)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+			if !req.Content.Valid {
+				req.Content = pgtype.Text{String: post.Content, Valid: true}
+			}
+			if !req.ContentFormat.Valid {
+				req.ContentFormat = pgtype.Text{String: post.ContentFormat, Valid: true}
+			}
+		}
+
+		rendered, err := server.renderPost(ctx, req.ContentFormat.String, req.Content.String)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
 		arg.Content = req.Content
+		arg.ContentFormat = req.ContentFormat
+		arg.ContentHtml = pgtype.Text{String: rendered.HTML, Valid: true}
+		arg.ContentToc = rendered.TOC
+		arg.RenderVersion = pgtype.Int4{Int32: render.Version, Valid: true}
+		arg.RenderWarnings = rendered.Warnings
 	}
 
 	// Publicated
 	if req.Publicated.Valid {
+		if req.Publicated.Bool && !server.ensureEmailVerified(ctx) {
+			return
+		}
 		arg.Publicated = req.Publicated
 	}
 
@@ -318,7 +445,31 @@ func (server *Server) updatePost(ctx *gin.Context) {
 		arg.CategoryID = req.CategoryId
 	}
 
-	post, err := server.store.UpdatePost(ctx, arg)
+	txArg := db.UpdatePostTxParams{
+		Post: arg,
+	}
+
+	// CoAuthors
+	if req.CoAuthors != nil {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		if !editor.MainAuthor && !util.HasRole(authPayload.Role, util.EditorRole) {
+			ctx.JSON(http.StatusForbidden, errorResponse(errNotPostMainAuthor))
+			return
+		}
+
+		txArg.ReplaceCoAuthors = true
+		txArg.CoAuthorIDs, err = server.resolveCoAuthors(ctx, editor.PostAuthorID, *req.CoAuthors)
+		if err != nil {
+			if errors.Is(err, errUnknownCoAuthor) || errors.Is(err, errAuthorIsCoAuthor) {
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	post, err := server.store.UpdatePostTx(ctx, txArg)
 	if err != nil {
 		if db.ErrorCode(err) == db.UniqueViolation {
 			ctx.JSON(http.StatusForbidden, errorResponse(err))
@@ -327,6 +478,8 @@ func (server *Server) updatePost(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
+
+	server.refreshLinkingPosts(post.ID)
 	ctx.JSON(http.StatusOK, post)
 }
 
@@ -376,5 +529,6 @@ func (server *Server) deletePost(ctx *gin.Context) {
 		return
 	}
 
+	server.refreshLinkingPosts(postID)
 	ctx.JSON(http.StatusOK, postID)
 }
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var (
+	errNotPostAuthor     = errors.New("only the authors of the post or an editor can change it")
+	errNotPostMainAuthor = errors.New("only the main author of the post or an editor can change its co-authors")
+	errAuthorIsCoAuthor  = errors.New("the author of the post can not be its co-author")
+	errUnknownCoAuthor   = errors.New("unknown co-author")
+)
+
+// postEditor is the logged user that is changing a post
+type postEditor struct {
+	ID         uuid.UUID
+	MainAuthor bool
+	// PostAuthorID is the main author of the post, it is the zero uuid when the post has none
+	PostAuthorID uuid.UUID
+}
+
+// ensureCanEditPost responds with 404 or 403 and returns false when the logged user is not an author
+// of the post, editors and admins can edit every post
+func (server *Server) ensureCanEditPost(ctx *gin.Context, postID uuid.UUID) (postEditor, bool) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return postEditor{}, false
+	}
+
+	access, err := server.store.GetPostAccess(ctx, db.GetPostAccessParams{
+		PostID: postID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return postEditor{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return postEditor{}, false
+	}
+
+	editor := postEditor{
+		ID:         user.ID,
+		MainAuthor: access.AuthorID.Valid && access.AuthorID.Bytes == user.ID,
+	}
+	if access.AuthorID.Valid {
+		editor.PostAuthorID = access.AuthorID.Bytes
+	}
+
+	if !editor.MainAuthor && !access.IsCoAuthor && !util.HasRole(authPayload.Role, util.EditorRole) {
+		ctx.JSON(http.StatusForbidden, errorResponse(errNotPostAuthor))
+		return postEditor{}, false
+	}
+	return editor, true
+}
+
+// resolveCoAuthors returns the ids of the co-authors usernames, the author of the post can not be one of them
+func (server *Server) resolveCoAuthors(ctx *gin.Context, authorID uuid.UUID, usernames []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(usernames))
+	for _, username := range usernames {
+		user, err := server.store.GetUserByUsername(ctx, username)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%w %s", errUnknownCoAuthor, username)
+			}
+			return nil, err
+		}
+
+		if user.ID == authorID {
+			return nil, errAuthorIsCoAuthor
+		}
+		ids = append(ids, user.ID)
+	}
+	return ids, nil
+}
@@ -2,9 +2,12 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
+	"net/url"
+	"strings"
 
-	_ "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -162,3 +165,92 @@ func (server *Server) listPostsPublic(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, posts)
 }
+
+// get Posts By Author Public handler
+type getPostsByAuthorPublicRequest struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+// getPostsByAuthorPublic godoc
+//
+//	@Summary		List Posts by Author
+//	@Description	Recive the posts publics written or co-written by an author
+//	@Tags			post,author,list
+//	@Produce		json
+//	@Success		200			{object}	db.GetPostsByAuthorPublicRow
+//
+//	@Param			username	path		string	true	"username"
+//	@Router			/authors/{username}/posts [get]
+func (server *Server) getPostsByAuthorPublic(ctx *gin.Context) {
+	var req getPostsByAuthorPublicRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, err := server.store.GetAuthorByUsername(ctx, req.Username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	posts, err := server.store.GetPostsByAuthorPublic(ctx, req.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, posts)
+}
+
+// get Post By Slug Public handler
+type getPostBySlugPublicRequest struct {
+	Slug string `uri:"slug" binding:"required"`
+}
+
+// getPostBySlugPublic godoc
+//
+//	@Summary		Get a Post by Slug Public
+//	@Description	Recive the one post public by its slug, a previous slug of the post redirects to the current one with 302
+//	@Tags			post,get
+//	@Produce		json
+//	@Success		200		{object}	db.GetPostBySlugPublicRow
+//
+//	@Param			slug	path		string	true	"slug"
+//	@Router			/posts/by-slug/{slug} [get]
+func (server *Server) getPostBySlugPublic(ctx *gin.Context) {
+	var req getPostBySlugPublicRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	post, err := server.store.GetPostBySlugPublic(ctx, req.Slug)
+	if err == nil {
+		ctx.JSON(http.StatusOK, post)
+		return
+	}
+	if !errors.Is(err, db.ErrRecordNotFound) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	slug, err := server.store.GetPostSlugRedirectPublic(ctx, req.Slug)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	location := strings.Replace(ctx.FullPath(), ":slug", url.PathEscape(slug), 1)
+	// A previous slug can be taken by another post later, so the redirect is temporary and only cached for a while
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.Redirect(http.StatusFound, location)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPostBySlugPublic(t *testing.T) {
+	store := newFakeStore()
+	server := newTestServer(t, store)
+
+	post := db.GetPostBySlugPublicRow{
+		ID:         uuid.New(),
+		Title:      util.RandomString(12),
+		Slug:       "current-slug",
+		Publicated: true,
+	}
+	store.putPost(post, "previous-slug")
+	store.putPost(db.GetPostBySlugPublicRow{ID: uuid.New(), Slug: "draft-slug"}, "previous-draft-slug")
+
+	testCases := []struct {
+		name          string
+		slug          string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "CurrentSlug",
+			slug: "current-slug",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.GetPostBySlugPublicRow
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, post.ID, got.ID)
+				require.Equal(t, post.Title, got.Title)
+			},
+		},
+		{
+			name: "PreviousSlug",
+			slug: "previous-slug",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusFound, recorder.Code)
+				require.Equal(t, "/v1/posts/by-slug/current-slug", recorder.Header().Get("Location"))
+				require.Equal(t, "public, max-age=300", recorder.Header().Get("Cache-Control"))
+			},
+		},
+		{
+			name: "UnknownSlug",
+			slug: "unknown-slug",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "UnpublishedPost",
+			slug: "draft-slug",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "PreviousSlugOfUnpublishedPost",
+			slug: "previous-draft-slug",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := http.NewRequest(http.MethodGet, "/v1/posts/by-slug/"+tc.slug, nil)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
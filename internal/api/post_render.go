@@ -0,0 +1,216 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/render"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// staleRenderBatchSize is how many posts are rendered again per query when the renderer changes
+	staleRenderBatchSize = 100
+	// postURLPath is the path of the posts in the frontend, followed by their slug
+	postURLPath = "/posts/"
+)
+
+// renderedPost is the cached output of the renderer that is stored with a post
+type renderedPost struct {
+	HTML     string
+	TOC      json.RawMessage
+	Warnings json.RawMessage
+}
+
+// renderPost renders the content of a post for storing it
+func (server *Server) renderPost(ctx context.Context, format string, content string) (renderedPost, error) {
+	document, err := server.renderer.Render(ctx, format, content)
+	if err != nil {
+		return renderedPost{}, err
+	}
+
+	toc, err := json.Marshal(document.TOC)
+	if err != nil {
+		return renderedPost{}, err
+	}
+
+	warnings, err := json.Marshal(document.Warnings)
+	if err != nil {
+		return renderedPost{}, err
+	}
+
+	return renderedPost{HTML: document.HTML, TOC: toc, Warnings: warnings}, nil
+}
+
+// postResolver resolves the links of the post shortcode to the current slug of the posts
+type postResolver struct {
+	store       db.Store
+	frontendURL string
+}
+
+func (resolver postResolver) ResolvePost(ctx context.Context, id uuid.UUID) (render.PostLink, error) {
+	post, err := resolver.store.GetPostLink(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return render.PostLink{}, render.ErrPostNotFound
+		}
+		return render.PostLink{}, err
+	}
+
+	return render.PostLink{
+		Title:     post.Title,
+		URL:       resolver.frontendURL + postURLPath + url.PathEscape(post.Slug),
+		Published: post.Publicated,
+	}, nil
+}
+
+// previewPost handler
+type previewPostRequest struct {
+	Content       string `json:"content"`
+	ContentFormat string `json:"content_format" binding:"omitempty,oneof=markdown html"`
+}
+
+type previewPostResponse struct {
+	ContentHtml    string           `json:"content_html"`
+	ContentToc     []render.Heading `json:"content_toc"`
+	RenderWarnings []render.Warning `json:"render_warnings"`
+}
+
+// previewPost godoc
+//
+//	@Summary					Preview a Post
+//	@Description				Render the content of a post without saving it, the default format is markdown.
+//	@Description				The unknown or invalid shortcodes are returned as warnings.
+//	@Tags						post,create
+//	@Accept						json
+//	@Produce					json
+//	@Success					200		{object}	previewPostResponse
+//
+//	@Param						post	body		previewPostRequest	true	"post Content"
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/admin/post/preview [post]
+func (server *Server) previewPost(ctx *gin.Context) {
+	var req previewPostRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if len(req.ContentFormat) == 0 {
+		req.ContentFormat = render.FormatMarkdown
+	}
+
+	document, err := server.renderer.Render(ctx, req.ContentFormat, req.Content)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, previewPostResponse{
+		ContentHtml:    document.HTML,
+		ContentToc:     document.TOC,
+		RenderWarnings: document.Warnings,
+	})
+}
+
+// listPostRenderWarnings godoc
+//
+//	@Summary					List the Render Warnings of the Posts
+//	@Description				Return the posts whose content has unknown or invalid shortcodes with their warnings
+//	@Tags						post,list
+//	@Produce					json
+//	@Success					200	{object}	db.ListPostRenderWarningsRow
+//
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//
+//	@Router						/admin/posts/render-warnings [get]
+func (server *Server) listPostRenderWarnings(ctx *gin.Context) {
+	posts, err := server.store.ListPostRenderWarnings(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, posts)
+}
+
+// refreshLinkingPosts renders again in the background the posts that link to a post
+// with the post shortcode, so their links follow its new slug, title or state
+func (server *Server) refreshLinkingPosts(postID uuid.UUID) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		marked, err := server.store.MarkLinkingPostsStale(ctx, postID)
+		if err != nil {
+			log.Printf("cannot mark the posts linking to %s: %v\n", postID, err)
+			return
+		}
+		if marked > 0 {
+			server.renderStalePosts(ctx)
+		}
+	}()
+}
+
+// renderStalePosts renders again the posts whose cached HTML was made by an older version of the renderer
+func (server *Server) renderStalePosts(ctx context.Context) {
+	// The posts would be rendered twice by concurrent runs
+	server.renderMutex.Lock()
+	defer server.renderMutex.Unlock()
+
+	rendered := 0
+	for {
+		posts, err := server.store.ListStaleRenderedPosts(ctx, db.ListStaleRenderedPostsParams{
+			RenderVersion: render.Version,
+			LimitCount:    staleRenderBatchSize,
+		})
+		if err != nil {
+			log.Println("cannot list the posts to render:", err)
+			return
+		}
+
+		for _, post := range posts {
+			output, err := server.renderPost(ctx, post.ContentFormat, post.Content)
+			if err != nil {
+				log.Printf("cannot render post %s: %v\n", post.ID, err)
+				return
+			}
+
+			// A post updated in the meantime was already rendered by the update
+			_, err = server.store.SetPostRendering(ctx, db.SetPostRenderingParams{
+				ID:             post.ID,
+				ContentHtml:    output.HTML,
+				ContentToc:     output.TOC,
+				RenderVersion:  render.Version,
+				RenderWarnings: output.Warnings,
+				UpdatedAt:      post.UpdatedAt,
+			})
+			if err != nil {
+				log.Printf("cannot save the rendering of post %s: %v\n", post.ID, err)
+				return
+			}
+			rendered++
+		}
+
+		if len(posts) < staleRenderBatchSize {
+			break
+		}
+	}
+
+	if rendered > 0 {
+		log.Printf("rendered %d posts again\n", rendered)
+	}
+}
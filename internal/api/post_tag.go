@@ -2,6 +2,7 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 
 	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
@@ -49,6 +50,10 @@ func (server *Server) createPostTag(ctx *gin.Context) {
 		return
 	}
 
+	if _, ok := server.ensureCanEditPost(ctx, post_id); !ok {
+		return
+	}
+
 	arg := db.CreatePostTagParams{
 		PostID: post_id,
 		TagID:  tag_id,
@@ -101,6 +106,20 @@ func (server *Server) deletePostTag(ctx *gin.Context) {
 		return
 	}
 
+	postTag, err := server.store.GetPostTag(ctx, postTagID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if _, ok := server.ensureCanEditPost(ctx, postTag.PostID); !ok {
+		return
+	}
+
 	err = server.store.DeletePostTag(ctx, postTagID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -1,7 +1,7 @@
 package api
 
 import (
-	"log"
+	"fmt"
 
 	"github.com/JairoRiver/personal_blog_backend/docs" // Swagger generated files
 	"github.com/JairoRiver/personal_blog_backend/pkg/util"
@@ -11,74 +11,164 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger" // gin-swagger middleware
 )
 
-func (server *Server) setupRouter() {
-	config, err := util.LoadConfig(".", "app")
-	if err != nil {
-		log.Fatal("cannot load config:", err)
-	}
-
+func (server *Server) setupRouter() error {
 	// Swagger 2.0 Meta Information
 	docs.SwaggerInfo.Title = "Personal Blog - API"
 	docs.SwaggerInfo.Description = "Personal Blog - Post and Users API"
 	docs.SwaggerInfo.Version = "1.0"
-	docs.SwaggerInfo.Host = config.HostName
+	docs.SwaggerInfo.Host = server.config.HostName
 	docs.SwaggerInfo.BasePath = "/v1"
 	//	@securityDefinitions.apiKey	JWT
 	//	@in							header
 	//	@name						authorization
 	docs.SwaggerInfo.Schemes = []string{"http", "https"}
 
-	router := gin.Default()
-
-	router.MaxMultipartMemory = 8 << 20 // 8 MiB
+	router, err := newRouter(server.trustedProxies)
+	if err != nil {
+		return err
+	}
 
 	//autRoutes := router.Group("/")
 	apiRoutes := router.Group(docs.SwaggerInfo.BasePath)
-	authRoutes := apiRoutes.Group("").Use(authMiddleware(server.tokenMaker))
+	authRoutes := apiRoutes.Group("").Use(ipAllowlistMiddleware(server.adminAllowlist), authMiddleware(server.tokenMaker, server.store), sessionMiddleware())
+
+	// The role protected routes are only served on the admin listener when it is configured
+	roleRoutes := apiRoutes.Group("")
+	if server.adminTLS != nil {
+		server.adminRouter, err = newRouter(server.trustedProxies)
+		if err != nil {
+			return err
+		}
+		roleRoutes = server.adminRouter.Group(docs.SwaggerInfo.BasePath, clientCertMiddleware())
+	}
+	authorRoutes := roleRoutes.Group("").Use(ipAllowlistMiddleware(server.adminAllowlist), authMiddleware(server.tokenMaker, server.store), roleMiddleware(util.AuthorRole))
+	editorRoutes := roleRoutes.Group("").Use(ipAllowlistMiddleware(server.adminAllowlist), authMiddleware(server.tokenMaker, server.store), roleMiddleware(util.EditorRole))
+	adminRoutes := roleRoutes.Group("").Use(ipAllowlistMiddleware(server.adminAllowlist), authMiddleware(server.tokenMaker, server.store), roleMiddleware(util.AdminRole))
 
 	// User routes
-	authRoutes.POST("/user", server.createUser)
-	authRoutes.GET("/user/:id", server.getUser)
-	authRoutes.GET("/users", server.listUsers)
-	authRoutes.PUT("/user/:id", server.updateUser)
-	authRoutes.DELETE("/user/:id", server.deleteUser)
+	adminRoutes.GET("/user/:id", scopeMiddleware(util.UsersAdminScope), server.getUser)
+	adminRoutes.GET("/users", scopeMiddleware(util.UsersAdminScope), server.listUsers)
+	adminRoutes.PUT("/user/:id", scopeMiddleware(util.UsersAdminScope), server.updateUser)
+	adminRoutes.DELETE("/user/:id", scopeMiddleware(util.UsersAdminScope), server.deleteUser)
+	adminRoutes.POST("/user/:id/enable", scopeMiddleware(util.UsersAdminScope), server.enableUser)
+	adminRoutes.DELETE("/user/:id/purge", scopeMiddleware(util.UsersAdminScope), server.purgeUser)
 	apiRoutes.POST("/login", server.loginUser)
+	apiRoutes.POST("/login/2fa", server.loginTOTP)
+	apiRoutes.POST("/tokens/renew_access", server.renewAccessToken)
+	apiRoutes.POST("/password/forgot", server.forgotPassword)
+	apiRoutes.POST("/password/reset", server.resetPassword)
+	apiRoutes.POST("/email/verify", server.verifyEmail)
+	authRoutes.POST("/me/email/verification", server.resendEmailVerification)
+
+	// Logged user routes
+	authRoutes.GET("/me", server.getMe)
+	authRoutes.PUT("/me", server.updateMe)
+	authRoutes.PUT("/me/password", server.changePassword)
+	authRoutes.PUT("/me/avatar", server.uploadAvatar)
+	authRoutes.DELETE("/me/avatar", server.deleteAvatar)
+
+	// Author routes
+	apiRoutes.GET("/authors", server.listAuthors)
+	apiRoutes.GET("/authors/:username", server.getAuthor)
+	apiRoutes.GET("/authors/:username/posts", server.getPostsByAuthorPublic)
+
+	// Invite routes
+	adminRoutes.POST("/invite", scopeMiddleware(util.UsersAdminScope), server.createInvite)
+	adminRoutes.GET("/invites", scopeMiddleware(util.UsersAdminScope), server.listInvites)
+	adminRoutes.DELETE("/invite/:id", scopeMiddleware(util.UsersAdminScope), server.revokeInvite)
+	apiRoutes.POST("/invite/accept", server.acceptInvite)
+
+	// Two-factor authentication routes
+	authRoutes.POST("/me/2fa/totp", server.enrollTOTP)
+	authRoutes.POST("/me/2fa/totp/verify", server.verifyTOTP)
+	authRoutes.DELETE("/me/2fa/totp", server.disableTOTP)
+
+	// Passkey routes
+	authRoutes.POST("/me/passkeys/register/begin", passkeysMiddleware(server.passkeys), server.beginPasskeyRegistration)
+	authRoutes.POST("/me/passkeys/register/finish", passkeysMiddleware(server.passkeys), server.finishPasskeyRegistration)
+	authRoutes.GET("/me/passkeys", server.listPasskeys)
+	authRoutes.DELETE("/me/passkeys/:id", server.deletePasskey)
+	apiRoutes.POST("/login/passkey/begin", passkeysMiddleware(server.passkeys), server.beginPasskeyLogin)
+	apiRoutes.POST("/login/passkey/finish", passkeysMiddleware(server.passkeys), server.finishPasskeyLogin)
+
+	// Single sign-on routes
+	apiRoutes.GET("/login/oidc", oidcMiddleware(server.oidc), server.beginOIDCLogin)
+	apiRoutes.POST("/login/oidc/callback", oidcMiddleware(server.oidc), server.finishOIDCLogin)
+
+	// Session routes
+	authRoutes.GET("/sessions", server.listSessions)
+	authRoutes.DELETE("/session/:id", server.revokeSession)
+	authRoutes.DELETE("/sessions", server.revokeAllSessions)
+	adminRoutes.DELETE("/user/:id/sessions", scopeMiddleware(util.UsersAdminScope), server.blockUserSessions)
+
+	// Personal access token routes
+	authRoutes.POST("/tokens/personal", server.createPersonalAccessToken)
+	authRoutes.GET("/tokens/personal", server.listPersonalAccessTokens)
+	authRoutes.DELETE("/tokens/personal/:id", server.revokePersonalAccessToken)
 
 	// Categories routes
-	authRoutes.POST("/category", server.createCategory)
+	editorRoutes.POST("/category", scopeMiddleware(util.CategoriesWriteScope), server.createCategory)
 	apiRoutes.GET("/category/:id", server.getCategory)
 	apiRoutes.GET("/categories", server.listCategories)
-	authRoutes.PUT("/category/:id", server.updateCategory)
-	authRoutes.DELETE("/category/:id", server.deleteCategory)
+	editorRoutes.PUT("/category/:id", scopeMiddleware(util.CategoriesWriteScope), server.updateCategory)
+	editorRoutes.DELETE("/category/:id", scopeMiddleware(util.CategoriesWriteScope), server.deleteCategory)
 
 	// Tags routes
-	authRoutes.POST("/tag", server.createTag)
+	editorRoutes.POST("/tag", scopeMiddleware(util.TagsWriteScope), server.createTag)
 	apiRoutes.GET("/tag/:id", server.getTag)
 	apiRoutes.GET("/tags", server.listTags)
-	authRoutes.DELETE("/tag/:id", server.deleteTag)
+	editorRoutes.PUT("/tag/:id", scopeMiddleware(util.TagsWriteScope), server.updateTag)
+	editorRoutes.DELETE("/tag/:id", scopeMiddleware(util.TagsWriteScope), server.deleteTag)
 
 	// Post routes admin
-	authRoutes.POST("/admin/post", server.createPost)
-	authRoutes.GET("/admin/post/:id", server.getPostByIdPrivate)
-	authRoutes.GET("/admin/category-post/:id", server.getPostByCategoryPrivate)
-	authRoutes.GET("/admin/tag-post/:id", server.getPostByTagPrivate)
-	authRoutes.GET("/admin/posts", server.listPostsPrivate)
-	authRoutes.PUT("/admin/post/{id}", server.updatePost)
-	authRoutes.DELETE("/admin/post/{id}", server.deletePost)
+	authorRoutes.POST("/admin/post", scopeMiddleware(util.PostsWriteScope), server.createPost)
+	authorRoutes.POST("/admin/post/preview", scopeMiddleware(util.PostsWriteScope), server.previewPost)
+	authorRoutes.GET("/admin/post/:id", scopeMiddleware(util.PostsReadScope), server.getPostByIdPrivate)
+	authorRoutes.GET("/admin/category-post/:id", scopeMiddleware(util.PostsReadScope), server.getPostByCategoryPrivate)
+	authorRoutes.GET("/admin/tag-post/:id", scopeMiddleware(util.PostsReadScope), server.getPostByTagPrivate)
+	authorRoutes.GET("/admin/posts", scopeMiddleware(util.PostsReadScope), server.listPostsPrivate)
+	authorRoutes.GET("/admin/posts/render-warnings", scopeMiddleware(util.PostsReadScope), server.listPostRenderWarnings)
+	authorRoutes.PUT("/admin/post/:id", scopeMiddleware(util.PostsWriteScope), server.updatePost)
+	editorRoutes.DELETE("/admin/post/:id", scopeMiddleware(util.PostsWriteScope), server.deletePost)
 
 	// Post routes public
 	apiRoutes.GET("/post/:id", server.getPostByIdPublic)
 	apiRoutes.GET("/category-post/:id", server.getPostByCategoryPublic)
 	apiRoutes.GET("tag-post/:id", server.getPostByTagPublic)
 	apiRoutes.GET("/posts", server.listPostsPublic)
+	apiRoutes.GET("/posts/by-slug/:slug", server.getPostBySlugPublic)
 
 	// PostTag routes
-	authRoutes.POST("/admin/post-tag", server.createPostTag)
-	apiRoutes.DELETE("/admin/post-tag/:id", server.deletePostTag)
+	authorRoutes.POST("/admin/post-tag", scopeMiddleware(util.PostsWriteScope), server.createPostTag)
+	authorRoutes.DELETE("/admin/post-tag/:id", scopeMiddleware(util.PostsWriteScope), server.deletePostTag)
+
+	// Highlight themes routes public
+	apiRoutes.GET("/highlight/themes", server.listHighlightThemes)
+	apiRoutes.GET("/highlight/themes/:theme/style.css", server.getHighlightTheme)
+	apiRoutes.GET("/highlight/style.css", server.getDefaultHighlightTheme)
+
+	// Public keys to verify the tokens
+	router.GET("/.well-known/jwks.json", server.getJWKS)
 
 	// swagger
 	url := ginSwagger.URL("http://localhost:8080/swagger/doc.json")
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, url))
 
 	server.router = router
+	return nil
+}
+
+// newRouter creates a gin engine that only reads the client IP from the trusted proxies,
+// so the IP allowlist and the login throttle can not be bypassed with a forged header
+func newRouter(trustedProxies []string) (*gin.Engine, error) {
+	router := gin.Default()
+
+	router.MaxMultipartMemory = 8 << 20 // 8 MiB
+
+	err := router.SetTrustedProxies(trustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+
+	return router, nil
 }
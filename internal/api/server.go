@@ -1,28 +1,64 @@
 package api
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log"
+	"net/netip"
+	"os"
+	"sync"
+	"time"
 
 	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
 	"github.com/JairoRiver/personal_blog_backend/pkg/assets"
+	"github.com/JairoRiver/personal_blog_backend/pkg/mail"
+	"github.com/JairoRiver/personal_blog_backend/pkg/oidc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/passkey"
+	"github.com/JairoRiver/personal_blog_backend/pkg/render"
 	"github.com/JairoRiver/personal_blog_backend/pkg/token"
 	"github.com/JairoRiver/personal_blog_backend/pkg/util"
 	"github.com/gin-gonic/gin"
 )
 
+// appName is the name shown to users by authenticator apps and passkey prompts
+const appName = "Personal Blog"
+
 // Server serves HTTP request for out bloging services
 type Server struct {
 	config     util.Config
-	store      db.Querier
+	store      db.Store
 	tokenMaker token.Maker
 	assetStore assets.ImageStorer
+	passkeys   *passkey.WebAuthn
+	oidc       *oidc.Provider
+	mailer     mail.Mailer
+	renderer   *render.Renderer
 	router     *gin.Engine
+
+	// renderMutex serializes the renderings of the stale posts
+	renderMutex sync.Mutex
+
+	// trustedProxies are the proxies whose forwarded headers set the client IP
+	trustedProxies []string
+	// adminAllowlist limits the client IPs of the authenticated routes
+	adminAllowlist []netip.Prefix
+	// adminRouter serves the role protected routes on their own listener
+	// with mutual TLS, it is nil when they are served by router
+	adminRouter *gin.Engine
+	adminTLS    *tls.Config
+
+	passwordHasher *util.PasswordHasher
+	passwordPolicy *util.PasswordPolicy
+	// dummyPasswordHash is checked when a username does not exist,
+	// so the response time does not reveal which usernames exist
+	dummyPasswordHash string
 }
 
 // NewServer creates a new HTTP server and set up routing.
-func NewServer(config util.Config, store db.Querier) (*Server, error) {
-	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+func NewServer(config util.Config, store db.Store) (*Server, error) {
+	tokenMaker, err := newTokenMaker(config)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
@@ -37,19 +73,189 @@ func NewServer(config util.Config, store db.Querier) (*Server, error) {
 		log.Panic("Can't create a new S3 session")
 	}
 
+	mailer, err := newMailer(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create mailer: %w", err)
+	}
+
+	passwordHasher, err := util.NewPasswordHasher(config.Argon2Params())
+	if err != nil {
+		return nil, fmt.Errorf("cannot create password hasher: %w", err)
+	}
+
+	passwordPolicy, err := util.NewPasswordPolicy(config.PasswordMinLength, config.PasswordDenylist)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create password policy: %w", err)
+	}
+
+	dummyPasswordHash, err := passwordHasher.Hash(util.RandomString(16))
+	if err != nil {
+		return nil, fmt.Errorf("cannot hash dummy password: %w", err)
+	}
+
 	server := Server{
-		config:     config,
-		store:      store,
-		tokenMaker: tokenMaker,
-		assetStore: assetMaker,
+		config:            config,
+		store:             store,
+		tokenMaker:        tokenMaker,
+		assetStore:        assetMaker,
+		mailer:            mailer,
+		renderer:          render.NewRenderer(render.NewRegistry(postResolver{store: store, frontendURL: config.FrontendURL})),
+		passwordHasher:    passwordHasher,
+		passwordPolicy:    passwordPolicy,
+		dummyPasswordHash: dummyPasswordHash,
+	}
+
+	// Passkeys are only available when the relying party is configured
+	if len(config.WebAuthnRPID) > 0 {
+		server.passkeys, err = passkey.New(passkey.Config{
+			RPID:          config.WebAuthnRPID,
+			RPDisplayName: appName,
+			RPOrigins:     config.WebAuthnRPOrigins,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(config.HighlightTheme) > 0 && !render.IsTheme(config.HighlightTheme) {
+		return nil, fmt.Errorf("unknown highlight theme %s", config.HighlightTheme)
+	}
+
+	server.trustedProxies, err = parseTrustedProxies(config.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	server.adminAllowlist, err = parseAllowedCIDRs(config.AdminAllowedCIDRs)
+	if err != nil {
+		return nil, err
 	}
 
-	server.setupRouter()
+	// The role protected routes move to their own listener when it is configured
+	if len(config.AdminServerAddress) > 0 {
+		server.adminTLS, err = newAdminTLSConfig(config.AdminTLSCertFile, config.AdminTLSKeyFile, config.AdminClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Single sign-on is only available when the OpenID Connect provider is configured
+	if len(config.OIDCIssuerURL) > 0 {
+		if len(config.OIDCDefaultRole) > 0 && !util.IsSupportedRole(config.OIDCDefaultRole) {
+			return nil, fmt.Errorf("unsupported OIDC default role %s", config.OIDCDefaultRole)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		server.oidc, err = oidc.New(ctx, oidc.Config{
+			IssuerURL:    config.OIDCIssuerURL,
+			ClientID:     config.OIDCClientID,
+			ClientSecret: config.OIDCClientSecret,
+			RedirectURL:  config.OIDCRedirectURL,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = server.setupRouter()
+	if err != nil {
+		return nil, err
+	}
 	return &server, nil
 }
 
+// newTokenMaker creates the token maker of the configured token type,
+// the keyring file takes precedence over the single key settings
+func newTokenMaker(config util.Config) (token.Maker, error) {
+	if len(config.TokenKeyringFile) > 0 {
+		keyring, err := token.LoadKeyring(config.TokenKeyringFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load token keyring: %w", err)
+		}
+
+		tokenType := config.TokenType
+		if len(tokenType) == 0 {
+			tokenType = token.PasetoLocalType
+		}
+		if keyring.TokenType != tokenType {
+			return nil, fmt.Errorf("the token keyring has %s keys but the token type is %s", keyring.TokenType, tokenType)
+		}
+
+		return token.NewKeyringMaker(keyring)
+	}
+
+	key := config.TokenSymmetricKey
+	if len(config.TokenPrivateKeyFile) > 0 {
+		privateKey, err := os.ReadFile(config.TokenPrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read token private key: %w", err)
+		}
+		key = string(privateKey)
+	}
+
+	return token.NewMaker(config.TokenType, key)
+}
+
+// newMailer creates the SMTP mailer. Without an SMTP host the emails are only logged in
+// development and test, any other environment refuses to start. The same goes for a relay
+// without TLS, which is only accepted with SMTP_ALLOW_INSECURE in development and test.
+func newMailer(config util.Config) (mail.Mailer, error) {
+	if config.SMTPAllowInsecure && !config.IsDevelopment() {
+		return nil, errors.New("SMTP_ALLOW_INSECURE is only allowed in the development and test environments")
+	}
+
+	if len(config.SMTPHost) == 0 {
+		if !config.IsDevelopment() {
+			return nil, errors.New("SMTP_HOST is required outside of the development and test environments")
+		}
+		log.Printf("WARNING: SMTP_HOST is not set, emails will be written to the log instead of being sent (ENVIRONMENT=%s)\n", config.Environment)
+		return mail.NewLogMailer(nil), nil
+	}
+
+	return mail.NewSMTPMailer(mail.SMTPConfig{
+		Host:          config.SMTPHost,
+		Port:          config.SMTPPort,
+		Username:      config.SMTPUsername,
+		Password:      config.SMTPPassword,
+		From:          config.MailFrom,
+		AllowInsecure: config.SMTPAllowInsecure,
+	})
+}
+
+// sendMail sends an email in the background, so the response time of the request
+// does not depend on the mail server
+func (server *Server) sendMail(msg mail.Message) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		if err := server.mailer.Send(ctx, msg); err != nil {
+			log.Printf("cannot send email %q: %v\n", msg.Subject, err)
+		}
+	}()
+}
+
 func (server *Server) Start(address string) error {
-	return server.router.Run(address)
+	go server.renderStalePosts(context.Background())
+
+	if server.config.SessionSweepInterval > 0 {
+		go server.sweepExpiredSessions(context.Background(), server.config.SessionSweepInterval)
+	}
+
+	if server.adminRouter == nil {
+		return server.router.Run(address)
+	}
+
+	errs := make(chan error, 2)
+	go func() {
+		errs <- server.router.Run(address)
+	}()
+	go func() {
+		errs <- server.startAdmin(server.config.AdminServerAddress)
+	}()
+	return <-errs
 }
 
 func errorResponse(err error) gin.H {
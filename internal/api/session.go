@@ -0,0 +1,225 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type sessionResponse struct {
+	ID        uuid.UUID `json:"id"`
+	UserAgent string    `json:"user_agent"`
+	ClientIp  string    `json:"client_ip"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newSessionResponse(session db.Session) sessionResponse {
+	return sessionResponse{
+		ID:        session.ID,
+		UserAgent: session.UserAgent,
+		ClientIp:  session.ClientIp,
+		ExpiresAt: session.ExpiresAt,
+		CreatedAt: session.CreatedAt,
+	}
+}
+
+// listSessions godoc
+//
+//	@Summary					List Sessions
+//	@Description				Recive the active sessions of the logged user
+//	@Tags						session,list
+//	@Produce					json
+//	@Success					200	{array}	sessionResponse
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/sessions [get]
+func (server *Server) listSessions(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	sessions, err := server.store.ListActiveSessions(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]sessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, newSessionResponse(session))
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// revoke Session handler
+type revokeSessionRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// revokeSession godoc
+//
+//	@Summary					Revoke Session
+//	@Description				Block one session of the logged user and the sessions rotated from it
+//	@Tags						session,delete
+//	@Produce					json
+//	@Param						id	path		string	true	"id"
+//	@Success					200	{object}	uuid.UUID
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/session/{id} [delete]
+func (server *Server) revokeSession(ctx *gin.Context) {
+	var req revokeSessionRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	sessionID, err := uuid.Parse(req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	blocked, err := server.store.BlockSession(ctx, db.BlockSessionParams{
+		ID:       sessionID,
+		Username: authPayload.Username,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if blocked == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(db.ErrRecordNotFound))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sessionID)
+}
+
+// revokeAllSessions godoc
+//
+//	@Summary					Logout Everywhere
+//	@Description				Block every session of the logged user
+//	@Tags						session,delete
+//	@Produce					json
+//	@Success					200	{object}	string
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/sessions [delete]
+func (server *Server) revokeAllSessions(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	err := server.store.BlockUserSessions(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, authPayload.Username)
+}
+
+// block User Sessions handler
+type blockUserSessionsRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// blockUserSessions godoc
+//
+//	@Summary					Block User Sessions
+//	@Description				Block every session of any user
+//	@Tags						session,user,delete
+//	@Produce					json
+//	@Param						id	path		string	true	"id"
+//	@Success					200	{object}	uuid.UUID
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/user/{id}/sessions [delete]
+func (server *Server) blockUserSessions(ctx *gin.Context) {
+	var req blockUserSessionsRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	userID, err := uuid.Parse(req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = server.store.BlockUserSessions(ctx, user.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, userID)
+}
+
+// sweepExpiredSessions deletes the expired sessions, login challenges, passkey ceremonies, single sign-on logins,
+// password reset tokens, email verifications and old failed login counters every interval until ctx is done
+func (server *Server) sweepExpiredSessions(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	sweeps := []struct {
+		name   string
+		delete func(ctx context.Context) (int64, error)
+	}{
+		{name: "expired sessions", delete: server.store.DeleteExpiredSessions},
+		{name: "expired login challenges", delete: server.store.DeleteExpiredMFAChallenges},
+		{name: "expired passkey ceremonies", delete: server.store.DeleteExpiredWebAuthnSessions},
+		{name: "expired single sign-on logins", delete: server.store.DeleteExpiredOIDCLogins},
+		{name: "expired password reset tokens", delete: server.store.DeleteExpiredPasswordResetTokens},
+		{name: "expired email verifications", delete: server.store.DeleteExpiredEmailVerifications},
+		{name: "old failed login counters", delete: func(ctx context.Context) (int64, error) {
+			return server.store.DeleteStaleLoginAttempts(ctx, time.Now().Add(-loginAttemptWindow))
+		}},
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, sweep := range sweeps {
+				deleted, err := sweep.delete(ctx)
+				if err != nil {
+					log.Printf("cannot delete %s: %v\n", sweep.name, err)
+					continue
+				}
+				if deleted > 0 {
+					log.Printf("deleted %d %s\n", deleted, sweep.name)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Slugs used when nothing is left of the title or the name after making the slug
+const (
+	defaultPostSlug     = "post"
+	defaultTagSlug      = "tag"
+	defaultCategorySlug = "category"
+)
+
+var errInvalidSlug = errors.New("the slug can only have lowercase letters and digits separated by hyphens")
+
+// uniqueSlug makes the slug of a text, a number is appended while exists reports it is taken
+func uniqueSlug(text string, fallback string, exists func(slug string) (bool, error)) (string, error) {
+	base := util.Slugify(text)
+	if len(base) == 0 {
+		base = fallback
+	}
+
+	candidate := base
+	for n := 2; ; n++ {
+		taken, err := exists(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+
+		suffix := fmt.Sprintf("-%d", n)
+		candidate = strings.TrimRight(base[:min(len(base), util.SlugMaxLength-len(suffix))], "-") + suffix
+	}
+}
+
+// uniquePostSlug makes the slug of a post title, postID is the post that will get the slug
+// so its own slug does not count as taken
+func (server *Server) uniquePostSlug(ctx *gin.Context, title string, postID uuid.UUID) (string, error) {
+	return uniqueSlug(title, defaultPostSlug, func(slug string) (bool, error) {
+		return server.store.PostSlugExists(ctx, db.PostSlugExistsParams{Slug: slug, PostID: postID})
+	})
+}
+
+// uniqueTagSlug makes the slug of a tag name, tagID is the tag that will get the slug
+func (server *Server) uniqueTagSlug(ctx *gin.Context, name string, tagID uuid.UUID) (string, error) {
+	return uniqueSlug(name, defaultTagSlug, func(slug string) (bool, error) {
+		return server.store.TagSlugExists(ctx, db.TagSlugExistsParams{Slug: slug, TagID: tagID})
+	})
+}
+
+// uniqueCategorySlug makes the slug of a category name, categoryID is the category that will get the slug
+func (server *Server) uniqueCategorySlug(ctx *gin.Context, name string, categoryID uuid.UUID) (string, error) {
+	return uniqueSlug(name, defaultCategorySlug, func(slug string) (bool, error) {
+		return server.store.CategorySlugExists(ctx, db.CategorySlugExistsParams{Slug: slug, CategoryID: categoryID})
+	})
+}
+
+// lookupKey returns the id or the slug that identifies a tag or a category in a route
+func lookupKey(key string) (id pgtype.UUID, slug pgtype.Text) {
+	if parsed, err := uuid.Parse(key); err == nil {
+		return pgtype.UUID{Bytes: parsed, Valid: true}, slug
+	}
+	return id, pgtype.Text{String: key, Valid: true}
+}
@@ -0,0 +1,439 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// fakeStore keeps the rows the handler tests need in memory, the methods a test does not need
+// are left to the embedded nil Store and panic when they are called
+type fakeStore struct {
+	db.Store
+
+	mu            sync.Mutex
+	users         map[uuid.UUID]db.User
+	sessions      map[uuid.UUID]db.Session
+	tokens        map[string]db.GetPersonalAccessTokenByHashRow
+	loginAttempts map[string]db.LoginAttempt
+	identities    map[string]db.UserIdentity
+	recoveryCodes map[uuid.UUID]int64
+	verifications []db.EmailVerification
+	posts         map[string]db.GetPostBySlugPublicRow
+	// postSlugs maps the previous slugs to the current slug of their post
+	postSlugs map[string]string
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		users:         map[uuid.UUID]db.User{},
+		sessions:      map[uuid.UUID]db.Session{},
+		tokens:        map[string]db.GetPersonalAccessTokenByHashRow{},
+		loginAttempts: map[string]db.LoginAttempt{},
+		identities:    map[string]db.UserIdentity{},
+		recoveryCodes: map[uuid.UUID]int64{},
+		posts:         map[string]db.GetPostBySlugPublicRow{},
+		postSlugs:     map[string]string{},
+	}
+}
+
+func (store *fakeStore) putUser(user db.User) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.users[user.ID] = user
+}
+
+func (store *fakeStore) putPersonalAccessToken(tokenHash string, pat db.GetPersonalAccessTokenByHashRow) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.tokens[tokenHash] = pat
+}
+
+func (store *fakeStore) putPost(post db.GetPostBySlugPublicRow, previousSlugs ...string) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.posts[post.Slug] = post
+	for _, slug := range previousSlugs {
+		store.postSlugs[slug] = post.Slug
+	}
+}
+
+func (store *fakeStore) session(id uuid.UUID) db.Session {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return store.sessions[id]
+}
+
+func (store *fakeStore) userByUsername(username string) (db.User, bool) {
+	for _, user := range store.users {
+		if user.Username == username {
+			return user, true
+		}
+	}
+	return db.User{}, false
+}
+
+func (store *fakeStore) GetUser(ctx context.Context, id uuid.UUID) (db.GetUserRow, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	user, ok := store.users[id]
+	if !ok {
+		return db.GetUserRow{}, db.ErrRecordNotFound
+	}
+	return db.GetUserRow{
+		ID:              user.ID,
+		Username:        user.Username,
+		Email:           user.Email,
+		Password:        user.Password,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+		Role:            user.Role,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		DisabledAt:      user.DisabledAt,
+		TotpEnabledAt:   user.TotpEnabledAt,
+	}, nil
+}
+
+func (store *fakeStore) GetUserByUsername(ctx context.Context, username string) (db.GetUserByUsernameRow, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	user, ok := store.userByUsername(username)
+	if !ok {
+		return db.GetUserByUsernameRow{}, db.ErrRecordNotFound
+	}
+	return db.GetUserByUsernameRow{
+		ID:              user.ID,
+		Username:        user.Username,
+		Password:        user.Password,
+		Email:           user.Email,
+		Role:            user.Role,
+		TotpEnabledAt:   user.TotpEnabledAt,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		DisabledAt:      user.DisabledAt,
+	}, nil
+}
+
+func (store *fakeStore) CreateSession(ctx context.Context, arg db.CreateSessionParams) (db.Session, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return store.createSession(arg), nil
+}
+
+func (store *fakeStore) createSession(arg db.CreateSessionParams) db.Session {
+	session := db.Session{
+		ID:           arg.ID,
+		Username:     arg.Username,
+		RefreshToken: arg.RefreshToken,
+		UserAgent:    arg.UserAgent,
+		ClientIp:     arg.ClientIp,
+		IsBlocked:    arg.IsBlocked,
+		ExpiresAt:    arg.ExpiresAt,
+		CreatedAt:    time.Now(),
+		FamilyID:     arg.FamilyID,
+		ParentID:     arg.ParentID,
+	}
+	store.sessions[session.ID] = session
+	return session
+}
+
+func (store *fakeStore) GetSession(ctx context.Context, id uuid.UUID) (db.Session, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	session, ok := store.sessions[id]
+	if !ok {
+		return db.Session{}, db.ErrRecordNotFound
+	}
+	return session, nil
+}
+
+func (store *fakeStore) RotateSessionTx(ctx context.Context, arg db.RotateSessionTxParams) (db.RotateSessionTxResult, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	session, ok := store.sessions[arg.SessionID]
+	if !ok || session.IsBlocked || session.RotatedAt.Valid {
+		return db.RotateSessionTxResult{}, db.ErrRecordNotFound
+	}
+	session.RotatedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	store.sessions[session.ID] = session
+
+	return db.RotateSessionTxResult{Session: store.createSession(arg.NewSession)}, nil
+}
+
+func (store *fakeStore) BlockSessionFamily(ctx context.Context, familyID uuid.UUID) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for id, session := range store.sessions {
+		if session.FamilyID == familyID {
+			session.IsBlocked = true
+			store.sessions[id] = session
+		}
+	}
+	return nil
+}
+
+func (store *fakeStore) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (db.GetPersonalAccessTokenByHashRow, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	pat, ok := store.tokens[tokenHash]
+	if !ok {
+		return db.GetPersonalAccessTokenByHashRow{}, db.ErrRecordNotFound
+	}
+	return pat, nil
+}
+
+func (store *fakeStore) TouchPersonalAccessToken(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (store *fakeStore) GetLoginLock(ctx context.Context, arg db.GetLoginLockParams) (pgtype.Timestamptz, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var lockedUntil pgtype.Timestamptz
+	for _, attempt := range []db.LoginAttempt{
+		store.loginAttempts[loginScopeUsername+"/"+arg.Username],
+		store.loginAttempts[loginScopeIP+"/"+arg.ClientIp],
+	} {
+		if attempt.LockedUntil.Valid && attempt.LockedUntil.Time.After(time.Now()) && attempt.LockedUntil.Time.After(lockedUntil.Time) {
+			lockedUntil = attempt.LockedUntil
+		}
+	}
+	if !lockedUntil.Valid {
+		return lockedUntil, db.ErrRecordNotFound
+	}
+	return lockedUntil, nil
+}
+
+func (store *fakeStore) RecordLoginFailure(ctx context.Context, arg db.RecordLoginFailureParams) (db.LoginAttempt, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	attempt, ok := store.loginAttempts[arg.Scope+"/"+arg.Key]
+	if !ok || attempt.LastFailureAt.Before(arg.ResetBefore) {
+		attempt = db.LoginAttempt{Scope: arg.Scope, Key: arg.Key}
+	}
+	attempt.Failures++
+	attempt.LastFailureAt = time.Now()
+	store.loginAttempts[arg.Scope+"/"+arg.Key] = attempt
+	return attempt, nil
+}
+
+func (store *fakeStore) LockLogin(ctx context.Context, arg db.LockLoginParams) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	attempt := store.loginAttempts[arg.Scope+"/"+arg.Key]
+	attempt.LockedUntil = arg.LockedUntil
+	store.loginAttempts[arg.Scope+"/"+arg.Key] = attempt
+	return nil
+}
+
+func (store *fakeStore) ResetLoginAttempts(ctx context.Context, arg db.ResetLoginAttemptsParams) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	delete(store.loginAttempts, arg.Scope+"/"+arg.Key)
+	return nil
+}
+
+func (store *fakeStore) GetPostBySlugPublic(ctx context.Context, slug string) (db.GetPostBySlugPublicRow, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	post, ok := store.posts[slug]
+	if !ok || !post.Publicated {
+		return db.GetPostBySlugPublicRow{}, db.ErrRecordNotFound
+	}
+	return post, nil
+}
+
+func (store *fakeStore) GetPostSlugRedirectPublic(ctx context.Context, slug string) (string, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	current, ok := store.postSlugs[slug]
+	if !ok || !store.posts[current].Publicated {
+		return "", db.ErrRecordNotFound
+	}
+	return current, nil
+}
+
+func (store *fakeStore) UpdateUserTx(ctx context.Context, arg db.UpdateUserParams) (db.User, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	user, ok := store.users[arg.ID]
+	if !ok {
+		return db.User{}, db.ErrRecordNotFound
+	}
+
+	if arg.Username.Valid && arg.Username.String != user.Username {
+		if _, taken := store.userByUsername(arg.Username.String); taken {
+			return db.User{}, db.ErrUniqueViolation
+		}
+
+		// The sessions follow the username like the ON UPDATE CASCADE foreign key
+		for id, session := range store.sessions {
+			if session.Username == user.Username {
+				session.Username = arg.Username.String
+				store.sessions[id] = session
+			}
+		}
+		user.Username = arg.Username.String
+	}
+	if arg.Password.Valid {
+		user.Password = arg.Password.String
+	}
+	if arg.Role.Valid {
+		user.Role = arg.Role.String
+	}
+	user.UpdatedAt = time.Now()
+
+	store.users[user.ID] = user
+	return user, nil
+}
+
+func (store *fakeStore) RehashUserPassword(ctx context.Context, arg db.RehashUserPasswordParams) (int64, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	user, ok := store.users[arg.ID]
+	if !ok || user.Password != arg.OldPassword {
+		return 0, nil
+	}
+	user.Password = arg.NewPassword
+	store.users[user.ID] = user
+	return 1, nil
+}
+
+func (store *fakeStore) user(id uuid.UUID) db.User {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return store.users[id]
+}
+
+func (store *fakeStore) GetUserByEmail(ctx context.Context, email string) (db.GetUserByEmailRow, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for _, user := range store.users {
+		if user.Email == email {
+			return db.GetUserByEmailRow{
+				ID:              user.ID,
+				Username:        user.Username,
+				Email:           user.Email,
+				EmailVerifiedAt: user.EmailVerifiedAt,
+				DisabledAt:      user.DisabledAt,
+			}, nil
+		}
+	}
+	return db.GetUserByEmailRow{}, db.ErrRecordNotFound
+}
+
+func (store *fakeStore) GetUserIdentity(ctx context.Context, arg db.GetUserIdentityParams) (db.UserIdentity, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	identity, ok := store.identities[arg.Issuer+"/"+arg.Subject]
+	if !ok {
+		return db.UserIdentity{}, db.ErrRecordNotFound
+	}
+	return identity, nil
+}
+
+func (store *fakeStore) CreateUserIdentity(ctx context.Context, arg db.CreateUserIdentityParams) (db.UserIdentity, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, ok := store.identities[arg.Issuer+"/"+arg.Subject]; ok {
+		return db.UserIdentity{}, db.ErrUniqueViolation
+	}
+	identity := db.UserIdentity{
+		ID:        uuid.New(),
+		UserID:    arg.UserID,
+		Issuer:    arg.Issuer,
+		Subject:   arg.Subject,
+		CreatedAt: time.Now(),
+	}
+	store.identities[arg.Issuer+"/"+arg.Subject] = identity
+	return identity, nil
+}
+
+func (store *fakeStore) GetAuthorByUsername(ctx context.Context, username string) (db.GetAuthorByUsernameRow, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	user, ok := store.userByUsername(username)
+	if !ok {
+		return db.GetAuthorByUsernameRow{}, db.ErrRecordNotFound
+	}
+	return db.GetAuthorByUsernameRow{
+		Username:    user.Username,
+		DisplayName: user.Username,
+		SocialLinks: []byte("{}"),
+		CreatedAt:   user.CreatedAt,
+	}, nil
+}
+
+func (store *fakeStore) CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return store.recoveryCodes[userID], nil
+}
+
+func (store *fakeStore) UpdateAuthorProfile(ctx context.Context, arg db.UpdateAuthorProfileParams) (db.User, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	user, ok := store.users[arg.ID]
+	if !ok {
+		return db.User{}, db.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+func (store *fakeStore) CreateEmailVerification(ctx context.Context, arg db.CreateEmailVerificationParams) (db.EmailVerification, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	verification := db.EmailVerification{
+		ID:        uuid.New(),
+		UserID:    arg.UserID,
+		Email:     arg.Email,
+		TokenHash: arg.TokenHash,
+		ExpiresAt: arg.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+	store.verifications = append(store.verifications, verification)
+	return verification, nil
+}
+
+func (store *fakeStore) CountRecentEmailVerifications(ctx context.Context, arg db.CountRecentEmailVerificationsParams) (int64, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var count int64
+	for _, verification := range store.verifications {
+		if verification.UserID == arg.UserID && verification.CreatedAt.After(arg.CreatedAfter) {
+			count++
+		}
+	}
+	return count, nil
+}
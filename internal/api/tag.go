@@ -2,6 +2,7 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"io"
 	"mime/multipart"
 	"net/http"
@@ -12,27 +13,35 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const tagBucketPath = "tags"
 
 type createTagRequest struct {
-	Name string                `form:"name" binding:"required,alphanum"`
-	Logo *multipart.FileHeader `form:"logo" binding:"required"`
+	Name        string                `form:"name" binding:"required,max=64"`
+	Slug        string                `form:"slug"`
+	Description string                `form:"description" binding:"max=500"`
+	Logo        *multipart.FileHeader `form:"logo" binding:"required"`
 }
 
 // createTag godoc
 //
 //	@Summary					Create a new Tag
-//	@Description				Create a new Tag, the image are upload to S3 services
+//	@Description				Create a new Tag, the image are upload to S3 services.
+//	@Description				Without a slug it is made from the name.
 //	@Tags						tag,create
 //	@Accept						multipart/form-data
 //	@Produce					json
-//	@Success					200		{object}	db.Tag
+//	@Success					200			{object}	db.Tag
 //
-//	@Param						name	formData	string	true	"This is the tag name"
+//	@Param						name		formData	string	true	"This is the tag name"
 //
-//	@Param						logo	formData	file	true	"This is a image"
+//	@Param						slug		formData	string	false	"This is the tag slug"
+//
+//	@Param						description	formData	string	false	"This is the tag description"
+//
+//	@Param						logo		formData	file	true	"This is a image"
 //
 //	@securityDefinitions.apiKey	token
 //	@in							header
@@ -46,8 +55,23 @@ func (server *Server) createTag(ctx *gin.Context) {
 		return
 	}
 
+	slug := req.Slug
+	if len(slug) > 0 {
+		if !util.IsSlug(slug) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidSlug))
+			return
+		}
+	} else {
+		var err error
+		slug, err = server.uniqueTagSlug(ctx, req.Name, uuid.Nil)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
 	//tagBucketPath := "tags"
-	objectName := req.Name + util.RandomString(4)
+	objectName := slug + util.RandomString(4)
 
 	// Save image
 	fileContent, err := req.Logo.Open()
@@ -69,8 +93,10 @@ func (server *Server) createTag(ctx *gin.Context) {
 	}
 
 	arg := db.CreateTagParams{
-		Name:     req.Name,
-		ImageUrl: tagURL,
+		Name:        req.Name,
+		Slug:        slug,
+		Description: req.Description,
+		ImageUrl:    tagURL,
 	}
 
 	tag, err := server.store.CreateTag(ctx, arg)
@@ -94,19 +120,19 @@ func (server *Server) createTag(ctx *gin.Context) {
 
 // get Tag handler
 type getTagRequest struct {
-	ID string `uri:"id" binding:"required,uuid"`
+	ID string `uri:"id" binding:"required"`
 }
 
 // getTag godoc
 //
 //	@Summary		Get a Tag
-//	@Description	Recive the one tag information from a id
+//	@Description	Recive the one tag information from a id or a slug
 //	@Tags			tag,get
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	db.Tag
+//	@Success		200	{object}	db.LookupTagRow
 //
-//	@Param			id	path		string	true	"id"
+//	@Param			id	path		string	true	"id or slug"
 //	@Router			/tag/{id} [get]
 func (server *Server) getTag(ctx *gin.Context) {
 	var req getTagRequest
@@ -115,15 +141,13 @@ func (server *Server) getTag(ctx *gin.Context) {
 		return
 	}
 
-	tagID, err := uuid.Parse(req.ID)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	tag, err := server.store.GetTag(ctx, tagID)
+	tagID, slug := lookupKey(req.ID)
+	tag, err := server.store.LookupTag(ctx, db.LookupTagParams{
+		ID:   tagID,
+		Slug: slug,
+	})
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, db.ErrRecordNotFound) {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
 			return
 		}
@@ -156,6 +180,86 @@ func (server *Server) listTags(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, tags)
 }
 
+// updateTag handler
+type updateTagRequestData struct {
+	Name        string  `json:"name" binding:"omitempty,max=64"`
+	Slug        string  `json:"slug"`
+	Description *string `json:"description" binding:"omitempty,max=500"`
+}
+type updateTagRequestID struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// updateTag godoc
+//
+//	@Summary					Update Tag
+//	@Description				Update the tag information, the slug only changes when it is sent
+//	@Tags						tag,update
+//	@Accept						json
+//	@Produce					json
+//	@Param						id	path		string					true	"id"
+//	@Param						tag	body		updateTagRequestData	true	"Tag Data"
+//	@Success					200	{object}	db.Tag
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/tag/{id} [put]
+func (server *Server) updateTag(ctx *gin.Context) {
+	var tagIDReq updateTagRequestID
+	if err := ctx.ShouldBindUri(&tagIDReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	tagID, err := uuid.Parse(tagIDReq.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	var reqData updateTagRequestData
+	if err := ctx.ShouldBindJSON(&reqData); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.UpdateTagParams{
+		ID: tagID,
+	}
+
+	if len(reqData.Name) > 0 {
+		arg.Name = pgtype.Text{String: reqData.Name, Valid: true}
+	}
+
+	if len(reqData.Slug) > 0 {
+		if !util.IsSlug(reqData.Slug) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidSlug))
+			return
+		}
+		arg.Slug = pgtype.Text{String: reqData.Slug, Valid: true}
+	}
+
+	if reqData.Description != nil {
+		arg.Description = pgtype.Text{String: *reqData.Description, Valid: true}
+	}
+
+	tag, err := server.store.UpdateTag(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if db.ErrorCode(err) == db.UniqueViolation {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tag)
+}
+
 // delete Tag handler
 
 type deleteTagRequest struct {
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type renewAccessTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type renewAccessTokenResponse struct {
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	SessionID             uuid.UUID `json:"session_id"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+var (
+	errRefreshTokenReused = errors.New("refresh token was already used")
+	errNotRefreshToken    = errors.New("the token is not a refresh token")
+)
+
+// renewAccessToken godoc
+//
+//	@Summary		Renew Access Token
+//	@Description	Verify a refresh token against its session and return a new access token.
+//	@Description	The refresh token is rotated: the old one can not be used again and reusing it blocks every session of its chain.
+//	@Tags			token,login
+//	@Accept			json
+//	@Produce		json
+//	@Success		200		{object}	renewAccessTokenResponse
+//
+//	@Param			token	body		renewAccessTokenRequest	true	"Refresh Token"
+//	@Router			/tokens/renew_access [post]
+func (server *Server) renewAccessToken(ctx *gin.Context) {
+	var req renewAccessTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	refreshPayload, err := server.tokenMaker.VerifyToken(req.RefreshToken)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if refreshPayload.Type != token.RefreshToken {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errNotRefreshToken))
+		return
+	}
+
+	session, err := server.store.GetSession(ctx, refreshPayload.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if session.IsBlocked {
+		err := fmt.Errorf("blocked session")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if session.Username != refreshPayload.Username {
+		err := fmt.Errorf("incorrect session user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if session.RefreshToken != req.RefreshToken {
+		err := fmt.Errorf("mismatched session token")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if session.RotatedAt.Valid {
+		server.blockReusedSession(ctx, session)
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errRefreshTokenReused))
+		return
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		err := fmt.Errorf("expired session")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	// Read the role again so a demoted user does not keep the old one
+	user, err := server.store.GetUserByUsername(ctx, session.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if user.DisabledAt.Valid {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errUserDisabled))
+		return
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(
+		user.Username,
+		user.Role,
+		token.AccessToken,
+		server.config.AccessTokenDuration,
+	)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	refreshToken, newRefreshPayload, err := server.tokenMaker.CreateToken(
+		user.Username,
+		user.Role,
+		token.RefreshToken,
+		server.config.RefreshTokenDuration,
+	)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.RotateSessionTx(ctx, db.RotateSessionTxParams{
+		SessionID: session.ID,
+		NewSession: db.CreateSessionParams{
+			ID:           newRefreshPayload.ID,
+			Username:     user.Username,
+			RefreshToken: refreshToken,
+			UserAgent:    ctx.Request.UserAgent(),
+			ClientIp:     ctx.ClientIP(),
+			IsBlocked:    false,
+			ExpiresAt:    newRefreshPayload.ExpiredAt,
+			FamilyID:     session.FamilyID,
+			ParentID:     pgtype.UUID{Bytes: session.ID, Valid: true},
+		},
+	})
+	if err != nil {
+		// Another request rotated the same token first
+		if errors.Is(err, db.ErrRecordNotFound) {
+			server.blockReusedSession(ctx, session)
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errRefreshTokenReused))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := renewAccessTokenResponse{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
+		SessionID:             result.Session.ID,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: newRefreshPayload.ExpiredAt,
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// blockReusedSession blocks the whole rotation chain of a session whose refresh token was
+// presented again after being rotated, which most likely means the token was stolen
+func (server *Server) blockReusedSession(ctx *gin.Context, session db.Session) {
+	log.Printf(
+		"refresh token reuse detected for session %s of user %s from %s, possible token theft: blocking session family %s\n",
+		session.ID, session.Username, ctx.ClientIP(), session.FamilyID,
+	)
+
+	err := server.store.BlockSessionFamily(ctx, session.FamilyID)
+	if err != nil {
+		log.Println("cannot block session family:", err)
+	}
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// loginForTest logs the user in through the router and returns the response
+func loginForTest(t *testing.T, server *Server, username string, password string) loginUserResponse {
+	recorder := serveJSON(t, server.router, http.MethodPost, "/v1/login", gin.H{
+		"username": username,
+		"password": password,
+	})
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp loginUserResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	return rsp
+}
+
+func renewForTest(t *testing.T, server *Server, refreshToken string) *httptest.ResponseRecorder {
+	return serveJSON(t, server.router, http.MethodPost, "/v1/tokens/renew_access", gin.H{
+		"refresh_token": refreshToken,
+	})
+}
+
+func TestRenewAccessTokenRotation(t *testing.T) {
+	store := newFakeStore()
+	server := newTestServer(t, store)
+	password := util.RandomString(12)
+	user := randomUser(t, server, store, util.AuthorRole, password)
+
+	login := loginForTest(t, server, user.Username, password)
+
+	recorder := renewForTest(t, server, login.RefreshToken)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp renewAccessTokenResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.NotEqual(t, login.RefreshToken, rsp.RefreshToken)
+	require.NotEqual(t, login.SessionID, rsp.SessionID)
+
+	payload, err := server.tokenMaker.VerifyToken(rsp.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, token.AccessToken, payload.Type)
+	require.Equal(t, user.Username, payload.Username)
+
+	oldSession := store.session(login.SessionID)
+	require.True(t, oldSession.RotatedAt.Valid)
+	newSession := store.session(rsp.SessionID)
+	require.Equal(t, oldSession.FamilyID, newSession.FamilyID)
+	require.True(t, newSession.ParentID.Valid)
+	require.False(t, newSession.IsBlocked)
+
+	// The rotated token keeps working
+	recorder = renewForTest(t, server, rsp.RefreshToken)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestRenewAccessTokenReuse(t *testing.T) {
+	store := newFakeStore()
+	server := newTestServer(t, store)
+	password := util.RandomString(12)
+	user := randomUser(t, server, store, util.AuthorRole, password)
+
+	login := loginForTest(t, server, user.Username, password)
+
+	recorder := renewForTest(t, server, login.RefreshToken)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rsp renewAccessTokenResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+
+	// Presenting the old refresh token again blocks the whole chain
+	recorder = renewForTest(t, server, login.RefreshToken)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	require.True(t, store.session(login.SessionID).IsBlocked)
+	require.True(t, store.session(rsp.SessionID).IsBlocked)
+
+	recorder = renewForTest(t, server, rsp.RefreshToken)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestRenewAccessTokenRejected(t *testing.T) {
+	store := newFakeStore()
+	server := newTestServer(t, store)
+	password := util.RandomString(12)
+	user := randomUser(t, server, store, util.AuthorRole, password)
+
+	login := loginForTest(t, server, user.Username, password)
+
+	testCases := []struct {
+		name         string
+		refreshToken string
+		expectedCode int
+	}{
+		{name: "AccessToken", refreshToken: login.AccessToken, expectedCode: http.StatusUnauthorized},
+		{name: "InvalidToken", refreshToken: util.RandomString(40), expectedCode: http.StatusUnauthorized},
+		{name: "NoToken", expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := renewForTest(t, server, tc.refreshToken)
+			require.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+
+	// A refresh token without a session
+	refreshToken, _, err := server.tokenMaker.CreateToken(user.Username, user.Role, token.RefreshToken, server.config.RefreshTokenDuration)
+	require.NoError(t, err)
+	recorder := renewForTest(t, server, refreshToken)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+
+	// The session of the rejected access token is untouched
+	require.False(t, store.session(login.SessionID).RotatedAt.Valid)
+	require.False(t, store.session(login.SessionID).IsBlocked)
+}
@@ -0,0 +1,399 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	db "github.com/JairoRiver/personal_blog_backend/internal/db/sqlc"
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/JairoRiver/personal_blog_backend/pkg/totp"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	totpIssuer = appName
+
+	mfaChallengeDuration    = 5 * time.Minute
+	mfaChallengeMaxAttempts = 5
+)
+
+var (
+	errTOTPEnabled         = errors.New("two-factor authentication is already enabled")
+	errTOTPNotEnabled      = errors.New("two-factor authentication is not enabled")
+	errTOTPNotPending      = errors.New("there is no pending two-factor enrollment")
+	errInvalidTOTPCode     = errors.New("invalid two-factor code")
+	errInvalidMFAChallenge = errors.New("invalid or expired login challenge")
+)
+
+type loginChallengeResponse struct {
+	MFARequired    bool      `json:"mfa_required"`
+	ChallengeToken string    `json:"challenge_token"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// createMFAChallenge responds to a login with a correct password with a short-lived challenge
+// that must be completed with a second factor
+func (server *Server) createMFAChallenge(ctx *gin.Context, userID uuid.UUID) {
+	challengeToken, tokenHash, err := token.NewOpaqueToken("")
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	challenge, err := server.store.CreateMFAChallenge(ctx, db.CreateMFAChallengeParams{
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(mfaChallengeDuration),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp := loginChallengeResponse{
+		MFARequired:    true,
+		ChallengeToken: challengeToken,
+		ExpiresAt:      challenge.ExpiresAt,
+	}
+	ctx.JSON(http.StatusAccepted, rsp)
+}
+
+// verifySecondFactor checks a TOTP code or a recovery code of a user, both can only be used once
+func (server *Server) verifySecondFactor(ctx *gin.Context, user db.GetUserTOTPRow, code string) (bool, error) {
+	if step, ok := totp.Validate(user.TotpSecret.String, code, time.Now()); ok {
+		used, err := server.store.UseTOTPStep(ctx, db.UseTOTPStepParams{
+			ID:               user.ID,
+			TotpLastUsedStep: step,
+		})
+		return used > 0, err
+	}
+
+	used, err := server.store.UseRecoveryCode(ctx, db.UseRecoveryCodeParams{
+		UserID:   user.ID,
+		CodeHash: token.HashOpaqueToken(totp.NormalizeRecoveryCode(code)),
+	})
+	if used > 0 {
+		log.Printf("user %s logged in with a recovery code\n", user.Username)
+	}
+	return used > 0, err
+}
+
+// loginTOTP handler
+type loginTOTPRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// loginTOTP godoc
+//
+//	@Summary		Login Two-Factor
+//	@Description	Complete a login challenge with a TOTP code or a recovery code and return access token a refresh token
+//	@Tags			user,login
+//	@Accept			json
+//	@Produce		json
+//	@Success		200		{object}	loginUserResponse
+//
+//	@Param			code	body		loginTOTPRequest	true	"Challenge and Code"
+//	@Router			/login/2fa [post]
+func (server *Server) loginTOTP(ctx *gin.Context) {
+	var req loginTOTPRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	challenge, err := server.store.GetMFAChallengeByHash(ctx, token.HashOpaqueToken(req.ChallengeToken))
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidMFAChallenge))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if time.Now().After(challenge.ExpiresAt) || challenge.Attempts >= mfaChallengeMaxAttempts {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidMFAChallenge))
+		return
+	}
+
+	user, err := server.store.GetUserTOTP(ctx, challenge.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !user.TotpEnabledAt.Valid {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidMFAChallenge))
+		return
+	}
+
+	if !server.checkLoginLock(ctx, user.Username) {
+		return
+	}
+
+	ok, err := server.verifySecondFactor(ctx, user, req.Code)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !ok {
+		err = server.store.IncrementMFAChallengeAttempts(ctx, challenge.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		err = server.recordLoginFailure(ctx, user.Username)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidTOTPCode))
+		return
+	}
+
+	// The challenge can only be completed once
+	deleted, err := server.store.DeleteMFAChallenge(ctx, challenge.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if deleted == 0 {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errInvalidMFAChallenge))
+		return
+	}
+
+	rsp, err := server.createLoginSession(ctx, user.ID, user.Username, user.Role)
+	if err != nil {
+		if errors.Is(err, errUserDisabled) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type enrollTOTPResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// enrollTOTP godoc
+//
+//	@Summary					Enroll TOTP
+//	@Description				Generate a new TOTP secret for the logged user, the provisioning URI can be shown as a QR code.
+//	@Description				Two-factor authentication is enabled once a code of the secret is verified.
+//	@Tags						user,2fa
+//	@Produce					json
+//	@Success					200	{object}	enrollTOTPResponse
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/me/2fa/totp [post]
+func (server *Server) enrollTOTP(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if user.TotpEnabledAt.Valid {
+		ctx.JSON(http.StatusConflict, errorResponse(errTOTPEnabled))
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	updated, err := server.store.SetUserTOTPSecret(ctx, db.SetUserTOTPSecretParams{
+		ID:         user.ID,
+		TotpSecret: pgtype.Text{String: secret, Valid: true},
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if updated == 0 {
+		ctx.JSON(http.StatusConflict, errorResponse(errTOTPEnabled))
+		return
+	}
+
+	rsp := enrollTOTPResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(totpIssuer, user.Username, secret),
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// verifyTOTP handler
+type verifyTOTPRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+type verifyTOTPResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// verifyTOTP godoc
+//
+//	@Summary					Verify TOTP
+//	@Description				Verify a code of the pending TOTP secret and enable two-factor authentication.
+//	@Description				It returns the recovery codes, they are only shown once.
+//	@Tags						user,2fa
+//	@Accept						json
+//	@Produce					json
+//	@Success					200		{object}	verifyTOTPResponse
+//
+//	@Param						code	body		verifyTOTPRequest	true	"TOTP Code"
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/me/2fa/totp/verify [post]
+func (server *Server) verifyTOTP(ctx *gin.Context) {
+	var req verifyTOTPRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	account, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUserTOTP(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if user.TotpEnabledAt.Valid {
+		ctx.JSON(http.StatusConflict, errorResponse(errTOTPEnabled))
+		return
+	}
+
+	if !user.TotpSecret.Valid {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errTOTPNotPending))
+		return
+	}
+
+	step, ok := totp.Validate(user.TotpSecret.String, req.Code, time.Now())
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidTOTPCode))
+		return
+	}
+
+	recoveryCodes, err := totp.GenerateRecoveryCodes(totp.RecoveryCodeCount)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	codeHashes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		codeHashes = append(codeHashes, token.HashOpaqueToken(totp.NormalizeRecoveryCode(code)))
+	}
+
+	err = server.store.EnableTOTPTx(ctx, db.EnableTOTPTxParams{
+		UserID:             user.ID,
+		Step:               step,
+		RecoveryCodeHashes: codeHashes,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errTOTPNotPending))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, verifyTOTPResponse{RecoveryCodes: recoveryCodes})
+}
+
+// disableTOTP handler
+type disableTOTPRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// disableTOTP godoc
+//
+//	@Summary					Disable TOTP
+//	@Description				Disable two-factor authentication of the logged user, it needs the password and a TOTP code or a recovery code
+//	@Tags						user,2fa
+//	@Accept						json
+//	@Produce					json
+//	@Success					200		{object}	string
+//
+//	@Param						code	body		disableTOTPRequest	true	"Password and Code"
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/me/2fa/totp [delete]
+func (server *Server) disableTOTP(ctx *gin.Context) {
+	var req disableTOTPRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	account, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = server.passwordHasher.Check(req.Password, account.Password)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUserTOTP(ctx, account.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !user.TotpEnabledAt.Valid {
+		ctx.JSON(http.StatusNotFound, errorResponse(errTOTPNotEnabled))
+		return
+	}
+
+	ok, err := server.verifySecondFactor(ctx, user, req.Code)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidTOTPCode))
+		return
+	}
+
+	err = server.store.DisableTOTPTx(ctx, user.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user.Username)
+}
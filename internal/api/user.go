@@ -2,6 +2,7 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 	"time"
 
@@ -12,88 +13,42 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-// createUser handler
-type createUserRequest struct {
-	Username string `json:"username" binding:"required,alphanum"`
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
-}
-
 type userResponse struct {
-	ID        uuid.UUID
-	Username  string
-	Email     string
-	CreatedAt time.Time
+	ID            uuid.UUID
+	Username      string
+	Email         string
+	EmailVerified bool
+	Disabled      bool
+	Role          string
+	CreatedAt     time.Time
 }
 
 func newUserResponse(user db.User) userResponse {
 	return userResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
+		ID:            user.ID,
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerifiedAt.Valid,
+		Disabled:      user.DisabledAt.Valid,
+		Role:          user.Role,
+		CreatedAt:     user.CreatedAt,
 	}
 }
 
-// createUser godoc
-//
-//	@Summary					Create a new User
-//	@Description				Create a new user
-//	@Tags						user,create
-//	@Accept						json
-//	@Produce					json
-//	@Success					200		{object}	userResponse
-//
-//	@Param						user	body		createUserRequest	true	"User Data"
-//	@securityDefinitions.apiKey	token
-//	@in							header
-//	@name						Authorization
-//	@Security					JWT
-//	@Router						/user [post]
-func (server *Server) createUser(ctx *gin.Context) {
-	var req createUserRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	hashedPassword, err := util.HashPassword(req.Password)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	arg := db.CreateUserParams{
-		Username: req.Username,
-		Email:    req.Email,
-		Password: hashedPassword,
-	}
-
-	user, err := server.store.CreateUser(ctx, arg)
-	if err != nil {
-		if db.ErrorCode(err) == db.UniqueViolation {
-			ctx.JSON(http.StatusForbidden, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	response := newUserResponse(user)
-	ctx.JSON(http.StatusOK, response)
-}
-
 // get User handler
 type getUserRequest struct {
 	ID string `uri:"id" binding:"required,uuid"`
 }
 
-func getUserResponse(user db.User) userResponse {
+func getUserResponse(user db.GetUserRow) userResponse {
 	return userResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
+		ID:            user.ID,
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerifiedAt.Valid,
+		Disabled:      user.DisabledAt.Valid,
+		Role:          user.Role,
+		CreatedAt:     user.CreatedAt,
 	}
 }
 
@@ -104,7 +59,7 @@ func getUserResponse(user db.User) userResponse {
 //	@Tags						user,get
 //	@Accept						json
 //	@Produce					json
-//	@Success					200	{object}	db.User
+//	@Success					200	{object}	userResponse
 //
 //	@Param						id	path		string	true	"id"
 //
@@ -173,6 +128,7 @@ type updateUserRequestData struct {
 	Username string `json:"username" binding:"omitempty,alphanum"`
 	Email    string `json:"email" binding:"omitempty,email"`
 	Password string `json:"password" binding:"omitempty"`
+	Role     string `json:"role" binding:"omitempty,oneof=admin editor author"`
 }
 type updateUserRequestID struct {
 	ID string `uri:"id" binding:"required,uuid"`
@@ -181,7 +137,7 @@ type updateUserRequestID struct {
 // updateUser godoc
 //
 //	@Summary					Update User
-//	@Description				Update the user information
+//	@Description				Update the user information, a new email takes effect once it is confirmed from a link sent to it
 //	@Tags						user,update
 //	@Accept						json
 //	@Produce					json
@@ -202,6 +158,7 @@ func (server *Server) updateUser(ctx *gin.Context) {
 	userID, err := uuid.Parse(userIDReq.ID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
 	}
 
 	var reqData updateUserRequestData
@@ -216,9 +173,12 @@ func (server *Server) updateUser(ctx *gin.Context) {
 
 	//Validate is the password is valid
 	if len(reqData.Password) > 0 {
-		hashedPassword, err := util.HashPassword(reqData.Password)
-
+		hashedPassword, err := server.hashNewPassword(reqData.Password)
 		if err != nil {
+			if errors.Is(err, util.ErrWeakPassword) {
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
 			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 			return
 		}
@@ -231,14 +191,51 @@ func (server *Server) updateUser(ctx *gin.Context) {
 		arg.Username = pgtype.Text{String: reqData.Username, Valid: true}
 	}
 
-	//Validate is the email is valid
+	//The new email takes effect once it is verified
 	if len(reqData.Email) > 0 {
-		arg.Email = pgtype.Text{String: reqData.Email, Valid: true}
+		current, err := server.store.GetUser(ctx, userID)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				ctx.JSON(http.StatusNotFound, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		if reqData.Email != current.Email {
+			err = server.requestEmailChange(ctx, current.ID, current.Username, current.Email, reqData.Email)
+			if err != nil {
+				if errors.Is(err, errEmailInUse) {
+					ctx.JSON(http.StatusForbidden, errorResponse(err))
+					return
+				}
+				if errors.Is(err, errTooManyEmails) {
+					ctx.JSON(http.StatusTooManyRequests, errorResponse(err))
+					return
+				}
+				ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+		}
+	}
+
+	//Validate is the role is valid
+	if len(reqData.Role) > 0 {
+		arg.Role = pgtype.Text{String: reqData.Role, Valid: true}
 	}
 
-	user, err := server.store.UpdateUser(ctx, arg)
+	user, err := server.store.UpdateUserTx(ctx, arg)
 
 	if err != nil {
+		if errors.Is(err, db.ErrLastAdmin) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
 		if db.ErrorCode(err) == db.UniqueViolation {
 			ctx.JSON(http.StatusForbidden, errorResponse(err))
 			return
@@ -247,12 +244,7 @@ func (server *Server) updateUser(ctx *gin.Context) {
 		return
 	}
 
-	response := userResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-	}
+	response := newUserResponse(user)
 	ctx.JSON(http.StatusOK, response)
 
 }
@@ -263,15 +255,21 @@ type deleteUserRequest struct {
 	ID string `uri:"id" binding:"required,uuid"`
 }
 
+type deleteUserRequestQuery struct {
+	TransferTo string `form:"transfer_to" binding:"omitempty,alphanum"`
+}
+
 // deleteUser godoc
 //
 //	@Summary					Delete User
-//	@Description				Delete the user register
+//	@Description				Deactivate the user: it can not login anymore, its sessions and personal access tokens are revoked.
+//	@Description				Its posts are transferred to the transfer_to user or left without author. Use the purge endpoint to delete the register.
 //	@Tags						user,delete
 //	@Accept						json
 //	@Produce					json
-//	@Param						id	path		string	true	"id"
-//	@Success					200	{object}	uuid.UUID
+//	@Param						id			path		string	true	"id"
+//	@Param						transfer_to	query		string	false	"username that receives the posts"
+//	@Success					200			{object}	uuid.UUID
 //	@securityDefinitions.apiKey	token
 //	@in							header
 //	@name						Authorization
@@ -284,22 +282,178 @@ func (server *Server) deleteUser(ctx *gin.Context) {
 		return
 	}
 
+	var reqQuery deleteUserRequestQuery
+	if err := ctx.ShouldBindQuery(&reqQuery); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
 	userID, err := uuid.Parse(req.ID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	err = server.store.DeleteUser(ctx, userID)
+	user, err := server.store.GetUser(ctx, userID)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	arg := db.DeactivateUserTxParams{
+		UserID:   user.ID,
+		Username: user.Username,
+	}
+
+	if len(reqQuery.TransferTo) > 0 {
+		receiver, err := server.store.GetUserByUsername(ctx, reqQuery.TransferTo)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidTransferUser))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		if receiver.ID == user.ID || receiver.DisabledAt.Valid {
+			ctx.JSON(http.StatusBadRequest, errorResponse(errInvalidTransferUser))
+			return
+		}
+		arg.TransferTo = pgtype.UUID{Bytes: receiver.ID, Valid: true}
+	}
+
+	err = server.store.DeactivateUserTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrLastAdmin) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusForbidden, errorResponse(errUserAlreadyDisabled))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, userID)
+}
+
+// enable User handler
+type enableUserRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// enableUser godoc
+//
+//	@Summary					Enable User
+//	@Description				Reactivate a deleted user, the posts that were transferred are not given back
+//	@Tags						user,update
+//	@Produce					json
+//	@Param						id	path		string	true	"id"
+//	@Success					200	{object}	uuid.UUID
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/user/{id}/enable [post]
+func (server *Server) enableUser(ctx *gin.Context) {
+	var req enableUserRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	userID, err := uuid.Parse(req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	enabled, err := server.store.EnableUser(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if enabled == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(db.ErrRecordNotFound))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, userID)
+}
+
+// purge User handler
+type purgeUserRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// purgeUser godoc
+//
+//	@Summary					Purge User
+//	@Description				Delete the register of a deleted user and all its data, it can not be undone
+//	@Tags						user,delete
+//	@Produce					json
+//	@Param						id	path		string	true	"id"
+//	@Success					200	{object}	uuid.UUID
+//	@securityDefinitions.apiKey	token
+//	@in							header
+//	@name						Authorization
+//	@Security					JWT
+//	@Router						/user/{id}/purge [delete]
+func (server *Server) purgeUser(ctx *gin.Context) {
+	var req purgeUserRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	userID, err := uuid.Parse(req.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
 			return
 		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !user.DisabledAt.Valid {
+		ctx.JSON(http.StatusForbidden, errorResponse(errUserNotDisabled))
+		return
+	}
 
+	err = server.store.PurgeUserTx(ctx, db.PurgeUserTxParams{
+		UserID:   user.ID,
+		Username: user.Username,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusForbidden, errorResponse(errUserNotDisabled))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
 	ctx.JSON(http.StatusOK, userID)
 }
+
+var (
+	errUserDisabled        = errors.New("the user is disabled")
+	errUserAlreadyDisabled = errors.New("the user is already disabled")
+	errUserNotDisabled     = errors.New("only deleted users can be purged")
+	errInvalidTransferUser = errors.New("the posts can only be transferred to another active user")
+)
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JairoRiver/personal_blog_backend/pkg/token"
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateUserRename(t *testing.T) {
+	store := newFakeStore()
+	server := newTestServer(t, store)
+	admin := randomUser(t, server, store, util.AdminRole, util.RandomString(12))
+	password := util.RandomString(12)
+	user := randomUser(t, server, store, util.AuthorRole, password)
+	other := randomUser(t, server, store, util.AuthorRole, util.RandomString(12))
+
+	// The user has logged in before, so a session references the username
+	login := loginForTest(t, server, user.Username, password)
+
+	updateUser := func(body gin.H) *httptest.ResponseRecorder {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		request := httptest.NewRequest(http.MethodPut, "/v1/user/"+user.ID.String(), bytes.NewReader(data))
+		addAuthorization(t, request, server.tokenMaker, admin, token.AccessToken)
+
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	recorder := updateUser(gin.H{"username": other.Username})
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+
+	newUsername := util.RandomString(10)
+	recorder = updateUser(gin.H{"username": newUsername})
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp userResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, newUsername, rsp.Username)
+	require.Equal(t, newUsername, store.session(login.SessionID).Username)
+
+	// The user logs in with the new username
+	loginForTest(t, server, newUsername, password)
+}
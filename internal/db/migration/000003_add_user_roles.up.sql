@@ -0,0 +1,6 @@
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'author';
+
+-- Before roles existed every account could manage the whole blog
+UPDATE "users" SET "role" = 'admin';
+
+ALTER TABLE "users" ADD CONSTRAINT "users_role_check" CHECK ("role" IN ('admin', 'editor', 'author'));
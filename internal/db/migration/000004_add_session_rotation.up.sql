@@ -0,0 +1,12 @@
+ALTER TABLE "sessions" ADD COLUMN "family_id" uuid;
+ALTER TABLE "sessions" ADD COLUMN "parent_id" uuid;
+ALTER TABLE "sessions" ADD COLUMN "rotated_at" timestamptz;
+
+-- Every existing session starts its own rotation chain
+UPDATE "sessions" SET "family_id" = "id";
+
+ALTER TABLE "sessions" ALTER COLUMN "family_id" SET NOT NULL;
+
+ALTER TABLE "sessions" ADD FOREIGN KEY ("parent_id") REFERENCES "sessions" ("id") ON DELETE SET NULL;
+
+CREATE INDEX ON "sessions" ("family_id");
@@ -0,0 +1,15 @@
+CREATE TABLE "personal_access_tokens" (
+  "id" uuid PRIMARY KEY DEFAULT (uuid_generate_v4()),
+  "user_id" uuid NOT NULL,
+  "name" varchar NOT NULL,
+  "token_hash" varchar UNIQUE NOT NULL,
+  "scopes" varchar[] NOT NULL,
+  "last_used_at" timestamptz,
+  "expires_at" timestamptz,
+  "revoked_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "personal_access_tokens" ("user_id");
+
+ALTER TABLE "personal_access_tokens" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
@@ -0,0 +1,26 @@
+ALTER TABLE "users" ADD COLUMN "totp_secret" varchar;
+ALTER TABLE "users" ADD COLUMN "totp_enabled_at" timestamptz;
+ALTER TABLE "users" ADD COLUMN "totp_last_used_step" bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE "recovery_codes" (
+  "id" uuid PRIMARY KEY DEFAULT (uuid_generate_v4()),
+  "user_id" uuid NOT NULL,
+  "code_hash" varchar NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "recovery_codes" ("user_id", "code_hash");
+
+ALTER TABLE "recovery_codes" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE TABLE "mfa_challenges" (
+  "id" uuid PRIMARY KEY DEFAULT (uuid_generate_v4()),
+  "user_id" uuid NOT NULL,
+  "token_hash" varchar UNIQUE NOT NULL,
+  "attempts" int NOT NULL DEFAULT 0,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "mfa_challenges" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
@@ -0,0 +1,26 @@
+CREATE TABLE "webauthn_credentials" (
+  "id" uuid PRIMARY KEY DEFAULT (uuid_generate_v4()),
+  "user_id" uuid NOT NULL,
+  "name" varchar NOT NULL,
+  "credential_id" bytea UNIQUE NOT NULL,
+  "credential" jsonb NOT NULL,
+  "last_used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "webauthn_credentials" ("user_id");
+
+ALTER TABLE "webauthn_credentials" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+CREATE TABLE "webauthn_sessions" (
+  "id" uuid PRIMARY KEY DEFAULT (uuid_generate_v4()),
+  "user_id" uuid,
+  "ceremony" varchar NOT NULL,
+  "session_data" jsonb NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "webauthn_sessions" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "webauthn_sessions" ADD CONSTRAINT "webauthn_sessions_ceremony_check" CHECK ("ceremony" IN ('registration', 'login'));
@@ -0,0 +1,10 @@
+CREATE TABLE "login_attempts" (
+  "scope" varchar NOT NULL,
+  "key" varchar NOT NULL,
+  "failures" int NOT NULL DEFAULT 0,
+  "last_failure_at" timestamptz NOT NULL DEFAULT (now()),
+  "locked_until" timestamptz,
+  PRIMARY KEY ("scope", "key")
+);
+
+ALTER TABLE "login_attempts" ADD CONSTRAINT "login_attempts_scope_check" CHECK ("scope" IN ('username', 'ip'));
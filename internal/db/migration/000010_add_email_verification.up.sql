@@ -0,0 +1,18 @@
+ALTER TABLE "users" ADD COLUMN "email_verified_at" timestamptz;
+
+-- The existing accounts were created by the admins, their emails are trusted
+UPDATE "users" SET "email_verified_at" = now();
+
+CREATE TABLE "email_verifications" (
+  "id" uuid PRIMARY KEY DEFAULT (uuid_generate_v4()),
+  "user_id" uuid NOT NULL,
+  "email" varchar NOT NULL,
+  "token_hash" varchar UNIQUE NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "email_verifications" ("user_id");
+
+ALTER TABLE "email_verifications" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
@@ -0,0 +1,15 @@
+CREATE TABLE "invites" (
+  "id" uuid PRIMARY KEY DEFAULT (uuid_generate_v4()),
+  "email" varchar NOT NULL,
+  "role" varchar NOT NULL,
+  "token_hash" varchar UNIQUE NOT NULL,
+  "invited_by" uuid,
+  "expires_at" timestamptz NOT NULL,
+  "accepted_at" timestamptz,
+  "revoked_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "invites" ADD FOREIGN KEY ("invited_by") REFERENCES "users" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "invites" ADD CONSTRAINT "invites_role_check" CHECK ("role" IN ('admin', 'editor', 'author'));
@@ -0,0 +1,5 @@
+ALTER TABLE "users" DROP COLUMN IF EXISTS "avatar_url";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "social_links";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "website";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "bio";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "display_name";
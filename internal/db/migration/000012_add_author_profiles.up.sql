@@ -0,0 +1,5 @@
+ALTER TABLE "users" ADD COLUMN "display_name" varchar NOT NULL DEFAULT '';
+ALTER TABLE "users" ADD COLUMN "bio" text NOT NULL DEFAULT '';
+ALTER TABLE "users" ADD COLUMN "website" varchar NOT NULL DEFAULT '';
+ALTER TABLE "users" ADD COLUMN "social_links" jsonb NOT NULL DEFAULT '{}';
+ALTER TABLE "users" ADD COLUMN "avatar_url" varchar NOT NULL DEFAULT '';
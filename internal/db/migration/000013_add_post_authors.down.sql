@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "post_authors";
+
+ALTER TABLE "posts" DROP COLUMN IF EXISTS "last_edited_by";
+ALTER TABLE "posts" DROP COLUMN IF EXISTS "author_id";
@@ -0,0 +1,26 @@
+ALTER TABLE "posts" ADD COLUMN "author_id" uuid;
+ALTER TABLE "posts" ADD COLUMN "last_edited_by" uuid;
+
+-- The existing posts were written by the first admin
+UPDATE "posts" SET "author_id" = (
+  SELECT "id" FROM "users" WHERE "role" = 'admin' ORDER BY "created_at" LIMIT 1
+);
+
+CREATE TABLE "post_authors" (
+  "post_id" uuid NOT NULL,
+  "user_id" uuid NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("post_id", "user_id")
+);
+
+CREATE INDEX ON "posts" ("author_id");
+
+CREATE INDEX ON "post_authors" ("user_id");
+
+ALTER TABLE "posts" ADD FOREIGN KEY ("author_id") REFERENCES "users" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "posts" ADD FOREIGN KEY ("last_edited_by") REFERENCES "users" ("id") ON DELETE SET NULL;
+
+ALTER TABLE "post_authors" ADD FOREIGN KEY ("post_id") REFERENCES "posts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "post_authors" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
@@ -0,0 +1,22 @@
+CREATE TABLE "oidc_logins" (
+  "id" uuid PRIMARY KEY DEFAULT (uuid_generate_v4()),
+  "state_hash" varchar UNIQUE NOT NULL,
+  "nonce" varchar NOT NULL,
+  "code_verifier" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "user_identities" (
+  "id" uuid PRIMARY KEY DEFAULT (uuid_generate_v4()),
+  "user_id" uuid NOT NULL,
+  "issuer" varchar NOT NULL,
+  "subject" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "user_identities" ("issuer", "subject");
+
+CREATE INDEX ON "user_identities" ("user_id");
+
+ALTER TABLE "user_identities" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
@@ -0,0 +1,23 @@
+ALTER TABLE "posts" ADD COLUMN "slug" varchar;
+
+-- The existing posts get an ASCII slug of their title, authors can change it later
+UPDATE "posts" SET "slug" = trim(both '-' from regexp_replace(lower("title"), '[^a-z0-9]+', '-', 'g'));
+
+UPDATE "posts" SET "slug" = "id"::text WHERE "slug" = '';
+
+UPDATE "posts" SET "slug" = "slug" || '-' || left("id"::text, 8)
+WHERE "slug" IN (SELECT "slug" FROM "posts" GROUP BY "slug" HAVING COUNT(*) > 1);
+
+ALTER TABLE "posts" ALTER COLUMN "slug" SET NOT NULL;
+
+ALTER TABLE "posts" ADD CONSTRAINT "posts_slug_key" UNIQUE ("slug");
+
+CREATE TABLE "post_slugs" (
+  "slug" varchar PRIMARY KEY,
+  "post_id" uuid NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "post_slugs" ("post_id");
+
+ALTER TABLE "post_slugs" ADD FOREIGN KEY ("post_id") REFERENCES "posts" ("id") ON DELETE CASCADE;
@@ -0,0 +1,4 @@
+ALTER TABLE "tags" DROP COLUMN IF EXISTS "description";
+ALTER TABLE "tags" DROP COLUMN IF EXISTS "slug";
+ALTER TABLE "categories" DROP COLUMN IF EXISTS "description";
+ALTER TABLE "categories" DROP COLUMN IF EXISTS "slug";
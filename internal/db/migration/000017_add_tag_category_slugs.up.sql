@@ -0,0 +1,26 @@
+ALTER TABLE "categories" ADD COLUMN "slug" varchar;
+ALTER TABLE "categories" ADD COLUMN "description" text NOT NULL DEFAULT '';
+
+ALTER TABLE "tags" ADD COLUMN "slug" varchar;
+ALTER TABLE "tags" ADD COLUMN "description" text NOT NULL DEFAULT '';
+
+-- The existing categories and tags get an ASCII slug of their name, editors can change it later
+UPDATE "categories" SET "slug" = trim(both '-' from regexp_replace(lower("name"), '[^a-z0-9]+', '-', 'g'));
+
+UPDATE "categories" SET "slug" = "id"::text WHERE "slug" = '';
+
+UPDATE "categories" SET "slug" = "slug" || '-' || left("id"::text, 8)
+WHERE "slug" IN (SELECT "slug" FROM "categories" GROUP BY "slug" HAVING COUNT(*) > 1);
+
+UPDATE "tags" SET "slug" = trim(both '-' from regexp_replace(lower("name"), '[^a-z0-9]+', '-', 'g'));
+
+UPDATE "tags" SET "slug" = "id"::text WHERE "slug" = '';
+
+UPDATE "tags" SET "slug" = "slug" || '-' || left("id"::text, 8)
+WHERE "slug" IN (SELECT "slug" FROM "tags" GROUP BY "slug" HAVING COUNT(*) > 1);
+
+ALTER TABLE "categories" ALTER COLUMN "slug" SET NOT NULL;
+ALTER TABLE "categories" ADD CONSTRAINT "categories_slug_key" UNIQUE ("slug");
+
+ALTER TABLE "tags" ALTER COLUMN "slug" SET NOT NULL;
+ALTER TABLE "tags" ADD CONSTRAINT "tags_slug_key" UNIQUE ("slug");
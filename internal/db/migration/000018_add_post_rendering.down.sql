@@ -0,0 +1,4 @@
+ALTER TABLE "posts" DROP COLUMN IF EXISTS "render_version";
+ALTER TABLE "posts" DROP COLUMN IF EXISTS "content_toc";
+ALTER TABLE "posts" DROP COLUMN IF EXISTS "content_html";
+ALTER TABLE "posts" DROP COLUMN IF EXISTS "content_format";
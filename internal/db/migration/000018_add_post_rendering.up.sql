@@ -0,0 +1,8 @@
+ALTER TABLE "posts" ADD COLUMN "content_format" varchar NOT NULL DEFAULT 'markdown';
+ALTER TABLE "posts" ADD COLUMN "content_html" text NOT NULL DEFAULT '';
+ALTER TABLE "posts" ADD COLUMN "content_toc" jsonb NOT NULL DEFAULT '[]';
+
+-- The posts with an older render version are rendered again when the server starts
+ALTER TABLE "posts" ADD COLUMN "render_version" integer NOT NULL DEFAULT 0;
+
+ALTER TABLE "posts" ADD CONSTRAINT "posts_content_format_check" CHECK ("content_format" IN ('markdown', 'html'));
@@ -0,0 +1 @@
+ALTER TABLE "posts" ADD COLUMN "render_warnings" jsonb NOT NULL DEFAULT '[]';
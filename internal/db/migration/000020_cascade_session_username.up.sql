@@ -0,0 +1,3 @@
+-- Renaming a user must not fail because of the sessions it already has
+ALTER TABLE "sessions" DROP CONSTRAINT IF EXISTS "sessions_username_fkey";
+ALTER TABLE "sessions" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON UPDATE CASCADE;
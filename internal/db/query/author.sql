@@ -0,0 +1,45 @@
+-- name: ListAuthors :many
+SELECT u.username
+      ,u.display_name
+      ,u.bio
+      ,u.website
+      ,u.social_links
+      ,u.avatar_url
+      ,u.created_at
+FROM users as u
+WHERE u.disabled_at IS NULL
+ORDER BY u.username;
+
+-- name: GetAuthorByUsername :one
+SELECT u.username
+      ,u.display_name
+      ,u.bio
+      ,u.website
+      ,u.social_links
+      ,u.avatar_url
+      ,u.created_at
+FROM users as u
+WHERE u.username = $1
+  AND u.disabled_at IS NULL
+LIMIT 1;
+
+-- name: UpdateAuthorProfile :one
+UPDATE users
+SET
+  display_name = COALESCE(sqlc.narg(display_name), display_name),
+  bio = COALESCE(sqlc.narg(bio), bio),
+  website = COALESCE(sqlc.narg(website), website),
+  social_links = COALESCE(sqlc.narg(social_links), social_links),
+  updated_at = NOW()
+WHERE
+  id = sqlc.arg(id)
+RETURNING *;
+
+-- name: SetUserAvatar :one
+UPDATE users
+SET
+  avatar_url = $2,
+  updated_at = NOW()
+WHERE
+  id = $1
+RETURNING *;
@@ -1,26 +1,41 @@
 -- name: CreateCategory :one
 INSERT INTO categories (
-  name
+  name,
+  slug,
+  description
 ) VALUES (
-  $1
+  $1,$2,$3
 ) RETURNING *;
 
--- name: GetCategory :one
+-- name: LookupCategory :one
 SELECT ca.id
       ,ca.name
+      ,ca.slug
+      ,ca.description
       ,ca.created_at
       ,ca.updated_at
 FROM categories as ca
-WHERE ca.id = $1 
+WHERE ca.id = sqlc.narg(id)
+   OR ca.slug = sqlc.narg(slug)
 LIMIT 1;
 
 -- name: ListCategories :many
 SELECT ca.id
       ,ca.name
+      ,ca.slug
+      ,ca.description
       ,ca.created_at
       ,ca.updated_at
 FROM categories as ca;
 
+-- name: CategorySlugExists :one
+SELECT EXISTS (
+  SELECT 1
+  FROM categories
+  WHERE slug = sqlc.arg(slug)
+    AND id <> sqlc.arg(category_id)
+);
+
 -- name: DeleteCategory :exec
 DELETE FROM categories
 WHERE id = $1;
@@ -29,7 +44,9 @@ WHERE id = $1;
 UPDATE categories
 SET
   name = COALESCE(sqlc.narg(name), name),
+  slug = COALESCE(sqlc.narg(slug), slug),
+  description = COALESCE(sqlc.narg(description), description),
   updated_at = NOW()
 WHERE
   id = sqlc.arg(id)
-RETURNING *;
\ No newline at end of file
+RETURNING *;
@@ -0,0 +1,45 @@
+-- name: CreateEmailVerification :one
+INSERT INTO email_verifications (
+  user_id,
+  email,
+  token_hash,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetEmailVerificationByHash :one
+SELECT * FROM email_verifications
+WHERE token_hash = $1
+LIMIT 1;
+
+-- name: CountRecentEmailVerifications :one
+SELECT COUNT(*)
+FROM email_verifications
+WHERE user_id = $1
+  AND created_at > sqlc.arg(created_after)::timestamptz;
+
+-- name: UseEmailVerification :execrows
+UPDATE email_verifications
+SET used_at = NOW()
+WHERE id = $1
+  AND used_at IS NULL
+  AND expires_at > NOW();
+
+-- name: DeleteEmailVerifications :exec
+DELETE FROM email_verifications
+WHERE user_id = $1
+  AND used_at IS NULL;
+
+-- name: DeleteExpiredEmailVerifications :execrows
+DELETE FROM email_verifications
+WHERE expires_at <= NOW();
+
+-- name: VerifyUserEmail :one
+UPDATE users
+SET
+  email = $2,
+  email_verified_at = NOW(),
+  updated_at = NOW()
+WHERE id = $1
+RETURNING *;
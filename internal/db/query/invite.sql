@@ -0,0 +1,44 @@
+-- name: CreateInvite :one
+INSERT INTO invites (
+  email,
+  role,
+  token_hash,
+  invited_by,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetInviteByHash :one
+SELECT * FROM invites
+WHERE token_hash = $1
+LIMIT 1;
+
+-- name: ListPendingInvites :many
+SELECT i.id
+      ,i.email
+      ,i.role
+      ,i.expires_at
+      ,i.created_at
+      ,u.username AS invited_by
+FROM invites AS i
+LEFT JOIN users AS u ON i.invited_by = u.id
+WHERE i.accepted_at IS NULL
+  AND i.revoked_at IS NULL
+  AND i.expires_at > NOW()
+ORDER BY i.created_at DESC;
+
+-- name: AcceptInvite :execrows
+UPDATE invites
+SET accepted_at = NOW()
+WHERE id = $1
+  AND accepted_at IS NULL
+  AND revoked_at IS NULL
+  AND expires_at > NOW();
+
+-- name: RevokeInvite :execrows
+UPDATE invites
+SET revoked_at = NOW()
+WHERE id = $1
+  AND accepted_at IS NULL
+  AND revoked_at IS NULL;
@@ -0,0 +1,42 @@
+-- name: GetLoginLock :one
+SELECT locked_until
+FROM login_attempts
+WHERE ((scope = 'username' AND key = sqlc.arg(username))
+    OR (scope = 'ip' AND key = sqlc.arg(client_ip)))
+  AND locked_until > NOW()
+ORDER BY locked_until DESC
+LIMIT 1;
+
+-- name: RecordLoginFailure :one
+INSERT INTO login_attempts (
+  scope,
+  key,
+  failures,
+  last_failure_at
+) VALUES (
+  $1, $2, 1, NOW()
+)
+ON CONFLICT (scope, key) DO UPDATE
+SET
+  failures = CASE
+    WHEN login_attempts.last_failure_at < sqlc.arg(reset_before)::timestamptz THEN 1
+    ELSE login_attempts.failures + 1
+  END,
+  last_failure_at = NOW()
+RETURNING *;
+
+-- name: LockLogin :exec
+UPDATE login_attempts
+SET locked_until = $3
+WHERE scope = $1
+  AND key = $2;
+
+-- name: ResetLoginAttempts :exec
+DELETE FROM login_attempts
+WHERE scope = $1
+  AND key = $2;
+
+-- name: DeleteStaleLoginAttempts :execrows
+DELETE FROM login_attempts
+WHERE last_failure_at < sqlc.arg(reset_before)::timestamptz
+  AND (locked_until IS NULL OR locked_until <= NOW());
@@ -0,0 +1,33 @@
+-- name: CreateOIDCLogin :one
+INSERT INTO oidc_logins (
+  state_hash,
+  nonce,
+  code_verifier,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ConsumeOIDCLogin :one
+DELETE FROM oidc_logins
+WHERE state_hash = $1
+RETURNING *;
+
+-- name: DeleteExpiredOIDCLogins :execrows
+DELETE FROM oidc_logins
+WHERE expires_at <= NOW();
+
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities (
+  user_id,
+  issuer,
+  subject
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: GetUserIdentity :one
+SELECT * FROM user_identities
+WHERE issuer = $1
+  AND subject = $2
+LIMIT 1;
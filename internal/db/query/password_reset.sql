@@ -0,0 +1,41 @@
+-- name: CreatePasswordResetToken :one
+INSERT INTO password_reset_tokens (
+  user_id,
+  token_hash,
+  expires_at
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: GetPasswordResetTokenByHash :one
+SELECT prt.id
+      ,prt.user_id
+      ,prt.expires_at
+      ,prt.used_at
+      ,u.username
+FROM password_reset_tokens AS prt
+JOIN users AS u ON prt.user_id = u.id
+WHERE prt.token_hash = $1
+LIMIT 1;
+
+-- name: CountRecentPasswordResetTokens :one
+SELECT COUNT(*)
+FROM password_reset_tokens
+WHERE user_id = $1
+  AND created_at > sqlc.arg(created_after)::timestamptz;
+
+-- name: UsePasswordResetToken :execrows
+UPDATE password_reset_tokens
+SET used_at = NOW()
+WHERE id = $1
+  AND used_at IS NULL
+  AND expires_at > NOW();
+
+-- name: DeletePasswordResetTokens :exec
+DELETE FROM password_reset_tokens
+WHERE user_id = $1
+  AND used_at IS NULL;
+
+-- name: DeleteExpiredPasswordResetTokens :execrows
+DELETE FROM password_reset_tokens
+WHERE expires_at <= NOW();
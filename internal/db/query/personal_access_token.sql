@@ -0,0 +1,50 @@
+-- name: CreatePersonalAccessToken :one
+INSERT INTO personal_access_tokens (
+  user_id,
+  name,
+  token_hash,
+  scopes,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetPersonalAccessTokenByHash :one
+SELECT pat.id
+      ,pat.user_id
+      ,pat.name
+      ,pat.scopes
+      ,pat.expires_at
+      ,pat.revoked_at
+      ,pat.created_at
+      ,u.username
+      ,u.role
+      ,u.disabled_at
+FROM personal_access_tokens AS pat
+JOIN users AS u ON pat.user_id = u.id
+WHERE pat.token_hash = $1
+LIMIT 1;
+
+-- name: ListPersonalAccessTokens :many
+SELECT * FROM personal_access_tokens
+WHERE user_id = $1
+  AND revoked_at IS NULL
+ORDER BY created_at DESC;
+
+-- name: TouchPersonalAccessToken :exec
+UPDATE personal_access_tokens
+SET last_used_at = NOW()
+WHERE id = $1;
+
+-- name: RevokePersonalAccessToken :execrows
+UPDATE personal_access_tokens
+SET revoked_at = NOW()
+WHERE id = $1
+  AND user_id = $2
+  AND revoked_at IS NULL;
+
+-- name: RevokeUserPersonalAccessTokens :exec
+UPDATE personal_access_tokens
+SET revoked_at = NOW()
+WHERE user_id = $1
+  AND revoked_at IS NULL;
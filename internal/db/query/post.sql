@@ -5,160 +5,314 @@ INSERT INTO posts (
  ,subtitle
  ,content
  ,publicated
+ ,author_id
+ ,slug
+ ,content_format
+ ,content_html
+ ,content_toc
+ ,render_version
+ ,render_warnings
 ) VALUES (
-  $1,$2,$3,$4,$5
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12
 ) RETURNING *;
 
 -- name: GetPostByIdPublic :one
 SELECT po.id
       ,po.title
+      ,po.slug
       ,po.subtitle
       ,po.content
+      ,po.content_format
+      ,po.content_html
+      ,po.content_toc
       ,po.publicated
       ,po.category_id
       ,po.created_at
       ,po.updated_at
       ,ca.name AS category_name
-      ,ARRAY_AGG(ta.name || "|" || ta.id) AS tags
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
+      ,ARRAY_AGG(ta.name || '|' || ta.id::text) AS tags
 FROM posts AS po
 JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
 LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
 LEFT JOIN tags AS ta on pt.tag_id = ta.id 
 WHERE po.id = $1
   AND po.publicated IS TRUE
-GROUP BY 1,2,3,4,5,6,7,8
+GROUP BY 1,2,3,4,5,6,7,8,9,10,11,12,au.username
+LIMIT 1;
+
+-- name: GetPostBySlugPublic :one
+SELECT po.id
+      ,po.title
+      ,po.slug
+      ,po.subtitle
+      ,po.content
+      ,po.content_format
+      ,po.content_html
+      ,po.content_toc
+      ,po.publicated
+      ,po.category_id
+      ,po.created_at
+      ,po.updated_at
+      ,ca.name AS category_name
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
+      ,ARRAY_AGG(ta.name || '|' || ta.id::text) AS tags
+FROM posts AS po
+JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
+LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
+LEFT JOIN tags AS ta on pt.tag_id = ta.id 
+WHERE po.slug = $1
+  AND po.publicated IS TRUE
+GROUP BY 1,2,3,4,5,6,7,8,9,10,11,12,au.username
 LIMIT 1;
 
 -- name: GetPostByIdPrivate :one
 SELECT po.id
       ,po.title
+      ,po.slug
       ,po.subtitle
       ,po.content
+      ,po.content_format
+      ,po.content_html
+      ,po.content_toc
+      ,po.render_warnings
       ,po.publicated
       ,po.category_id
       ,po.created_at
       ,po.updated_at
       ,ca.name AS category_name
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
+      ,ed.username AS last_edited_by
       ,ARRAY_AGG(ta.name) AS tags
 FROM posts AS po
 JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
+LEFT JOIN users AS ed ON po.last_edited_by = ed.id
 LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
 LEFT JOIN tags AS ta on pt.tag_id = ta.id 
 WHERE po.id = $1
-GROUP BY 1,2,3,4,5,6,7,8
+GROUP BY 1,2,3,4,5,6,7,8,9,10,11,12,13,au.username,ed.username
 LIMIT 1;
 
 -- name: GetPostByCategoryPublic :many
 SELECT po.id
       ,po.title
+      ,po.slug
       ,po.subtitle
       ,po.content
+      ,po.content_format
+      ,po.content_html
+      ,po.content_toc
       ,po.publicated
       ,po.category_id
       ,po.created_at
       ,po.updated_at
       ,ca.name AS category_name
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
       ,ARRAY_AGG(ta.name) AS tags
 FROM posts AS po
 JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
 LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
 LEFT JOIN tags AS ta on pt.tag_id = ta.id 
 WHERE ca.id = $1
   AND po.publicated IS TRUE
-GROUP BY 1,2,3,4,5,6,7,8;
+GROUP BY 1,2,3,4,5,6,7,8,9,10,11,12,au.username;
 
 -- name: GetPostByCategoryPrivate :many
 SELECT po.id
       ,po.title
+      ,po.slug
       ,po.subtitle
       ,po.content
+      ,po.content_format
+      ,po.content_html
+      ,po.content_toc
       ,po.publicated
       ,po.category_id
       ,po.created_at
       ,po.updated_at
       ,ca.name AS category_name
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
+      ,ed.username AS last_edited_by
       ,ARRAY_AGG(ta.name) AS tags
 FROM posts AS po
 JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
+LEFT JOIN users AS ed ON po.last_edited_by = ed.id
 LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
 LEFT JOIN tags AS ta on pt.tag_id = ta.id 
 WHERE ca.id = $1
-GROUP BY 1,2,3,4,5,6,7,8;
+GROUP BY 1,2,3,4,5,6,7,8,9,10,11,12,au.username,ed.username;
 
 -- name: GetPostByTagPublic :many
 SELECT po.id
       ,po.title
+      ,po.slug
       ,po.subtitle
       ,po.content
+      ,po.content_format
+      ,po.content_html
+      ,po.content_toc
       ,po.publicated
       ,po.category_id
       ,po.created_at
       ,po.updated_at
       ,ca.name AS category_name
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
       ,ARRAY_AGG(ta.name) AS tags
 FROM posts AS po
 JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
 LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
 LEFT JOIN tags AS ta on pt.tag_id = ta.id 
 WHERE ta.id = $1
   AND po.publicated IS TRUE
-GROUP BY 1,2,3,4,5,6,7,8;
+GROUP BY 1,2,3,4,5,6,7,8,9,10,11,12,au.username;
 
 -- name: GetPostByTagPrivate :many
 SELECT po.id
       ,po.title
+      ,po.slug
       ,po.subtitle
       ,po.content
+      ,po.content_format
+      ,po.content_html
+      ,po.content_toc
       ,po.publicated
       ,po.category_id
       ,po.created_at
       ,po.updated_at
       ,ca.name AS category_name
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
+      ,ed.username AS last_edited_by
       ,ARRAY_AGG(ta.name) AS tags
 FROM posts AS po
 JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
+LEFT JOIN users AS ed ON po.last_edited_by = ed.id
 LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
 LEFT JOIN tags AS ta on pt.tag_id = ta.id 
 WHERE ta.id = $1
-GROUP BY 1,2,3,4,5,6,7,8;
+GROUP BY 1,2,3,4,5,6,7,8,9,10,11,12,au.username,ed.username;
 
 -- name: ListPostsPublic :many
 SELECT po.id
       ,po.title
+      ,po.slug
       ,po.subtitle
       ,po.created_at
       ,po.category_id
       ,ca.name AS category_name
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
       ,ARRAY_AGG(ta.name) AS tags
 FROM posts AS po
 JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
 LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
 LEFT JOIN tags AS ta on pt.tag_id = ta.id
 WHERE po.publicated IS TRUE
-GROUP BY 1,2,3,4,5,6;
+GROUP BY 1,2,3,4,5,6,7,au.username;
 
 -- name: ListPostsPrivate :many
 SELECT po.id
       ,po.title
+      ,po.slug
       ,po.subtitle
       ,po.created_at
       ,po.category_id
       ,ca.name AS category_name
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
+      ,ed.username AS last_edited_by
       ,ARRAY_AGG(ta.name) AS tags
 FROM posts AS po
 JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
+LEFT JOIN users AS ed ON po.last_edited_by = ed.id
 LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
 LEFT JOIN tags AS ta on pt.tag_id = ta.id
-GROUP BY 1,2,3,4,5,6;
+GROUP BY 1,2,3,4,5,6,7,au.username,ed.username;
 
 -- name: UpdatePost :one
 UPDATE posts
 SET
   title = COALESCE(sqlc.narg(title), title)
+ ,slug = COALESCE(sqlc.narg(slug), slug)
  ,subtitle = COALESCE(sqlc.narg(subtitle), subtitle)
  ,content = COALESCE(sqlc.narg(content), content)
+ ,content_format = COALESCE(sqlc.narg(content_format), content_format)
+ ,content_html = COALESCE(sqlc.narg(content_html), content_html)
+ ,content_toc = COALESCE(sqlc.narg(content_toc), content_toc)
+ ,render_version = COALESCE(sqlc.narg(render_version), render_version)
+ ,render_warnings = COALESCE(sqlc.narg(render_warnings), render_warnings)
  ,publicated = COALESCE(sqlc.narg(publicated), publicated)
  ,category_id = COALESCE(sqlc.narg(category_id), category_id)
+ ,last_edited_by = sqlc.arg(last_edited_by)
  ,updated_at = NOW()
 WHERE
   id = sqlc.arg(id)
@@ -166,4 +320,171 @@ RETURNING *;
 
 -- name: DeletePost :exec
 DELETE FROM posts
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: GetPostsByAuthorPublic :many
+SELECT po.id
+      ,po.title
+      ,po.slug
+      ,po.subtitle
+      ,po.created_at
+      ,po.category_id
+      ,ca.name AS category_name
+      ,ARRAY_AGG(ta.name) AS tags
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
+FROM posts AS po
+JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
+LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
+LEFT JOIN tags AS ta on pt.tag_id = ta.id
+WHERE po.publicated IS TRUE
+  AND (
+    au.username = $1
+    OR EXISTS (
+      SELECT 1
+      FROM post_authors AS pa
+      JOIN users AS cu ON pa.user_id = cu.id
+      WHERE pa.post_id = po.id
+        AND cu.username = $1
+    )
+  )
+GROUP BY 1,2,3,4,5,6,7,au.username;
+
+-- name: GetPostAccess :one
+SELECT po.author_id
+      ,EXISTS (
+         SELECT 1
+         FROM post_authors AS pa
+         WHERE pa.post_id = po.id
+           AND pa.user_id = sqlc.arg(user_id)
+       ) AS is_co_author
+FROM posts AS po
+WHERE po.id = sqlc.arg(post_id)
+LIMIT 1;
+
+-- name: AddPostAuthor :exec
+INSERT INTO post_authors (
+  post_id
+ ,user_id
+) VALUES (
+  $1,$2
+) ON CONFLICT DO NOTHING;
+
+-- name: DeletePostAuthors :exec
+DELETE FROM post_authors
+WHERE post_id = $1;
+
+-- name: TransferPostAuthor :exec
+UPDATE posts
+SET author_id = sqlc.narg(to_user_id)
+WHERE author_id = sqlc.arg(from_user_id);
+
+-- name: ClearPostEditor :exec
+UPDATE posts
+SET last_edited_by = NULL
+WHERE last_edited_by = $1;
+
+-- name: TransferPostCoAuthors :exec
+INSERT INTO post_authors (
+  post_id
+ ,user_id
+)
+SELECT pa.post_id
+      ,sqlc.arg(to_user_id)
+FROM post_authors AS pa
+JOIN posts AS po ON pa.post_id = po.id
+WHERE pa.user_id = sqlc.arg(from_user_id)
+  AND po.author_id IS DISTINCT FROM sqlc.arg(to_user_id)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteUserPostAuthors :exec
+DELETE FROM post_authors
+WHERE user_id = $1;
+
+-- name: GetPostSlug :one
+SELECT slug FROM posts
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: PostSlugExists :one
+SELECT EXISTS (
+  SELECT 1
+  FROM posts
+  WHERE slug = sqlc.arg(slug)
+    AND id <> sqlc.arg(post_id)
+);
+
+-- name: AddPostSlugHistory :exec
+INSERT INTO post_slugs (
+  slug
+ ,post_id
+) VALUES (
+  $1,$2
+) ON CONFLICT (slug) DO UPDATE
+SET post_id = EXCLUDED.post_id
+   ,created_at = NOW();
+
+-- name: DeletePostSlugHistory :exec
+DELETE FROM post_slugs
+WHERE slug = $1;
+
+-- name: GetPostSlugRedirectPublic :one
+SELECT po.slug
+FROM post_slugs AS ps
+JOIN posts AS po ON ps.post_id = po.id
+WHERE ps.slug = $1
+  AND po.publicated IS TRUE
+LIMIT 1;
+
+-- name: ListStaleRenderedPosts :many
+SELECT id
+      ,content_format
+      ,content
+      ,updated_at
+FROM posts
+WHERE render_version < sqlc.arg(render_version)
+ORDER BY created_at
+LIMIT sqlc.arg(limit_count);
+
+-- name: SetPostRendering :execrows
+UPDATE posts
+SET
+  content_html = sqlc.arg(content_html)
+ ,content_toc = sqlc.arg(content_toc)
+ ,render_version = sqlc.arg(render_version)
+ ,render_warnings = sqlc.arg(render_warnings)
+WHERE id = sqlc.arg(id)
+  AND updated_at = sqlc.arg(updated_at);
+
+-- name: ListPostRenderWarnings :many
+SELECT id
+      ,title
+      ,slug
+      ,render_warnings
+      ,updated_at
+FROM posts
+WHERE render_warnings <> '[]'::jsonb
+ORDER BY updated_at DESC;
+
+-- name: GetPostLink :one
+SELECT id
+      ,title
+      ,slug
+      ,publicated
+FROM posts
+WHERE id = $1
+LIMIT 1;
+
+-- name: MarkLinkingPostsStale :execrows
+UPDATE posts
+SET render_version = 0
+WHERE id <> sqlc.arg(post_id)::uuid
+  AND strpos(content, sqlc.arg(post_id)::uuid::text) > 0;
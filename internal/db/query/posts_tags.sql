@@ -8,4 +8,9 @@ INSERT INTO posts_tags (
 
 -- name: DeletePostTag :exec
 DELETE FROM posts_tags
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: GetPostTag :one
+SELECT * FROM posts_tags
+WHERE id = $1
+LIMIT 1;
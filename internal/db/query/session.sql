@@ -6,11 +6,58 @@ INSERT INTO sessions (
   user_agent,
   client_ip,
   is_blocked,
-  expires_at
+  expires_at,
+  family_id,
+  parent_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
 ) RETURNING *;
 
 -- name: GetSession :one
 SELECT * FROM sessions
 WHERE id = $1 LIMIT 1;
+
+-- name: ListActiveSessions :many
+SELECT * FROM sessions
+WHERE username = $1
+  AND is_blocked IS FALSE
+  AND rotated_at IS NULL
+  AND expires_at > NOW()
+ORDER BY created_at DESC;
+
+-- name: RotateSession :one
+UPDATE sessions
+SET rotated_at = NOW()
+WHERE id = $1
+  AND is_blocked IS FALSE
+  AND rotated_at IS NULL
+RETURNING *;
+
+-- name: BlockSession :execrows
+UPDATE sessions
+SET is_blocked = TRUE
+WHERE family_id = (
+  SELECT s.family_id
+  FROM sessions AS s
+  WHERE s.id = $1
+    AND s.username = $2
+);
+
+-- name: BlockSessionFamily :exec
+UPDATE sessions
+SET is_blocked = TRUE
+WHERE family_id = $1;
+
+-- name: BlockUserSessions :exec
+UPDATE sessions
+SET is_blocked = TRUE
+WHERE username = $1
+  AND is_blocked IS FALSE;
+
+-- name: DeleteExpiredSessions :execrows
+DELETE FROM sessions
+WHERE expires_at <= NOW();
+
+-- name: DeleteUserSessions :exec
+DELETE FROM sessions
+WHERE username = $1;
@@ -1,14 +1,18 @@
 -- name: CreateTag :one
 INSERT INTO tags (
   name,
+  slug,
+  description,
   image_url
 ) VALUES (
-  $1,$2
+  $1,$2,$3,$4
 ) RETURNING *;
 
 -- name: GetTag :one
 SELECT id
       ,name
+      ,slug
+      ,description
       ,image_url
       ,created_at
       ,updated_at
@@ -16,24 +20,48 @@ FROM tags
 WHERE id = $1 
 LIMIT 1;
 
--- name: GetTagByName :one
+-- name: LookupTag :one
 SELECT id
       ,name
+      ,slug
+      ,description
       ,image_url
       ,created_at
       ,updated_at
 FROM tags
-WHERE name = $1 
+WHERE id = sqlc.narg(id)
+   OR slug = sqlc.narg(slug)
 LIMIT 1;
 
 -- name: ListTags :many
 SELECT id
       ,name
+      ,slug
+      ,description
       ,image_url
       ,created_at
       ,updated_at
 FROM tags;
 
+-- name: TagSlugExists :one
+SELECT EXISTS (
+  SELECT 1
+  FROM tags
+  WHERE slug = sqlc.arg(slug)
+    AND id <> sqlc.arg(tag_id)
+);
+
+-- name: UpdateTag :one
+UPDATE tags
+SET
+  name = COALESCE(sqlc.narg(name), name),
+  slug = COALESCE(sqlc.narg(slug), slug),
+  description = COALESCE(sqlc.narg(description), description),
+  updated_at = NOW()
+WHERE
+  id = sqlc.arg(id)
+RETURNING *;
+
 -- name: DeleteTag :exec
 DELETE FROM tags
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
@@ -0,0 +1,96 @@
+-- name: GetUserTOTP :one
+SELECT u.id
+      ,u.username
+      ,u.role
+      ,u.totp_secret
+      ,u.totp_enabled_at
+      ,u.totp_last_used_step
+FROM users AS u
+WHERE u.id = $1
+LIMIT 1;
+
+-- name: SetUserTOTPSecret :execrows
+UPDATE users
+SET
+  totp_secret = $2,
+  totp_last_used_step = 0,
+  updated_at = NOW()
+WHERE id = $1
+  AND totp_enabled_at IS NULL;
+
+-- name: EnableUserTOTP :execrows
+UPDATE users
+SET
+  totp_enabled_at = NOW(),
+  totp_last_used_step = $2,
+  updated_at = NOW()
+WHERE id = $1
+  AND totp_secret IS NOT NULL
+  AND totp_enabled_at IS NULL;
+
+-- name: DisableUserTOTP :exec
+UPDATE users
+SET
+  totp_secret = NULL,
+  totp_enabled_at = NULL,
+  totp_last_used_step = 0,
+  updated_at = NOW()
+WHERE id = $1;
+
+-- name: UseTOTPStep :execrows
+UPDATE users
+SET totp_last_used_step = $2
+WHERE id = $1
+  AND totp_last_used_step < $2;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO recovery_codes (
+  user_id,
+  code_hash
+) VALUES (
+  $1, $2
+);
+
+-- name: CountRecoveryCodes :one
+SELECT COUNT(*)
+FROM recovery_codes
+WHERE user_id = $1
+  AND used_at IS NULL;
+
+-- name: UseRecoveryCode :execrows
+UPDATE recovery_codes
+SET used_at = NOW()
+WHERE user_id = $1
+  AND code_hash = $2
+  AND used_at IS NULL;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM recovery_codes
+WHERE user_id = $1;
+
+-- name: CreateMFAChallenge :one
+INSERT INTO mfa_challenges (
+  user_id,
+  token_hash,
+  expires_at
+) VALUES (
+  $1, $2, $3
+) RETURNING *;
+
+-- name: GetMFAChallengeByHash :one
+SELECT * FROM mfa_challenges
+WHERE token_hash = $1
+LIMIT 1;
+
+-- name: IncrementMFAChallengeAttempts :exec
+UPDATE mfa_challenges
+SET attempts = attempts + 1
+WHERE id = $1;
+
+-- name: DeleteMFAChallenge :execrows
+DELETE FROM mfa_challenges
+WHERE id = $1;
+
+-- name: DeleteExpiredMFAChallenges :execrows
+DELETE FROM mfa_challenges
+WHERE expires_at <= NOW();
@@ -2,9 +2,10 @@
 INSERT INTO users (
   username,
   email,
-  password
+  password,
+  role
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4
 ) RETURNING *;
 
 -- name: GetUser :one
@@ -14,6 +15,10 @@ SELECT u.id
       ,u.password 
       ,u.created_at
       ,u.updated_at
+      ,u.role
+      ,u.email_verified_at
+      ,u.disabled_at
+      ,u.totp_enabled_at
 FROM users as u
 WHERE u.id = $1 
 LIMIT 1;
@@ -22,6 +27,11 @@ LIMIT 1;
 SELECT u.id
       ,u.username
       ,u.password
+      ,u.email
+      ,u.role
+      ,u.totp_enabled_at
+      ,u.email_verified_at
+      ,u.disabled_at
 FROM users as u
 WHERE u.username = $1 
 LIMIT 1;
@@ -30,12 +40,38 @@ LIMIT 1;
 SELECT u.id
       ,u.username
       ,u.email
+      ,u.role
+      ,u.email_verified_at
+      ,u.disabled_at
       ,u.created_at
 FROM users as u;
 
--- name: DeleteUser :exec
+-- name: LockActiveUsersByRole :many
+SELECT id
+FROM users
+WHERE role = $1
+  AND disabled_at IS NULL
+ORDER BY id
+FOR UPDATE;
+
+-- name: DisableUser :execrows
+UPDATE users
+SET disabled_at = NOW()
+   ,updated_at = NOW()
+WHERE id = $1
+  AND disabled_at IS NULL;
+
+-- name: EnableUser :execrows
+UPDATE users
+SET disabled_at = NULL
+   ,updated_at = NOW()
+WHERE id = $1
+  AND disabled_at IS NOT NULL;
+
+-- name: DeleteDisabledUser :execrows
 DELETE FROM users
-WHERE id = $1;
+WHERE id = $1
+  AND disabled_at IS NOT NULL;
 
 -- name: UpdateUser :one
 UPDATE users
@@ -43,7 +79,25 @@ SET
   password = COALESCE(sqlc.narg(password), password),
   updated_at = NOW(),
   username = COALESCE(sqlc.narg(username), username),
-  email = COALESCE(sqlc.narg(email), email)
+  email = COALESCE(sqlc.narg(email), email),
+  role = COALESCE(sqlc.narg(role), role)
 WHERE
   id = sqlc.arg(id)
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: RehashUserPassword :execrows
+UPDATE users
+SET password = sqlc.arg(new_password)
+   ,updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND password = sqlc.arg(old_password);
+
+-- name: GetUserByEmail :one
+SELECT u.id
+      ,u.username
+      ,u.email
+      ,u.email_verified_at
+      ,u.disabled_at
+FROM users as u
+WHERE u.email = $1
+LIMIT 1;
@@ -0,0 +1,51 @@
+-- name: CreateWebAuthnCredential :one
+INSERT INTO webauthn_credentials (
+  user_id,
+  name,
+  credential_id,
+  credential
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetWebAuthnCredentialByCredentialID :one
+SELECT * FROM webauthn_credentials
+WHERE credential_id = $1
+LIMIT 1;
+
+-- name: ListWebAuthnCredentials :many
+SELECT * FROM webauthn_credentials
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: UpdateWebAuthnCredentialUse :exec
+UPDATE webauthn_credentials
+SET
+  credential = $2,
+  last_used_at = NOW()
+WHERE id = $1;
+
+-- name: DeleteWebAuthnCredential :execrows
+DELETE FROM webauthn_credentials
+WHERE id = $1
+  AND user_id = $2;
+
+-- name: CreateWebAuthnSession :one
+INSERT INTO webauthn_sessions (
+  user_id,
+  ceremony,
+  session_data,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ConsumeWebAuthnSession :one
+DELETE FROM webauthn_sessions
+WHERE id = $1
+  AND ceremony = $2
+RETURNING *;
+
+-- name: DeleteExpiredWebAuthnSessions :execrows
+DELETE FROM webauthn_sessions
+WHERE expires_at <= NOW();
@@ -0,0 +1,196 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.22.0
+// source: author.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const getAuthorByUsername = `-- name: GetAuthorByUsername :one
+SELECT u.username
+      ,u.display_name
+      ,u.bio
+      ,u.website
+      ,u.social_links
+      ,u.avatar_url
+      ,u.created_at
+FROM users as u
+WHERE u.username = $1
+  AND u.disabled_at IS NULL
+LIMIT 1
+`
+
+type GetAuthorByUsernameRow struct {
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	Bio         string    `json:"bio"`
+	Website     string    `json:"website"`
+	SocialLinks []byte    `json:"social_links"`
+	AvatarUrl   string    `json:"avatar_url"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (q *Queries) GetAuthorByUsername(ctx context.Context, username string) (GetAuthorByUsernameRow, error) {
+	row := q.db.QueryRow(ctx, getAuthorByUsername, username)
+	var i GetAuthorByUsernameRow
+	err := row.Scan(
+		&i.Username,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Website,
+		&i.SocialLinks,
+		&i.AvatarUrl,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listAuthors = `-- name: ListAuthors :many
+SELECT u.username
+      ,u.display_name
+      ,u.bio
+      ,u.website
+      ,u.social_links
+      ,u.avatar_url
+      ,u.created_at
+FROM users as u
+WHERE u.disabled_at IS NULL
+ORDER BY u.username
+`
+
+type ListAuthorsRow struct {
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	Bio         string    `json:"bio"`
+	Website     string    `json:"website"`
+	SocialLinks []byte    `json:"social_links"`
+	AvatarUrl   string    `json:"avatar_url"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (q *Queries) ListAuthors(ctx context.Context) ([]ListAuthorsRow, error) {
+	rows, err := q.db.Query(ctx, listAuthors)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAuthorsRow{}
+	for rows.Next() {
+		var i ListAuthorsRow
+		if err := rows.Scan(
+			&i.Username,
+			&i.DisplayName,
+			&i.Bio,
+			&i.Website,
+			&i.SocialLinks,
+			&i.AvatarUrl,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setUserAvatar = `-- name: SetUserAvatar :one
+UPDATE users
+SET
+  avatar_url = $2,
+  updated_at = NOW()
+WHERE
+  id = $1
+RETURNING id, username, email, password, created_at, updated_at, role, totp_secret, totp_enabled_at, totp_last_used_step, email_verified_at, display_name, bio, website, social_links, avatar_url, disabled_at
+`
+
+type SetUserAvatarParams struct {
+	ID        uuid.UUID `json:"id"`
+	AvatarUrl string    `json:"avatar_url"`
+}
+
+func (q *Queries) SetUserAvatar(ctx context.Context, arg SetUserAvatarParams) (User, error) {
+	row := q.db.QueryRow(ctx, setUserAvatar, arg.ID, arg.AvatarUrl)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastUsedStep,
+		&i.EmailVerifiedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Website,
+		&i.SocialLinks,
+		&i.AvatarUrl,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const updateAuthorProfile = `-- name: UpdateAuthorProfile :one
+UPDATE users
+SET
+  display_name = COALESCE($1, display_name),
+  bio = COALESCE($2, bio),
+  website = COALESCE($3, website),
+  social_links = COALESCE($4, social_links),
+  updated_at = NOW()
+WHERE
+  id = $5
+RETURNING id, username, email, password, created_at, updated_at, role, totp_secret, totp_enabled_at, totp_last_used_step, email_verified_at, display_name, bio, website, social_links, avatar_url, disabled_at
+`
+
+type UpdateAuthorProfileParams struct {
+	DisplayName pgtype.Text `json:"display_name"`
+	Bio         pgtype.Text `json:"bio"`
+	Website     pgtype.Text `json:"website"`
+	SocialLinks []byte      `json:"social_links"`
+	ID          uuid.UUID   `json:"id"`
+}
+
+func (q *Queries) UpdateAuthorProfile(ctx context.Context, arg UpdateAuthorProfileParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateAuthorProfile,
+		arg.DisplayName,
+		arg.Bio,
+		arg.Website,
+		arg.SocialLinks,
+		arg.ID,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastUsedStep,
+		&i.EmailVerifiedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Website,
+		&i.SocialLinks,
+		&i.AvatarUrl,
+		&i.DisabledAt,
+	)
+	return i, err
+}
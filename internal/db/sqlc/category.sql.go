@@ -7,27 +7,59 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const categorySlugExists = `-- name: CategorySlugExists :one
+SELECT EXISTS (
+  SELECT 1
+  FROM categories
+  WHERE slug = $1
+    AND id <> $2
+)
+`
+
+type CategorySlugExistsParams struct {
+	Slug       string    `json:"slug"`
+	CategoryID uuid.UUID `json:"category_id"`
+}
+
+func (q *Queries) CategorySlugExists(ctx context.Context, arg CategorySlugExistsParams) (bool, error) {
+	row := q.db.QueryRow(ctx, categorySlugExists, arg.Slug, arg.CategoryID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const createCategory = `-- name: CreateCategory :one
 INSERT INTO categories (
-  name
+  name,
+  slug,
+  description
 ) VALUES (
-  $1
-) RETURNING id, name, created_at, updated_at
+  $1,$2,$3
+) RETURNING id, name, created_at, updated_at, slug, description
 `
 
-func (q *Queries) CreateCategory(ctx context.Context, name string) (Category, error) {
-	row := q.db.QueryRow(ctx, createCategory, name)
+type CreateCategoryParams struct {
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+}
+
+func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error) {
+	row := q.db.QueryRow(ctx, createCategory, arg.Name, arg.Slug, arg.Description)
 	var i Category
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Slug,
+		&i.Description,
 	)
 	return i, err
 }
@@ -42,48 +74,39 @@ func (q *Queries) DeleteCategory(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
-const getCategory = `-- name: GetCategory :one
+const listCategories = `-- name: ListCategories :many
 SELECT ca.id
       ,ca.name
+      ,ca.slug
+      ,ca.description
       ,ca.created_at
       ,ca.updated_at
 FROM categories as ca
-WHERE ca.id = $1 
-LIMIT 1
 `
 
-func (q *Queries) GetCategory(ctx context.Context, id uuid.UUID) (Category, error) {
-	row := q.db.QueryRow(ctx, getCategory, id)
-	var i Category
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+type ListCategoriesRow struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
-const listCategories = `-- name: ListCategories :many
-SELECT ca.id
-      ,ca.name
-      ,ca.created_at
-      ,ca.updated_at
-FROM categories as ca
-`
-
-func (q *Queries) ListCategories(ctx context.Context) ([]Category, error) {
+func (q *Queries) ListCategories(ctx context.Context) ([]ListCategoriesRow, error) {
 	rows, err := q.db.Query(ctx, listCategories)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Category{}
+	items := []ListCategoriesRow{}
 	for rows.Next() {
-		var i Category
+		var i ListCategoriesRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
+			&i.Slug,
+			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -97,29 +120,81 @@ func (q *Queries) ListCategories(ctx context.Context) ([]Category, error) {
 	return items, nil
 }
 
+const lookupCategory = `-- name: LookupCategory :one
+SELECT ca.id
+      ,ca.name
+      ,ca.slug
+      ,ca.description
+      ,ca.created_at
+      ,ca.updated_at
+FROM categories as ca
+WHERE ca.id = $1
+   OR ca.slug = $2
+LIMIT 1
+`
+
+type LookupCategoryParams struct {
+	ID   pgtype.UUID `json:"id"`
+	Slug pgtype.Text `json:"slug"`
+}
+
+type LookupCategoryRow struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (q *Queries) LookupCategory(ctx context.Context, arg LookupCategoryParams) (LookupCategoryRow, error) {
+	row := q.db.QueryRow(ctx, lookupCategory, arg.ID, arg.Slug)
+	var i LookupCategoryRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const updateCategory = `-- name: UpdateCategory :one
 UPDATE categories
 SET
   name = COALESCE($1, name),
+  slug = COALESCE($2, slug),
+  description = COALESCE($3, description),
   updated_at = NOW()
 WHERE
-  id = $2
-RETURNING id, name, created_at, updated_at
+  id = $4
+RETURNING id, name, created_at, updated_at, slug, description
 `
 
 type UpdateCategoryParams struct {
-	Name pgtype.Text `json:"name"`
-	ID   uuid.UUID   `json:"id"`
+	Name        pgtype.Text `json:"name"`
+	Slug        pgtype.Text `json:"slug"`
+	Description pgtype.Text `json:"description"`
+	ID          uuid.UUID   `json:"id"`
 }
 
 func (q *Queries) UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (Category, error) {
-	row := q.db.QueryRow(ctx, updateCategory, arg.Name, arg.ID)
+	row := q.db.QueryRow(ctx, updateCategory,
+		arg.Name,
+		arg.Slug,
+		arg.Description,
+		arg.ID,
+	)
 	var i Category
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Slug,
+		&i.Description,
 	)
 	return i, err
 }
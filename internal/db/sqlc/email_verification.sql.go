@@ -0,0 +1,171 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.22.0
+// source: email_verification.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const countRecentEmailVerifications = `-- name: CountRecentEmailVerifications :one
+SELECT COUNT(*)
+FROM email_verifications
+WHERE user_id = $1
+  AND created_at > $2::timestamptz
+`
+
+type CountRecentEmailVerificationsParams struct {
+	UserID       uuid.UUID `json:"user_id"`
+	CreatedAfter time.Time `json:"created_after"`
+}
+
+func (q *Queries) CountRecentEmailVerifications(ctx context.Context, arg CountRecentEmailVerificationsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countRecentEmailVerifications, arg.UserID, arg.CreatedAfter)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createEmailVerification = `-- name: CreateEmailVerification :one
+INSERT INTO email_verifications (
+  user_id,
+  email,
+  token_hash,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, user_id, email, token_hash, expires_at, used_at, created_at
+`
+
+type CreateEmailVerificationParams struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	TokenHash string    `json:"token_hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateEmailVerification(ctx context.Context, arg CreateEmailVerificationParams) (EmailVerification, error) {
+	row := q.db.QueryRow(ctx, createEmailVerification,
+		arg.UserID,
+		arg.Email,
+		arg.TokenHash,
+		arg.ExpiresAt,
+	)
+	var i EmailVerification
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Email,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteEmailVerifications = `-- name: DeleteEmailVerifications :exec
+DELETE FROM email_verifications
+WHERE user_id = $1
+  AND used_at IS NULL
+`
+
+func (q *Queries) DeleteEmailVerifications(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteEmailVerifications, userID)
+	return err
+}
+
+const deleteExpiredEmailVerifications = `-- name: DeleteExpiredEmailVerifications :execrows
+DELETE FROM email_verifications
+WHERE expires_at <= NOW()
+`
+
+func (q *Queries) DeleteExpiredEmailVerifications(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredEmailVerifications)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getEmailVerificationByHash = `-- name: GetEmailVerificationByHash :one
+SELECT id, user_id, email, token_hash, expires_at, used_at, created_at FROM email_verifications
+WHERE token_hash = $1
+LIMIT 1
+`
+
+func (q *Queries) GetEmailVerificationByHash(ctx context.Context, tokenHash string) (EmailVerification, error) {
+	row := q.db.QueryRow(ctx, getEmailVerificationByHash, tokenHash)
+	var i EmailVerification
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Email,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const useEmailVerification = `-- name: UseEmailVerification :execrows
+UPDATE email_verifications
+SET used_at = NOW()
+WHERE id = $1
+  AND used_at IS NULL
+  AND expires_at > NOW()
+`
+
+func (q *Queries) UseEmailVerification(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, useEmailVerification, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :one
+UPDATE users
+SET
+  email = $2,
+  email_verified_at = NOW(),
+  updated_at = NOW()
+WHERE id = $1
+RETURNING id, username, email, password, created_at, updated_at, role, totp_secret, totp_enabled_at, totp_last_used_step, email_verified_at, display_name, bio, website, social_links, avatar_url, disabled_at
+`
+
+type VerifyUserEmailParams struct {
+	ID    uuid.UUID `json:"id"`
+	Email string    `json:"email"`
+}
+
+func (q *Queries) VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error) {
+	row := q.db.QueryRow(ctx, verifyUserEmail, arg.ID, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Password,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastUsedStep,
+		&i.EmailVerifiedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Website,
+		&i.SocialLinks,
+		&i.AvatarUrl,
+		&i.DisabledAt,
+	)
+	return i, err
+}
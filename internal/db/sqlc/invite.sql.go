@@ -0,0 +1,164 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.22.0
+// source: invite.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const acceptInvite = `-- name: AcceptInvite :execrows
+UPDATE invites
+SET accepted_at = NOW()
+WHERE id = $1
+  AND accepted_at IS NULL
+  AND revoked_at IS NULL
+  AND expires_at > NOW()
+`
+
+func (q *Queries) AcceptInvite(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, acceptInvite, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createInvite = `-- name: CreateInvite :one
+INSERT INTO invites (
+  email,
+  role,
+  token_hash,
+  invited_by,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING id, email, role, token_hash, invited_by, expires_at, accepted_at, revoked_at, created_at
+`
+
+type CreateInviteParams struct {
+	Email     string      `json:"email"`
+	Role      string      `json:"role"`
+	TokenHash string      `json:"token_hash"`
+	InvitedBy pgtype.UUID `json:"invited_by"`
+	ExpiresAt time.Time   `json:"expires_at"`
+}
+
+func (q *Queries) CreateInvite(ctx context.Context, arg CreateInviteParams) (Invite, error) {
+	row := q.db.QueryRow(ctx, createInvite,
+		arg.Email,
+		arg.Role,
+		arg.TokenHash,
+		arg.InvitedBy,
+		arg.ExpiresAt,
+	)
+	var i Invite
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Role,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.ExpiresAt,
+		&i.AcceptedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getInviteByHash = `-- name: GetInviteByHash :one
+SELECT id, email, role, token_hash, invited_by, expires_at, accepted_at, revoked_at, created_at FROM invites
+WHERE token_hash = $1
+LIMIT 1
+`
+
+func (q *Queries) GetInviteByHash(ctx context.Context, tokenHash string) (Invite, error) {
+	row := q.db.QueryRow(ctx, getInviteByHash, tokenHash)
+	var i Invite
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Role,
+		&i.TokenHash,
+		&i.InvitedBy,
+		&i.ExpiresAt,
+		&i.AcceptedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listPendingInvites = `-- name: ListPendingInvites :many
+SELECT i.id
+      ,i.email
+      ,i.role
+      ,i.expires_at
+      ,i.created_at
+      ,u.username AS invited_by
+FROM invites AS i
+LEFT JOIN users AS u ON i.invited_by = u.id
+WHERE i.accepted_at IS NULL
+  AND i.revoked_at IS NULL
+  AND i.expires_at > NOW()
+ORDER BY i.created_at DESC
+`
+
+type ListPendingInvitesRow struct {
+	ID        uuid.UUID   `json:"id"`
+	Email     string      `json:"email"`
+	Role      string      `json:"role"`
+	ExpiresAt time.Time   `json:"expires_at"`
+	CreatedAt time.Time   `json:"created_at"`
+	InvitedBy pgtype.Text `json:"invited_by"`
+}
+
+func (q *Queries) ListPendingInvites(ctx context.Context) ([]ListPendingInvitesRow, error) {
+	rows, err := q.db.Query(ctx, listPendingInvites)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPendingInvitesRow{}
+	for rows.Next() {
+		var i ListPendingInvitesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Role,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.InvitedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeInvite = `-- name: RevokeInvite :execrows
+UPDATE invites
+SET revoked_at = NOW()
+WHERE id = $1
+  AND accepted_at IS NULL
+  AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeInvite(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeInvite, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
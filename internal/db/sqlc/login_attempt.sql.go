@@ -0,0 +1,121 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.22.0
+// source: login_attempt.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const deleteStaleLoginAttempts = `-- name: DeleteStaleLoginAttempts :execrows
+DELETE FROM login_attempts
+WHERE last_failure_at < $1::timestamptz
+  AND (locked_until IS NULL OR locked_until <= NOW())
+`
+
+func (q *Queries) DeleteStaleLoginAttempts(ctx context.Context, resetBefore time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteStaleLoginAttempts, resetBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getLoginLock = `-- name: GetLoginLock :one
+SELECT locked_until
+FROM login_attempts
+WHERE ((scope = 'username' AND key = $1)
+    OR (scope = 'ip' AND key = $2))
+  AND locked_until > NOW()
+ORDER BY locked_until DESC
+LIMIT 1
+`
+
+type GetLoginLockParams struct {
+	Username string `json:"username"`
+	ClientIp string `json:"client_ip"`
+}
+
+func (q *Queries) GetLoginLock(ctx context.Context, arg GetLoginLockParams) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, getLoginLock, arg.Username, arg.ClientIp)
+	var locked_until pgtype.Timestamptz
+	err := row.Scan(&locked_until)
+	return locked_until, err
+}
+
+const lockLogin = `-- name: LockLogin :exec
+UPDATE login_attempts
+SET locked_until = $3
+WHERE scope = $1
+  AND key = $2
+`
+
+type LockLoginParams struct {
+	Scope       string             `json:"scope"`
+	Key         string             `json:"key"`
+	LockedUntil pgtype.Timestamptz `json:"locked_until"`
+}
+
+func (q *Queries) LockLogin(ctx context.Context, arg LockLoginParams) error {
+	_, err := q.db.Exec(ctx, lockLogin, arg.Scope, arg.Key, arg.LockedUntil)
+	return err
+}
+
+const recordLoginFailure = `-- name: RecordLoginFailure :one
+INSERT INTO login_attempts (
+  scope,
+  key,
+  failures,
+  last_failure_at
+) VALUES (
+  $1, $2, 1, NOW()
+)
+ON CONFLICT (scope, key) DO UPDATE
+SET
+  failures = CASE
+    WHEN login_attempts.last_failure_at < $3::timestamptz THEN 1
+    ELSE login_attempts.failures + 1
+  END,
+  last_failure_at = NOW()
+RETURNING scope, key, failures, last_failure_at, locked_until
+`
+
+type RecordLoginFailureParams struct {
+	Scope       string    `json:"scope"`
+	Key         string    `json:"key"`
+	ResetBefore time.Time `json:"reset_before"`
+}
+
+func (q *Queries) RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) (LoginAttempt, error) {
+	row := q.db.QueryRow(ctx, recordLoginFailure, arg.Scope, arg.Key, arg.ResetBefore)
+	var i LoginAttempt
+	err := row.Scan(
+		&i.Scope,
+		&i.Key,
+		&i.Failures,
+		&i.LastFailureAt,
+		&i.LockedUntil,
+	)
+	return i, err
+}
+
+const resetLoginAttempts = `-- name: ResetLoginAttempts :exec
+DELETE FROM login_attempts
+WHERE scope = $1
+  AND key = $2
+`
+
+type ResetLoginAttemptsParams struct {
+	Scope string `json:"scope"`
+	Key   string `json:"key"`
+}
+
+func (q *Queries) ResetLoginAttempts(ctx context.Context, arg ResetLoginAttemptsParams) error {
+	_, err := q.db.Exec(ctx, resetLoginAttempts, arg.Scope, arg.Key)
+	return err
+}
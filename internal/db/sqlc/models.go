@@ -5,27 +5,120 @@
 package db
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Category struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+}
+
+type EmailVerification struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	Email     string             `json:"email"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type Invite struct {
+	ID         uuid.UUID          `json:"id"`
+	Email      string             `json:"email"`
+	Role       string             `json:"role"`
+	TokenHash  string             `json:"token_hash"`
+	InvitedBy  pgtype.UUID        `json:"invited_by"`
+	ExpiresAt  time.Time          `json:"expires_at"`
+	AcceptedAt pgtype.Timestamptz `json:"accepted_at"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type LoginAttempt struct {
+	Scope         string             `json:"scope"`
+	Key           string             `json:"key"`
+	Failures      int32              `json:"failures"`
+	LastFailureAt time.Time          `json:"last_failure_at"`
+	LockedUntil   pgtype.Timestamptz `json:"locked_until"`
+}
+
+type MfaChallenge struct {
 	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
+	UserID    uuid.UUID `json:"user_id"`
+	TokenHash string    `json:"token_hash"`
+	Attempts  int32     `json:"attempts"`
+	ExpiresAt time.Time `json:"expires_at"`
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type OidcLogin struct {
+	ID           uuid.UUID `json:"id"`
+	StateHash    string    `json:"state_hash"`
+	Nonce        string    `json:"nonce"`
+	CodeVerifier string    `json:"code_verifier"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type PasswordResetToken struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type PersonalAccessToken struct {
+	ID         uuid.UUID          `json:"id"`
+	UserID     uuid.UUID          `json:"user_id"`
+	Name       string             `json:"name"`
+	TokenHash  string             `json:"token_hash"`
+	Scopes     []string           `json:"scopes"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt  time.Time          `json:"created_at"`
 }
 
 type Post struct {
-	ID         uuid.UUID `json:"id"`
-	CategoryID uuid.UUID `json:"category_id"`
-	Title      string    `json:"title"`
-	Subtitle   string    `json:"subtitle"`
-	Content    string    `json:"content"`
-	Publicated bool      `json:"publicated"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID             uuid.UUID       `json:"id"`
+	CategoryID     uuid.UUID       `json:"category_id"`
+	Title          string          `json:"title"`
+	Subtitle       string          `json:"subtitle"`
+	Content        string          `json:"content"`
+	Publicated     bool            `json:"publicated"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	AuthorID       pgtype.UUID     `json:"author_id"`
+	LastEditedBy   pgtype.UUID     `json:"last_edited_by"`
+	Slug           string          `json:"slug"`
+	ContentFormat  string          `json:"content_format"`
+	ContentHtml    string          `json:"content_html"`
+	ContentToc     json.RawMessage `json:"content_toc"`
+	RenderVersion  int32           `json:"render_version"`
+	RenderWarnings json.RawMessage `json:"render_warnings"`
+}
+
+type PostAuthor struct {
+	PostID    uuid.UUID `json:"post_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PostSlug struct {
+	Slug      string    `json:"slug"`
+	PostID    uuid.UUID `json:"post_id"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type PostsTag struct {
@@ -36,30 +129,81 @@ type PostsTag struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+type RecoveryCode struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	CodeHash  string             `json:"code_hash"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
 type Session struct {
-	ID           uuid.UUID `json:"id"`
-	Username     string    `json:"username"`
-	RefreshToken string    `json:"refresh_token"`
-	UserAgent    string    `json:"user_agent"`
-	ClientIp     string    `json:"client_ip"`
-	IsBlocked    bool      `json:"is_blocked"`
-	ExpiresAt    time.Time `json:"expires_at"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID           uuid.UUID          `json:"id"`
+	Username     string             `json:"username"`
+	RefreshToken string             `json:"refresh_token"`
+	UserAgent    string             `json:"user_agent"`
+	ClientIp     string             `json:"client_ip"`
+	IsBlocked    bool               `json:"is_blocked"`
+	ExpiresAt    time.Time          `json:"expires_at"`
+	CreatedAt    time.Time          `json:"created_at"`
+	FamilyID     uuid.UUID          `json:"family_id"`
+	ParentID     pgtype.UUID        `json:"parent_id"`
+	RotatedAt    pgtype.Timestamptz `json:"rotated_at"`
 }
 
 type Tag struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	ImageUrl  string    `json:"image_url"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	ImageUrl    string    `json:"image_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
 }
 
 type User struct {
+	ID               uuid.UUID          `json:"id"`
+	Username         string             `json:"username"`
+	Email            string             `json:"email"`
+	Password         string             `json:"password"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+	Role             string             `json:"role"`
+	TotpSecret       pgtype.Text        `json:"totp_secret"`
+	TotpEnabledAt    pgtype.Timestamptz `json:"totp_enabled_at"`
+	TotpLastUsedStep int64              `json:"totp_last_used_step"`
+	EmailVerifiedAt  pgtype.Timestamptz `json:"email_verified_at"`
+	DisplayName      string             `json:"display_name"`
+	Bio              string             `json:"bio"`
+	Website          string             `json:"website"`
+	SocialLinks      []byte             `json:"social_links"`
+	AvatarUrl        string             `json:"avatar_url"`
+	DisabledAt       pgtype.Timestamptz `json:"disabled_at"`
+}
+
+type UserIdentity struct {
 	ID        uuid.UUID `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	Password  string    `json:"password"`
+	UserID    uuid.UUID `json:"user_id"`
+	Issuer    string    `json:"issuer"`
+	Subject   string    `json:"subject"`
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WebauthnCredential struct {
+	ID           uuid.UUID          `json:"id"`
+	UserID       uuid.UUID          `json:"user_id"`
+	Name         string             `json:"name"`
+	CredentialID []byte             `json:"credential_id"`
+	Credential   []byte             `json:"credential"`
+	LastUsedAt   pgtype.Timestamptz `json:"last_used_at"`
+	CreatedAt    time.Time          `json:"created_at"`
+}
+
+type WebauthnSession struct {
+	ID          uuid.UUID   `json:"id"`
+	UserID      pgtype.UUID `json:"user_id"`
+	Ceremony    string      `json:"ceremony"`
+	SessionData []byte      `json:"session_data"`
+	ExpiresAt   time.Time   `json:"expires_at"`
+	CreatedAt   time.Time   `json:"created_at"`
 }
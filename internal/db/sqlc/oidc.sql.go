@@ -0,0 +1,137 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.22.0
+// source: oidc.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const consumeOIDCLogin = `-- name: ConsumeOIDCLogin :one
+DELETE FROM oidc_logins
+WHERE state_hash = $1
+RETURNING id, state_hash, nonce, code_verifier, expires_at, created_at
+`
+
+func (q *Queries) ConsumeOIDCLogin(ctx context.Context, stateHash string) (OidcLogin, error) {
+	row := q.db.QueryRow(ctx, consumeOIDCLogin, stateHash)
+	var i OidcLogin
+	err := row.Scan(
+		&i.ID,
+		&i.StateHash,
+		&i.Nonce,
+		&i.CodeVerifier,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createOIDCLogin = `-- name: CreateOIDCLogin :one
+INSERT INTO oidc_logins (
+  state_hash,
+  nonce,
+  code_verifier,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, state_hash, nonce, code_verifier, expires_at, created_at
+`
+
+type CreateOIDCLoginParams struct {
+	StateHash    string    `json:"state_hash"`
+	Nonce        string    `json:"nonce"`
+	CodeVerifier string    `json:"code_verifier"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateOIDCLogin(ctx context.Context, arg CreateOIDCLoginParams) (OidcLogin, error) {
+	row := q.db.QueryRow(ctx, createOIDCLogin,
+		arg.StateHash,
+		arg.Nonce,
+		arg.CodeVerifier,
+		arg.ExpiresAt,
+	)
+	var i OidcLogin
+	err := row.Scan(
+		&i.ID,
+		&i.StateHash,
+		&i.Nonce,
+		&i.CodeVerifier,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createUserIdentity = `-- name: CreateUserIdentity :one
+INSERT INTO user_identities (
+  user_id,
+  issuer,
+  subject
+) VALUES (
+  $1, $2, $3
+) RETURNING id, user_id, issuer, subject, created_at
+`
+
+type CreateUserIdentityParams struct {
+	UserID  uuid.UUID `json:"user_id"`
+	Issuer  string    `json:"issuer"`
+	Subject string    `json:"subject"`
+}
+
+func (q *Queries) CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error) {
+	row := q.db.QueryRow(ctx, createUserIdentity, arg.UserID, arg.Issuer, arg.Subject)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Issuer,
+		&i.Subject,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteExpiredOIDCLogins = `-- name: DeleteExpiredOIDCLogins :execrows
+DELETE FROM oidc_logins
+WHERE expires_at <= NOW()
+`
+
+func (q *Queries) DeleteExpiredOIDCLogins(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredOIDCLogins)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getUserIdentity = `-- name: GetUserIdentity :one
+SELECT id, user_id, issuer, subject, created_at FROM user_identities
+WHERE issuer = $1
+  AND subject = $2
+LIMIT 1
+`
+
+type GetUserIdentityParams struct {
+	Issuer  string `json:"issuer"`
+	Subject string `json:"subject"`
+}
+
+func (q *Queries) GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error) {
+	row := q.db.QueryRow(ctx, getUserIdentity, arg.Issuer, arg.Subject)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Issuer,
+		&i.Subject,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,136 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.22.0
+// source: password_reset.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countRecentPasswordResetTokens = `-- name: CountRecentPasswordResetTokens :one
+SELECT COUNT(*)
+FROM password_reset_tokens
+WHERE user_id = $1
+  AND created_at > $2::timestamptz
+`
+
+type CountRecentPasswordResetTokensParams struct {
+	UserID       uuid.UUID `json:"user_id"`
+	CreatedAfter time.Time `json:"created_after"`
+}
+
+func (q *Queries) CountRecentPasswordResetTokens(ctx context.Context, arg CountRecentPasswordResetTokensParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countRecentPasswordResetTokens, arg.UserID, arg.CreatedAfter)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createPasswordResetToken = `-- name: CreatePasswordResetToken :one
+INSERT INTO password_reset_tokens (
+  user_id,
+  token_hash,
+  expires_at
+) VALUES (
+  $1, $2, $3
+) RETURNING id, user_id, token_hash, expires_at, used_at, created_at
+`
+
+type CreatePasswordResetTokenParams struct {
+	UserID    uuid.UUID `json:"user_id"`
+	TokenHash string    `json:"token_hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) (PasswordResetToken, error) {
+	row := q.db.QueryRow(ctx, createPasswordResetToken, arg.UserID, arg.TokenHash, arg.ExpiresAt)
+	var i PasswordResetToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteExpiredPasswordResetTokens = `-- name: DeleteExpiredPasswordResetTokens :execrows
+DELETE FROM password_reset_tokens
+WHERE expires_at <= NOW()
+`
+
+func (q *Queries) DeleteExpiredPasswordResetTokens(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredPasswordResetTokens)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deletePasswordResetTokens = `-- name: DeletePasswordResetTokens :exec
+DELETE FROM password_reset_tokens
+WHERE user_id = $1
+  AND used_at IS NULL
+`
+
+func (q *Queries) DeletePasswordResetTokens(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deletePasswordResetTokens, userID)
+	return err
+}
+
+const getPasswordResetTokenByHash = `-- name: GetPasswordResetTokenByHash :one
+SELECT prt.id
+      ,prt.user_id
+      ,prt.expires_at
+      ,prt.used_at
+      ,u.username
+FROM password_reset_tokens AS prt
+JOIN users AS u ON prt.user_id = u.id
+WHERE prt.token_hash = $1
+LIMIT 1
+`
+
+type GetPasswordResetTokenByHashRow struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	Username  string             `json:"username"`
+}
+
+func (q *Queries) GetPasswordResetTokenByHash(ctx context.Context, tokenHash string) (GetPasswordResetTokenByHashRow, error) {
+	row := q.db.QueryRow(ctx, getPasswordResetTokenByHash, tokenHash)
+	var i GetPasswordResetTokenByHashRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.Username,
+	)
+	return i, err
+}
+
+const usePasswordResetToken = `-- name: UsePasswordResetToken :execrows
+UPDATE password_reset_tokens
+SET used_at = NOW()
+WHERE id = $1
+  AND used_at IS NULL
+  AND expires_at > NOW()
+`
+
+func (q *Queries) UsePasswordResetToken(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, usePasswordResetToken, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
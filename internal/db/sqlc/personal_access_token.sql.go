@@ -0,0 +1,186 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.22.0
+// source: personal_access_token.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createPersonalAccessToken = `-- name: CreatePersonalAccessToken :one
+INSERT INTO personal_access_tokens (
+  user_id,
+  name,
+  token_hash,
+  scopes,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5
+) RETURNING id, user_id, name, token_hash, scopes, last_used_at, expires_at, revoked_at, created_at
+`
+
+type CreatePersonalAccessTokenParams struct {
+	UserID    uuid.UUID          `json:"user_id"`
+	Name      string             `json:"name"`
+	TokenHash string             `json:"token_hash"`
+	Scopes    []string           `json:"scopes"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) CreatePersonalAccessToken(ctx context.Context, arg CreatePersonalAccessTokenParams) (PersonalAccessToken, error) {
+	row := q.db.QueryRow(ctx, createPersonalAccessToken,
+		arg.UserID,
+		arg.Name,
+		arg.TokenHash,
+		arg.Scopes,
+		arg.ExpiresAt,
+	)
+	var i PersonalAccessToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.TokenHash,
+		&i.Scopes,
+		&i.LastUsedAt,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPersonalAccessTokenByHash = `-- name: GetPersonalAccessTokenByHash :one
+SELECT pat.id
+      ,pat.user_id
+      ,pat.name
+      ,pat.scopes
+      ,pat.expires_at
+      ,pat.revoked_at
+      ,pat.created_at
+      ,u.username
+      ,u.role
+      ,u.disabled_at
+FROM personal_access_tokens AS pat
+JOIN users AS u ON pat.user_id = u.id
+WHERE pat.token_hash = $1
+LIMIT 1
+`
+
+type GetPersonalAccessTokenByHashRow struct {
+	ID         uuid.UUID          `json:"id"`
+	UserID     uuid.UUID          `json:"user_id"`
+	Name       string             `json:"name"`
+	Scopes     []string           `json:"scopes"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	RevokedAt  pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+	Username   string             `json:"username"`
+	Role       string             `json:"role"`
+	DisabledAt pgtype.Timestamptz `json:"disabled_at"`
+}
+
+func (q *Queries) GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (GetPersonalAccessTokenByHashRow, error) {
+	row := q.db.QueryRow(ctx, getPersonalAccessTokenByHash, tokenHash)
+	var i GetPersonalAccessTokenByHashRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Scopes,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+		&i.Username,
+		&i.Role,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const listPersonalAccessTokens = `-- name: ListPersonalAccessTokens :many
+SELECT id, user_id, name, token_hash, scopes, last_used_at, expires_at, revoked_at, created_at FROM personal_access_tokens
+WHERE user_id = $1
+  AND revoked_at IS NULL
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListPersonalAccessTokens(ctx context.Context, userID uuid.UUID) ([]PersonalAccessToken, error) {
+	rows, err := q.db.Query(ctx, listPersonalAccessTokens, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PersonalAccessToken{}
+	for rows.Next() {
+		var i PersonalAccessToken
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.TokenHash,
+			&i.Scopes,
+			&i.LastUsedAt,
+			&i.ExpiresAt,
+			&i.RevokedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokePersonalAccessToken = `-- name: RevokePersonalAccessToken :execrows
+UPDATE personal_access_tokens
+SET revoked_at = NOW()
+WHERE id = $1
+  AND user_id = $2
+  AND revoked_at IS NULL
+`
+
+type RevokePersonalAccessTokenParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) RevokePersonalAccessToken(ctx context.Context, arg RevokePersonalAccessTokenParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokePersonalAccessToken, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeUserPersonalAccessTokens = `-- name: RevokeUserPersonalAccessTokens :exec
+UPDATE personal_access_tokens
+SET revoked_at = NOW()
+WHERE user_id = $1
+  AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeUserPersonalAccessTokens(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, revokeUserPersonalAccessTokens, userID)
+	return err
+}
+
+const touchPersonalAccessToken = `-- name: TouchPersonalAccessToken :exec
+UPDATE personal_access_tokens
+SET last_used_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) TouchPersonalAccessToken(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, touchPersonalAccessToken, id)
+	return err
+}
@@ -7,12 +7,64 @@ package db
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addPostAuthor = `-- name: AddPostAuthor :exec
+INSERT INTO post_authors (
+  post_id
+ ,user_id
+) VALUES (
+  $1,$2
+) ON CONFLICT DO NOTHING
+`
+
+type AddPostAuthorParams struct {
+	PostID uuid.UUID `json:"post_id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) AddPostAuthor(ctx context.Context, arg AddPostAuthorParams) error {
+	_, err := q.db.Exec(ctx, addPostAuthor, arg.PostID, arg.UserID)
+	return err
+}
+
+const addPostSlugHistory = `-- name: AddPostSlugHistory :exec
+INSERT INTO post_slugs (
+  slug
+ ,post_id
+) VALUES (
+  $1,$2
+) ON CONFLICT (slug) DO UPDATE
+SET post_id = EXCLUDED.post_id
+   ,created_at = NOW()
+`
+
+type AddPostSlugHistoryParams struct {
+	Slug   string    `json:"slug"`
+	PostID uuid.UUID `json:"post_id"`
+}
+
+func (q *Queries) AddPostSlugHistory(ctx context.Context, arg AddPostSlugHistoryParams) error {
+	_, err := q.db.Exec(ctx, addPostSlugHistory, arg.Slug, arg.PostID)
+	return err
+}
+
+const clearPostEditor = `-- name: ClearPostEditor :exec
+UPDATE posts
+SET last_edited_by = NULL
+WHERE last_edited_by = $1
+`
+
+func (q *Queries) ClearPostEditor(ctx context.Context, lastEditedBy pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, clearPostEditor, lastEditedBy)
+	return err
+}
+
 const createPost = `-- name: CreatePost :one
 INSERT INTO posts (
   category_id
@@ -20,17 +72,31 @@ INSERT INTO posts (
  ,subtitle
  ,content
  ,publicated
+ ,author_id
+ ,slug
+ ,content_format
+ ,content_html
+ ,content_toc
+ ,render_version
+ ,render_warnings
 ) VALUES (
-  $1,$2,$3,$4,$5
-) RETURNING id, category_id, title, subtitle, content, publicated, created_at, updated_at
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12
+) RETURNING id, category_id, title, subtitle, content, publicated, created_at, updated_at, author_id, last_edited_by, slug, content_format, content_html, content_toc, render_version, render_warnings
 `
 
 type CreatePostParams struct {
-	CategoryID uuid.UUID `json:"category_id"`
-	Title      string    `json:"title"`
-	Subtitle   string    `json:"subtitle"`
-	Content    string    `json:"content"`
-	Publicated bool      `json:"publicated"`
+	CategoryID     uuid.UUID       `json:"category_id"`
+	Title          string          `json:"title"`
+	Subtitle       string          `json:"subtitle"`
+	Content        string          `json:"content"`
+	Publicated     bool            `json:"publicated"`
+	AuthorID       pgtype.UUID     `json:"author_id"`
+	Slug           string          `json:"slug"`
+	ContentFormat  string          `json:"content_format"`
+	ContentHtml    string          `json:"content_html"`
+	ContentToc     json.RawMessage `json:"content_toc"`
+	RenderVersion  int32           `json:"render_version"`
+	RenderWarnings json.RawMessage `json:"render_warnings"`
 }
 
 func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, error) {
@@ -40,6 +106,13 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		arg.Subtitle,
 		arg.Content,
 		arg.Publicated,
+		arg.AuthorID,
+		arg.Slug,
+		arg.ContentFormat,
+		arg.ContentHtml,
+		arg.ContentToc,
+		arg.RenderVersion,
+		arg.RenderWarnings,
 	)
 	var i Post
 	err := row.Scan(
@@ -51,6 +124,14 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		&i.Publicated,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AuthorID,
+		&i.LastEditedBy,
+		&i.Slug,
+		&i.ContentFormat,
+		&i.ContentHtml,
+		&i.ContentToc,
+		&i.RenderVersion,
+		&i.RenderWarnings,
 	)
 	return i, err
 }
@@ -65,36 +146,118 @@ func (q *Queries) DeletePost(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const deletePostAuthors = `-- name: DeletePostAuthors :exec
+DELETE FROM post_authors
+WHERE post_id = $1
+`
+
+func (q *Queries) DeletePostAuthors(ctx context.Context, postID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deletePostAuthors, postID)
+	return err
+}
+
+const deletePostSlugHistory = `-- name: DeletePostSlugHistory :exec
+DELETE FROM post_slugs
+WHERE slug = $1
+`
+
+func (q *Queries) DeletePostSlugHistory(ctx context.Context, slug string) error {
+	_, err := q.db.Exec(ctx, deletePostSlugHistory, slug)
+	return err
+}
+
+const deleteUserPostAuthors = `-- name: DeleteUserPostAuthors :exec
+DELETE FROM post_authors
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteUserPostAuthors(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteUserPostAuthors, userID)
+	return err
+}
+
+const getPostAccess = `-- name: GetPostAccess :one
+SELECT po.author_id
+      ,EXISTS (
+         SELECT 1
+         FROM post_authors AS pa
+         WHERE pa.post_id = po.id
+           AND pa.user_id = $1
+       ) AS is_co_author
+FROM posts AS po
+WHERE po.id = $2
+LIMIT 1
+`
+
+type GetPostAccessParams struct {
+	UserID uuid.UUID `json:"user_id"`
+	PostID uuid.UUID `json:"post_id"`
+}
+
+type GetPostAccessRow struct {
+	AuthorID   pgtype.UUID `json:"author_id"`
+	IsCoAuthor bool        `json:"is_co_author"`
+}
+
+func (q *Queries) GetPostAccess(ctx context.Context, arg GetPostAccessParams) (GetPostAccessRow, error) {
+	row := q.db.QueryRow(ctx, getPostAccess, arg.UserID, arg.PostID)
+	var i GetPostAccessRow
+	err := row.Scan(&i.AuthorID, &i.IsCoAuthor)
+	return i, err
+}
+
 const getPostByCategoryPrivate = `-- name: GetPostByCategoryPrivate :many
 SELECT po.id
       ,po.title
+      ,po.slug
       ,po.subtitle
       ,po.content
+      ,po.content_format
+      ,po.content_html
+      ,po.content_toc
       ,po.publicated
       ,po.category_id
       ,po.created_at
       ,po.updated_at
       ,ca.name AS category_name
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
+      ,ed.username AS last_edited_by
       ,ARRAY_AGG(ta.name) AS tags
 FROM posts AS po
 JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
+LEFT JOIN users AS ed ON po.last_edited_by = ed.id
 LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
 LEFT JOIN tags AS ta on pt.tag_id = ta.id 
 WHERE ca.id = $1
-GROUP BY 1,2,3,4,5,6,7,8
+GROUP BY 1,2,3,4,5,6,7,8,9,10,11,12,au.username,ed.username
 `
 
 type GetPostByCategoryPrivateRow struct {
-	ID           uuid.UUID   `json:"id"`
-	Title        string      `json:"title"`
-	Subtitle     string      `json:"subtitle"`
-	Content      string      `json:"content"`
-	Publicated   bool        `json:"publicated"`
-	CategoryID   uuid.UUID   `json:"category_id"`
-	CreatedAt    time.Time   `json:"created_at"`
-	UpdatedAt    time.Time   `json:"updated_at"`
-	CategoryName string      `json:"category_name"`
-	Tags         interface{} `json:"tags"`
+	ID            uuid.UUID       `json:"id"`
+	Title         string          `json:"title"`
+	Slug          string          `json:"slug"`
+	Subtitle      string          `json:"subtitle"`
+	Content       string          `json:"content"`
+	ContentFormat string          `json:"content_format"`
+	ContentHtml   string          `json:"content_html"`
+	ContentToc    json.RawMessage `json:"content_toc"`
+	Publicated    bool            `json:"publicated"`
+	CategoryID    uuid.UUID       `json:"category_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+	CategoryName  string          `json:"category_name"`
+	Author        pgtype.Text     `json:"author"`
+	CoAuthors     []string        `json:"co_authors"`
+	LastEditedBy  pgtype.Text     `json:"last_edited_by"`
+	Tags          interface{}     `json:"tags"`
 }
 
 func (q *Queries) GetPostByCategoryPrivate(ctx context.Context, id uuid.UUID) ([]GetPostByCategoryPrivateRow, error) {
@@ -109,13 +272,20 @@ func (q *Queries) GetPostByCategoryPrivate(ctx context.Context, id uuid.UUID) ([
 		if err := rows.Scan(
 			&i.ID,
 			&i.Title,
+			&i.Slug,
 			&i.Subtitle,
 			&i.Content,
+			&i.ContentFormat,
+			&i.ContentHtml,
+			&i.ContentToc,
 			&i.Publicated,
 			&i.CategoryID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.CategoryName,
+			&i.Author,
+			&i.CoAuthors,
+			&i.LastEditedBy,
 			&i.Tags,
 		); err != nil {
 			return nil, err
@@ -131,34 +301,53 @@ func (q *Queries) GetPostByCategoryPrivate(ctx context.Context, id uuid.UUID) ([
 const getPostByCategoryPublic = `-- name: GetPostByCategoryPublic :many
 SELECT po.id
       ,po.title
+      ,po.slug
       ,po.subtitle
       ,po.content
+      ,po.content_format
+      ,po.content_html
+      ,po.content_toc
       ,po.publicated
       ,po.category_id
       ,po.created_at
       ,po.updated_at
       ,ca.name AS category_name
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
       ,ARRAY_AGG(ta.name) AS tags
 FROM posts AS po
 JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
 LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
 LEFT JOIN tags AS ta on pt.tag_id = ta.id 
 WHERE ca.id = $1
   AND po.publicated IS TRUE
-GROUP BY 1,2,3,4,5,6,7,8
+GROUP BY 1,2,3,4,5,6,7,8,9,10,11,12,au.username
 `
 
 type GetPostByCategoryPublicRow struct {
-	ID           uuid.UUID   `json:"id"`
-	Title        string      `json:"title"`
-	Subtitle     string      `json:"subtitle"`
-	Content      string      `json:"content"`
-	Publicated   bool        `json:"publicated"`
-	CategoryID   uuid.UUID   `json:"category_id"`
-	CreatedAt    time.Time   `json:"created_at"`
-	UpdatedAt    time.Time   `json:"updated_at"`
-	CategoryName string      `json:"category_name"`
-	Tags         interface{} `json:"tags"`
+	ID            uuid.UUID       `json:"id"`
+	Title         string          `json:"title"`
+	Slug          string          `json:"slug"`
+	Subtitle      string          `json:"subtitle"`
+	Content       string          `json:"content"`
+	ContentFormat string          `json:"content_format"`
+	ContentHtml   string          `json:"content_html"`
+	ContentToc    json.RawMessage `json:"content_toc"`
+	Publicated    bool            `json:"publicated"`
+	CategoryID    uuid.UUID       `json:"category_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+	CategoryName  string          `json:"category_name"`
+	Author        pgtype.Text     `json:"author"`
+	CoAuthors     []string        `json:"co_authors"`
+	Tags          interface{}     `json:"tags"`
 }
 
 func (q *Queries) GetPostByCategoryPublic(ctx context.Context, id uuid.UUID) ([]GetPostByCategoryPublicRow, error) {
@@ -173,13 +362,19 @@ func (q *Queries) GetPostByCategoryPublic(ctx context.Context, id uuid.UUID) ([]
 		if err := rows.Scan(
 			&i.ID,
 			&i.Title,
+			&i.Slug,
 			&i.Subtitle,
 			&i.Content,
+			&i.ContentFormat,
+			&i.ContentHtml,
+			&i.ContentToc,
 			&i.Publicated,
 			&i.CategoryID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.CategoryName,
+			&i.Author,
+			&i.CoAuthors,
 			&i.Tags,
 		); err != nil {
 			return nil, err
@@ -195,34 +390,58 @@ func (q *Queries) GetPostByCategoryPublic(ctx context.Context, id uuid.UUID) ([]
 const getPostByIdPrivate = `-- name: GetPostByIdPrivate :one
 SELECT po.id
       ,po.title
+      ,po.slug
       ,po.subtitle
       ,po.content
+      ,po.content_format
+      ,po.content_html
+      ,po.content_toc
+      ,po.render_warnings
       ,po.publicated
       ,po.category_id
       ,po.created_at
       ,po.updated_at
       ,ca.name AS category_name
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
+      ,ed.username AS last_edited_by
       ,ARRAY_AGG(ta.name) AS tags
 FROM posts AS po
 JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
+LEFT JOIN users AS ed ON po.last_edited_by = ed.id
 LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
 LEFT JOIN tags AS ta on pt.tag_id = ta.id 
 WHERE po.id = $1
-GROUP BY 1,2,3,4,5,6,7,8
+GROUP BY 1,2,3,4,5,6,7,8,9,10,11,12,13,au.username,ed.username
 LIMIT 1
 `
 
 type GetPostByIdPrivateRow struct {
-	ID           uuid.UUID   `json:"id"`
-	Title        string      `json:"title"`
-	Subtitle     string      `json:"subtitle"`
-	Content      string      `json:"content"`
-	Publicated   bool        `json:"publicated"`
-	CategoryID   uuid.UUID   `json:"category_id"`
-	CreatedAt    time.Time   `json:"created_at"`
-	UpdatedAt    time.Time   `json:"updated_at"`
-	CategoryName string      `json:"category_name"`
-	Tags         interface{} `json:"tags"`
+	ID             uuid.UUID       `json:"id"`
+	Title          string          `json:"title"`
+	Slug           string          `json:"slug"`
+	Subtitle       string          `json:"subtitle"`
+	Content        string          `json:"content"`
+	ContentFormat  string          `json:"content_format"`
+	ContentHtml    string          `json:"content_html"`
+	ContentToc     json.RawMessage `json:"content_toc"`
+	RenderWarnings json.RawMessage `json:"render_warnings"`
+	Publicated     bool            `json:"publicated"`
+	CategoryID     uuid.UUID       `json:"category_id"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	CategoryName   string          `json:"category_name"`
+	Author         pgtype.Text     `json:"author"`
+	CoAuthors      []string        `json:"co_authors"`
+	LastEditedBy   pgtype.Text     `json:"last_edited_by"`
+	Tags           interface{}     `json:"tags"`
 }
 
 func (q *Queries) GetPostByIdPrivate(ctx context.Context, id uuid.UUID) (GetPostByIdPrivateRow, error) {
@@ -231,13 +450,21 @@ func (q *Queries) GetPostByIdPrivate(ctx context.Context, id uuid.UUID) (GetPost
 	err := row.Scan(
 		&i.ID,
 		&i.Title,
+		&i.Slug,
 		&i.Subtitle,
 		&i.Content,
+		&i.ContentFormat,
+		&i.ContentHtml,
+		&i.ContentToc,
+		&i.RenderWarnings,
 		&i.Publicated,
 		&i.CategoryID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.CategoryName,
+		&i.Author,
+		&i.CoAuthors,
+		&i.LastEditedBy,
 		&i.Tags,
 	)
 	return i, err
@@ -246,35 +473,54 @@ func (q *Queries) GetPostByIdPrivate(ctx context.Context, id uuid.UUID) (GetPost
 const getPostByIdPublic = `-- name: GetPostByIdPublic :one
 SELECT po.id
       ,po.title
+      ,po.slug
       ,po.subtitle
       ,po.content
+      ,po.content_format
+      ,po.content_html
+      ,po.content_toc
       ,po.publicated
       ,po.category_id
       ,po.created_at
       ,po.updated_at
       ,ca.name AS category_name
-      ,ARRAY_AGG(ta.name || "|" || ta.id) AS tags
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
+      ,ARRAY_AGG(ta.name || '|' || ta.id::text) AS tags
 FROM posts AS po
 JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
 LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
 LEFT JOIN tags AS ta on pt.tag_id = ta.id 
 WHERE po.id = $1
   AND po.publicated IS TRUE
-GROUP BY 1,2,3,4,5,6,7,8
+GROUP BY 1,2,3,4,5,6,7,8,9,10,11,12,au.username
 LIMIT 1
 `
 
 type GetPostByIdPublicRow struct {
-	ID           uuid.UUID   `json:"id"`
-	Title        string      `json:"title"`
-	Subtitle     string      `json:"subtitle"`
-	Content      string      `json:"content"`
-	Publicated   bool        `json:"publicated"`
-	CategoryID   uuid.UUID   `json:"category_id"`
-	CreatedAt    time.Time   `json:"created_at"`
-	UpdatedAt    time.Time   `json:"updated_at"`
-	CategoryName string      `json:"category_name"`
-	Tags         interface{} `json:"tags"`
+	ID            uuid.UUID       `json:"id"`
+	Title         string          `json:"title"`
+	Slug          string          `json:"slug"`
+	Subtitle      string          `json:"subtitle"`
+	Content       string          `json:"content"`
+	ContentFormat string          `json:"content_format"`
+	ContentHtml   string          `json:"content_html"`
+	ContentToc    json.RawMessage `json:"content_toc"`
+	Publicated    bool            `json:"publicated"`
+	CategoryID    uuid.UUID       `json:"category_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+	CategoryName  string          `json:"category_name"`
+	Author        pgtype.Text     `json:"author"`
+	CoAuthors     []string        `json:"co_authors"`
+	Tags          interface{}     `json:"tags"`
 }
 
 func (q *Queries) GetPostByIdPublic(ctx context.Context, id uuid.UUID) (GetPostByIdPublicRow, error) {
@@ -283,13 +529,96 @@ func (q *Queries) GetPostByIdPublic(ctx context.Context, id uuid.UUID) (GetPostB
 	err := row.Scan(
 		&i.ID,
 		&i.Title,
+		&i.Slug,
 		&i.Subtitle,
 		&i.Content,
+		&i.ContentFormat,
+		&i.ContentHtml,
+		&i.ContentToc,
 		&i.Publicated,
 		&i.CategoryID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.CategoryName,
+		&i.Author,
+		&i.CoAuthors,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const getPostBySlugPublic = `-- name: GetPostBySlugPublic :one
+SELECT po.id
+      ,po.title
+      ,po.slug
+      ,po.subtitle
+      ,po.content
+      ,po.content_format
+      ,po.content_html
+      ,po.content_toc
+      ,po.publicated
+      ,po.category_id
+      ,po.created_at
+      ,po.updated_at
+      ,ca.name AS category_name
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
+      ,ARRAY_AGG(ta.name || '|' || ta.id::text) AS tags
+FROM posts AS po
+JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
+LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
+LEFT JOIN tags AS ta on pt.tag_id = ta.id 
+WHERE po.slug = $1
+  AND po.publicated IS TRUE
+GROUP BY 1,2,3,4,5,6,7,8,9,10,11,12,au.username
+LIMIT 1
+`
+
+type GetPostBySlugPublicRow struct {
+	ID            uuid.UUID       `json:"id"`
+	Title         string          `json:"title"`
+	Slug          string          `json:"slug"`
+	Subtitle      string          `json:"subtitle"`
+	Content       string          `json:"content"`
+	ContentFormat string          `json:"content_format"`
+	ContentHtml   string          `json:"content_html"`
+	ContentToc    json.RawMessage `json:"content_toc"`
+	Publicated    bool            `json:"publicated"`
+	CategoryID    uuid.UUID       `json:"category_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+	CategoryName  string          `json:"category_name"`
+	Author        pgtype.Text     `json:"author"`
+	CoAuthors     []string        `json:"co_authors"`
+	Tags          interface{}     `json:"tags"`
+}
+
+func (q *Queries) GetPostBySlugPublic(ctx context.Context, slug string) (GetPostBySlugPublicRow, error) {
+	row := q.db.QueryRow(ctx, getPostBySlugPublic, slug)
+	var i GetPostBySlugPublicRow
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.Subtitle,
+		&i.Content,
+		&i.ContentFormat,
+		&i.ContentHtml,
+		&i.ContentToc,
+		&i.Publicated,
+		&i.CategoryID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CategoryName,
+		&i.Author,
+		&i.CoAuthors,
 		&i.Tags,
 	)
 	return i, err
@@ -298,33 +627,55 @@ func (q *Queries) GetPostByIdPublic(ctx context.Context, id uuid.UUID) (GetPostB
 const getPostByTagPrivate = `-- name: GetPostByTagPrivate :many
 SELECT po.id
       ,po.title
+      ,po.slug
       ,po.subtitle
       ,po.content
+      ,po.content_format
+      ,po.content_html
+      ,po.content_toc
       ,po.publicated
       ,po.category_id
       ,po.created_at
       ,po.updated_at
       ,ca.name AS category_name
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
+      ,ed.username AS last_edited_by
       ,ARRAY_AGG(ta.name) AS tags
 FROM posts AS po
 JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
+LEFT JOIN users AS ed ON po.last_edited_by = ed.id
 LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
 LEFT JOIN tags AS ta on pt.tag_id = ta.id 
 WHERE ta.id = $1
-GROUP BY 1,2,3,4,5,6,7,8
+GROUP BY 1,2,3,4,5,6,7,8,9,10,11,12,au.username,ed.username
 `
 
 type GetPostByTagPrivateRow struct {
-	ID           uuid.UUID   `json:"id"`
-	Title        string      `json:"title"`
-	Subtitle     string      `json:"subtitle"`
-	Content      string      `json:"content"`
-	Publicated   bool        `json:"publicated"`
-	CategoryID   uuid.UUID   `json:"category_id"`
-	CreatedAt    time.Time   `json:"created_at"`
-	UpdatedAt    time.Time   `json:"updated_at"`
-	CategoryName string      `json:"category_name"`
-	Tags         interface{} `json:"tags"`
+	ID            uuid.UUID       `json:"id"`
+	Title         string          `json:"title"`
+	Slug          string          `json:"slug"`
+	Subtitle      string          `json:"subtitle"`
+	Content       string          `json:"content"`
+	ContentFormat string          `json:"content_format"`
+	ContentHtml   string          `json:"content_html"`
+	ContentToc    json.RawMessage `json:"content_toc"`
+	Publicated    bool            `json:"publicated"`
+	CategoryID    uuid.UUID       `json:"category_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+	CategoryName  string          `json:"category_name"`
+	Author        pgtype.Text     `json:"author"`
+	CoAuthors     []string        `json:"co_authors"`
+	LastEditedBy  pgtype.Text     `json:"last_edited_by"`
+	Tags          interface{}     `json:"tags"`
 }
 
 func (q *Queries) GetPostByTagPrivate(ctx context.Context, id uuid.UUID) ([]GetPostByTagPrivateRow, error) {
@@ -339,13 +690,20 @@ func (q *Queries) GetPostByTagPrivate(ctx context.Context, id uuid.UUID) ([]GetP
 		if err := rows.Scan(
 			&i.ID,
 			&i.Title,
+			&i.Slug,
 			&i.Subtitle,
 			&i.Content,
+			&i.ContentFormat,
+			&i.ContentHtml,
+			&i.ContentToc,
 			&i.Publicated,
 			&i.CategoryID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.CategoryName,
+			&i.Author,
+			&i.CoAuthors,
+			&i.LastEditedBy,
 			&i.Tags,
 		); err != nil {
 			return nil, err
@@ -361,34 +719,53 @@ func (q *Queries) GetPostByTagPrivate(ctx context.Context, id uuid.UUID) ([]GetP
 const getPostByTagPublic = `-- name: GetPostByTagPublic :many
 SELECT po.id
       ,po.title
+      ,po.slug
       ,po.subtitle
       ,po.content
+      ,po.content_format
+      ,po.content_html
+      ,po.content_toc
       ,po.publicated
       ,po.category_id
       ,po.created_at
       ,po.updated_at
       ,ca.name AS category_name
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
       ,ARRAY_AGG(ta.name) AS tags
 FROM posts AS po
 JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
 LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
 LEFT JOIN tags AS ta on pt.tag_id = ta.id 
 WHERE ta.id = $1
   AND po.publicated IS TRUE
-GROUP BY 1,2,3,4,5,6,7,8
+GROUP BY 1,2,3,4,5,6,7,8,9,10,11,12,au.username
 `
 
 type GetPostByTagPublicRow struct {
-	ID           uuid.UUID   `json:"id"`
-	Title        string      `json:"title"`
-	Subtitle     string      `json:"subtitle"`
-	Content      string      `json:"content"`
-	Publicated   bool        `json:"publicated"`
-	CategoryID   uuid.UUID   `json:"category_id"`
-	CreatedAt    time.Time   `json:"created_at"`
-	UpdatedAt    time.Time   `json:"updated_at"`
-	CategoryName string      `json:"category_name"`
-	Tags         interface{} `json:"tags"`
+	ID            uuid.UUID       `json:"id"`
+	Title         string          `json:"title"`
+	Slug          string          `json:"slug"`
+	Subtitle      string          `json:"subtitle"`
+	Content       string          `json:"content"`
+	ContentFormat string          `json:"content_format"`
+	ContentHtml   string          `json:"content_html"`
+	ContentToc    json.RawMessage `json:"content_toc"`
+	Publicated    bool            `json:"publicated"`
+	CategoryID    uuid.UUID       `json:"category_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+	CategoryName  string          `json:"category_name"`
+	Author        pgtype.Text     `json:"author"`
+	CoAuthors     []string        `json:"co_authors"`
+	Tags          interface{}     `json:"tags"`
 }
 
 func (q *Queries) GetPostByTagPublic(ctx context.Context, id uuid.UUID) ([]GetPostByTagPublicRow, error) {
@@ -403,13 +780,19 @@ func (q *Queries) GetPostByTagPublic(ctx context.Context, id uuid.UUID) ([]GetPo
 		if err := rows.Scan(
 			&i.ID,
 			&i.Title,
+			&i.Slug,
 			&i.Subtitle,
 			&i.Content,
+			&i.ContentFormat,
+			&i.ContentHtml,
+			&i.ContentToc,
 			&i.Publicated,
 			&i.CategoryID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.CategoryName,
+			&i.Author,
+			&i.CoAuthors,
 			&i.Tags,
 		); err != nil {
 			return nil, err
@@ -422,28 +805,227 @@ func (q *Queries) GetPostByTagPublic(ctx context.Context, id uuid.UUID) ([]GetPo
 	return items, nil
 }
 
+const getPostLink = `-- name: GetPostLink :one
+SELECT id
+      ,title
+      ,slug
+      ,publicated
+FROM posts
+WHERE id = $1
+LIMIT 1
+`
+
+type GetPostLinkRow struct {
+	ID         uuid.UUID `json:"id"`
+	Title      string    `json:"title"`
+	Slug       string    `json:"slug"`
+	Publicated bool      `json:"publicated"`
+}
+
+func (q *Queries) GetPostLink(ctx context.Context, id uuid.UUID) (GetPostLinkRow, error) {
+	row := q.db.QueryRow(ctx, getPostLink, id)
+	var i GetPostLinkRow
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Slug,
+		&i.Publicated,
+	)
+	return i, err
+}
+
+const getPostSlug = `-- name: GetPostSlug :one
+SELECT slug FROM posts
+WHERE id = $1
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetPostSlug(ctx context.Context, id uuid.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, getPostSlug, id)
+	var slug string
+	err := row.Scan(&slug)
+	return slug, err
+}
+
+const getPostSlugRedirectPublic = `-- name: GetPostSlugRedirectPublic :one
+SELECT po.slug
+FROM post_slugs AS ps
+JOIN posts AS po ON ps.post_id = po.id
+WHERE ps.slug = $1
+  AND po.publicated IS TRUE
+LIMIT 1
+`
+
+func (q *Queries) GetPostSlugRedirectPublic(ctx context.Context, slug string) (string, error) {
+	row := q.db.QueryRow(ctx, getPostSlugRedirectPublic, slug)
+	err := row.Scan(&slug)
+	return slug, err
+}
+
+const getPostsByAuthorPublic = `-- name: GetPostsByAuthorPublic :many
+SELECT po.id
+      ,po.title
+      ,po.slug
+      ,po.subtitle
+      ,po.created_at
+      ,po.category_id
+      ,ca.name AS category_name
+      ,ARRAY_AGG(ta.name) AS tags
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
+FROM posts AS po
+JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
+LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
+LEFT JOIN tags AS ta on pt.tag_id = ta.id
+WHERE po.publicated IS TRUE
+  AND (
+    au.username = $1
+    OR EXISTS (
+      SELECT 1
+      FROM post_authors AS pa
+      JOIN users AS cu ON pa.user_id = cu.id
+      WHERE pa.post_id = po.id
+        AND cu.username = $1
+    )
+  )
+GROUP BY 1,2,3,4,5,6,7,au.username
+`
+
+type GetPostsByAuthorPublicRow struct {
+	ID           uuid.UUID   `json:"id"`
+	Title        string      `json:"title"`
+	Slug         string      `json:"slug"`
+	Subtitle     string      `json:"subtitle"`
+	CreatedAt    time.Time   `json:"created_at"`
+	CategoryID   uuid.UUID   `json:"category_id"`
+	CategoryName string      `json:"category_name"`
+	Tags         interface{} `json:"tags"`
+	Author       pgtype.Text `json:"author"`
+	CoAuthors    []string    `json:"co_authors"`
+}
+
+func (q *Queries) GetPostsByAuthorPublic(ctx context.Context, username string) ([]GetPostsByAuthorPublicRow, error) {
+	rows, err := q.db.Query(ctx, getPostsByAuthorPublic, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetPostsByAuthorPublicRow{}
+	for rows.Next() {
+		var i GetPostsByAuthorPublicRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.Subtitle,
+			&i.CreatedAt,
+			&i.CategoryID,
+			&i.CategoryName,
+			&i.Tags,
+			&i.Author,
+			&i.CoAuthors,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPostRenderWarnings = `-- name: ListPostRenderWarnings :many
+SELECT id
+      ,title
+      ,slug
+      ,render_warnings
+      ,updated_at
+FROM posts
+WHERE render_warnings <> '[]'::jsonb
+ORDER BY updated_at DESC
+`
+
+type ListPostRenderWarningsRow struct {
+	ID             uuid.UUID       `json:"id"`
+	Title          string          `json:"title"`
+	Slug           string          `json:"slug"`
+	RenderWarnings json.RawMessage `json:"render_warnings"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+func (q *Queries) ListPostRenderWarnings(ctx context.Context) ([]ListPostRenderWarningsRow, error) {
+	rows, err := q.db.Query(ctx, listPostRenderWarnings)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPostRenderWarningsRow{}
+	for rows.Next() {
+		var i ListPostRenderWarningsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Slug,
+			&i.RenderWarnings,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPostsPrivate = `-- name: ListPostsPrivate :many
 SELECT po.id
       ,po.title
+      ,po.slug
       ,po.subtitle
       ,po.created_at
       ,po.category_id
       ,ca.name AS category_name
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
+      ,ed.username AS last_edited_by
       ,ARRAY_AGG(ta.name) AS tags
 FROM posts AS po
 JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
+LEFT JOIN users AS ed ON po.last_edited_by = ed.id
 LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
 LEFT JOIN tags AS ta on pt.tag_id = ta.id
-GROUP BY 1,2,3,4,5,6
+GROUP BY 1,2,3,4,5,6,7,au.username,ed.username
 `
 
 type ListPostsPrivateRow struct {
 	ID           uuid.UUID   `json:"id"`
 	Title        string      `json:"title"`
+	Slug         string      `json:"slug"`
 	Subtitle     string      `json:"subtitle"`
 	CreatedAt    time.Time   `json:"created_at"`
 	CategoryID   uuid.UUID   `json:"category_id"`
 	CategoryName string      `json:"category_name"`
+	Author       pgtype.Text `json:"author"`
+	CoAuthors    []string    `json:"co_authors"`
+	LastEditedBy pgtype.Text `json:"last_edited_by"`
 	Tags         interface{} `json:"tags"`
 }
 
@@ -459,10 +1041,14 @@ func (q *Queries) ListPostsPrivate(ctx context.Context) ([]ListPostsPrivateRow,
 		if err := rows.Scan(
 			&i.ID,
 			&i.Title,
+			&i.Slug,
 			&i.Subtitle,
 			&i.CreatedAt,
 			&i.CategoryID,
 			&i.CategoryName,
+			&i.Author,
+			&i.CoAuthors,
+			&i.LastEditedBy,
 			&i.Tags,
 		); err != nil {
 			return nil, err
@@ -478,26 +1064,39 @@ func (q *Queries) ListPostsPrivate(ctx context.Context) ([]ListPostsPrivateRow,
 const listPostsPublic = `-- name: ListPostsPublic :many
 SELECT po.id
       ,po.title
+      ,po.slug
       ,po.subtitle
       ,po.created_at
       ,po.category_id
       ,ca.name AS category_name
+      ,au.username AS author
+      ,ARRAY(
+         SELECT cu.username
+         FROM post_authors AS pa
+         JOIN users AS cu ON pa.user_id = cu.id
+         WHERE pa.post_id = po.id
+         ORDER BY pa.created_at
+       )::varchar[] AS co_authors
       ,ARRAY_AGG(ta.name) AS tags
 FROM posts AS po
 JOIN categories AS ca ON po.category_id = ca.id
+LEFT JOIN users AS au ON po.author_id = au.id
 LEFT JOIN posts_tags AS pt ON pt.post_id = po.id
 LEFT JOIN tags AS ta on pt.tag_id = ta.id
 WHERE po.publicated IS TRUE
-GROUP BY 1,2,3,4,5,6
+GROUP BY 1,2,3,4,5,6,7,au.username
 `
 
 type ListPostsPublicRow struct {
 	ID           uuid.UUID   `json:"id"`
 	Title        string      `json:"title"`
+	Slug         string      `json:"slug"`
 	Subtitle     string      `json:"subtitle"`
 	CreatedAt    time.Time   `json:"created_at"`
 	CategoryID   uuid.UUID   `json:"category_id"`
 	CategoryName string      `json:"category_name"`
+	Author       pgtype.Text `json:"author"`
+	CoAuthors    []string    `json:"co_authors"`
 	Tags         interface{} `json:"tags"`
 }
 
@@ -513,10 +1112,13 @@ func (q *Queries) ListPostsPublic(ctx context.Context) ([]ListPostsPublicRow, er
 		if err := rows.Scan(
 			&i.ID,
 			&i.Title,
+			&i.Slug,
 			&i.Subtitle,
 			&i.CreatedAt,
 			&i.CategoryID,
 			&i.CategoryName,
+			&i.Author,
+			&i.CoAuthors,
 			&i.Tags,
 		); err != nil {
 			return nil, err
@@ -529,36 +1131,216 @@ func (q *Queries) ListPostsPublic(ctx context.Context) ([]ListPostsPublicRow, er
 	return items, nil
 }
 
+const listStaleRenderedPosts = `-- name: ListStaleRenderedPosts :many
+SELECT id
+      ,content_format
+      ,content
+      ,updated_at
+FROM posts
+WHERE render_version < $1
+ORDER BY created_at
+LIMIT $2
+`
+
+type ListStaleRenderedPostsParams struct {
+	RenderVersion int32 `json:"render_version"`
+	LimitCount    int32 `json:"limit_count"`
+}
+
+type ListStaleRenderedPostsRow struct {
+	ID            uuid.UUID `json:"id"`
+	ContentFormat string    `json:"content_format"`
+	Content       string    `json:"content"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (q *Queries) ListStaleRenderedPosts(ctx context.Context, arg ListStaleRenderedPostsParams) ([]ListStaleRenderedPostsRow, error) {
+	rows, err := q.db.Query(ctx, listStaleRenderedPosts, arg.RenderVersion, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStaleRenderedPostsRow{}
+	for rows.Next() {
+		var i ListStaleRenderedPostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ContentFormat,
+			&i.Content,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markLinkingPostsStale = `-- name: MarkLinkingPostsStale :execrows
+UPDATE posts
+SET render_version = 0
+WHERE id <> $1::uuid
+  AND strpos(content, $1::uuid::text) > 0
+`
+
+func (q *Queries) MarkLinkingPostsStale(ctx context.Context, postID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, markLinkingPostsStale, postID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const postSlugExists = `-- name: PostSlugExists :one
+SELECT EXISTS (
+  SELECT 1
+  FROM posts
+  WHERE slug = $1
+    AND id <> $2
+)
+`
+
+type PostSlugExistsParams struct {
+	Slug   string    `json:"slug"`
+	PostID uuid.UUID `json:"post_id"`
+}
+
+func (q *Queries) PostSlugExists(ctx context.Context, arg PostSlugExistsParams) (bool, error) {
+	row := q.db.QueryRow(ctx, postSlugExists, arg.Slug, arg.PostID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const setPostRendering = `-- name: SetPostRendering :execrows
+UPDATE posts
+SET
+  content_html = $1
+ ,content_toc = $2
+ ,render_version = $3
+ ,render_warnings = $4
+WHERE id = $5
+  AND updated_at = $6
+`
+
+type SetPostRenderingParams struct {
+	ContentHtml    string          `json:"content_html"`
+	ContentToc     json.RawMessage `json:"content_toc"`
+	RenderVersion  int32           `json:"render_version"`
+	RenderWarnings json.RawMessage `json:"render_warnings"`
+	ID             uuid.UUID       `json:"id"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+func (q *Queries) SetPostRendering(ctx context.Context, arg SetPostRenderingParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setPostRendering,
+		arg.ContentHtml,
+		arg.ContentToc,
+		arg.RenderVersion,
+		arg.RenderWarnings,
+		arg.ID,
+		arg.UpdatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const transferPostAuthor = `-- name: TransferPostAuthor :exec
+UPDATE posts
+SET author_id = $1
+WHERE author_id = $2
+`
+
+type TransferPostAuthorParams struct {
+	ToUserID   pgtype.UUID `json:"to_user_id"`
+	FromUserID pgtype.UUID `json:"from_user_id"`
+}
+
+func (q *Queries) TransferPostAuthor(ctx context.Context, arg TransferPostAuthorParams) error {
+	_, err := q.db.Exec(ctx, transferPostAuthor, arg.ToUserID, arg.FromUserID)
+	return err
+}
+
+const transferPostCoAuthors = `-- name: TransferPostCoAuthors :exec
+INSERT INTO post_authors (
+  post_id
+ ,user_id
+)
+SELECT pa.post_id
+      ,$1
+FROM post_authors AS pa
+JOIN posts AS po ON pa.post_id = po.id
+WHERE pa.user_id = $2
+  AND po.author_id IS DISTINCT FROM $1
+ON CONFLICT DO NOTHING
+`
+
+type TransferPostCoAuthorsParams struct {
+	ToUserID   uuid.UUID `json:"to_user_id"`
+	FromUserID uuid.UUID `json:"from_user_id"`
+}
+
+func (q *Queries) TransferPostCoAuthors(ctx context.Context, arg TransferPostCoAuthorsParams) error {
+	_, err := q.db.Exec(ctx, transferPostCoAuthors, arg.ToUserID, arg.FromUserID)
+	return err
+}
+
 const updatePost = `-- name: UpdatePost :one
 UPDATE posts
 SET
   title = COALESCE($1, title)
- ,subtitle = COALESCE($2, subtitle)
- ,content = COALESCE($3, content)
- ,publicated = COALESCE($4, publicated)
- ,category_id = COALESCE($5, category_id)
+ ,slug = COALESCE($2, slug)
+ ,subtitle = COALESCE($3, subtitle)
+ ,content = COALESCE($4, content)
+ ,content_format = COALESCE($5, content_format)
+ ,content_html = COALESCE($6, content_html)
+ ,content_toc = COALESCE($7, content_toc)
+ ,render_version = COALESCE($8, render_version)
+ ,render_warnings = COALESCE($9, render_warnings)
+ ,publicated = COALESCE($10, publicated)
+ ,category_id = COALESCE($11, category_id)
+ ,last_edited_by = $12
  ,updated_at = NOW()
 WHERE
-  id = $6
-RETURNING id, category_id, title, subtitle, content, publicated, created_at, updated_at
+  id = $13
+RETURNING id, category_id, title, subtitle, content, publicated, created_at, updated_at, author_id, last_edited_by, slug, content_format, content_html, content_toc, render_version, render_warnings
 `
 
 type UpdatePostParams struct {
-	Title      pgtype.Text `json:"title"`
-	Subtitle   pgtype.Text `json:"subtitle"`
-	Content    pgtype.Text `json:"content"`
-	Publicated pgtype.Bool `json:"publicated"`
-	CategoryID pgtype.UUID `json:"category_id"`
-	ID         uuid.UUID   `json:"id"`
+	Title          pgtype.Text     `json:"title"`
+	Slug           pgtype.Text     `json:"slug"`
+	Subtitle       pgtype.Text     `json:"subtitle"`
+	Content        pgtype.Text     `json:"content"`
+	ContentFormat  pgtype.Text     `json:"content_format"`
+	ContentHtml    pgtype.Text     `json:"content_html"`
+	ContentToc     json.RawMessage `json:"content_toc"`
+	RenderVersion  pgtype.Int4     `json:"render_version"`
+	RenderWarnings json.RawMessage `json:"render_warnings"`
+	Publicated     pgtype.Bool     `json:"publicated"`
+	CategoryID     pgtype.UUID     `json:"category_id"`
+	LastEditedBy   pgtype.UUID     `json:"last_edited_by"`
+	ID             uuid.UUID       `json:"id"`
 }
 
 func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (Post, error) {
 	row := q.db.QueryRow(ctx, updatePost,
 		arg.Title,
+		arg.Slug,
 		arg.Subtitle,
 		arg.Content,
+		arg.ContentFormat,
+		arg.ContentHtml,
+		arg.ContentToc,
+		arg.RenderVersion,
+		arg.RenderWarnings,
 		arg.Publicated,
 		arg.CategoryID,
+		arg.LastEditedBy,
 		arg.ID,
 	)
 	var i Post
@@ -571,6 +1353,14 @@ func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) (Post, e
 		&i.Publicated,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.AuthorID,
+		&i.LastEditedBy,
+		&i.Slug,
+		&i.ContentFormat,
+		&i.ContentHtml,
+		&i.ContentToc,
+		&i.RenderVersion,
+		&i.RenderWarnings,
 	)
 	return i, err
 }
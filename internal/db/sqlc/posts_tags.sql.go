@@ -47,3 +47,22 @@ func (q *Queries) DeletePostTag(ctx context.Context, id uuid.UUID) error {
 	_, err := q.db.Exec(ctx, deletePostTag, id)
 	return err
 }
+
+const getPostTag = `-- name: GetPostTag :one
+SELECT id, post_id, tag_id, created_at, updated_at FROM posts_tags
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetPostTag(ctx context.Context, id uuid.UUID) (PostsTag, error) {
+	row := q.db.QueryRow(ctx, getPostTag, id)
+	var i PostsTag
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.TagID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -6,42 +6,139 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
-	CreateCategory(ctx context.Context, name string) (Category, error)
+	AcceptInvite(ctx context.Context, id uuid.UUID) (int64, error)
+	AddPostAuthor(ctx context.Context, arg AddPostAuthorParams) error
+	AddPostSlugHistory(ctx context.Context, arg AddPostSlugHistoryParams) error
+	BlockSession(ctx context.Context, arg BlockSessionParams) (int64, error)
+	BlockSessionFamily(ctx context.Context, familyID uuid.UUID) error
+	BlockUserSessions(ctx context.Context, username string) error
+	CategorySlugExists(ctx context.Context, arg CategorySlugExistsParams) (bool, error)
+	ClearPostEditor(ctx context.Context, lastEditedBy pgtype.UUID) error
+	ConsumeOIDCLogin(ctx context.Context, stateHash string) (OidcLogin, error)
+	ConsumeWebAuthnSession(ctx context.Context, arg ConsumeWebAuthnSessionParams) (WebauthnSession, error)
+	CountRecentEmailVerifications(ctx context.Context, arg CountRecentEmailVerificationsParams) (int64, error)
+	CountRecentPasswordResetTokens(ctx context.Context, arg CountRecentPasswordResetTokensParams) (int64, error)
+	CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error)
+	CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error)
+	CreateEmailVerification(ctx context.Context, arg CreateEmailVerificationParams) (EmailVerification, error)
+	CreateInvite(ctx context.Context, arg CreateInviteParams) (Invite, error)
+	CreateMFAChallenge(ctx context.Context, arg CreateMFAChallengeParams) (MfaChallenge, error)
+	CreateOIDCLogin(ctx context.Context, arg CreateOIDCLoginParams) (OidcLogin, error)
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) (PasswordResetToken, error)
+	CreatePersonalAccessToken(ctx context.Context, arg CreatePersonalAccessTokenParams) (PersonalAccessToken, error)
 	CreatePost(ctx context.Context, arg CreatePostParams) (Post, error)
 	CreatePostTag(ctx context.Context, arg CreatePostTagParams) (PostsTag, error)
+	CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error)
+	CreateWebAuthnCredential(ctx context.Context, arg CreateWebAuthnCredentialParams) (WebauthnCredential, error)
+	CreateWebAuthnSession(ctx context.Context, arg CreateWebAuthnSessionParams) (WebauthnSession, error)
 	DeleteCategory(ctx context.Context, id uuid.UUID) error
+	DeleteDisabledUser(ctx context.Context, id uuid.UUID) (int64, error)
+	DeleteEmailVerifications(ctx context.Context, userID uuid.UUID) error
+	DeleteExpiredEmailVerifications(ctx context.Context) (int64, error)
+	DeleteExpiredMFAChallenges(ctx context.Context) (int64, error)
+	DeleteExpiredOIDCLogins(ctx context.Context) (int64, error)
+	DeleteExpiredPasswordResetTokens(ctx context.Context) (int64, error)
+	DeleteExpiredSessions(ctx context.Context) (int64, error)
+	DeleteExpiredWebAuthnSessions(ctx context.Context) (int64, error)
+	DeleteMFAChallenge(ctx context.Context, id uuid.UUID) (int64, error)
+	DeletePasswordResetTokens(ctx context.Context, userID uuid.UUID) error
 	DeletePost(ctx context.Context, id uuid.UUID) error
+	DeletePostAuthors(ctx context.Context, postID uuid.UUID) error
+	DeletePostSlugHistory(ctx context.Context, slug string) error
 	DeletePostTag(ctx context.Context, id uuid.UUID) error
+	DeleteRecoveryCodes(ctx context.Context, userID uuid.UUID) error
+	DeleteStaleLoginAttempts(ctx context.Context, resetBefore time.Time) (int64, error)
 	DeleteTag(ctx context.Context, id uuid.UUID) error
-	DeleteUser(ctx context.Context, id uuid.UUID) error
-	GetCategory(ctx context.Context, id uuid.UUID) (Category, error)
+	DeleteUserPostAuthors(ctx context.Context, userID uuid.UUID) error
+	DeleteUserSessions(ctx context.Context, username string) error
+	DeleteWebAuthnCredential(ctx context.Context, arg DeleteWebAuthnCredentialParams) (int64, error)
+	DisableUser(ctx context.Context, id uuid.UUID) (int64, error)
+	DisableUserTOTP(ctx context.Context, id uuid.UUID) error
+	EnableUser(ctx context.Context, id uuid.UUID) (int64, error)
+	EnableUserTOTP(ctx context.Context, arg EnableUserTOTPParams) (int64, error)
+	GetAuthorByUsername(ctx context.Context, username string) (GetAuthorByUsernameRow, error)
+	GetEmailVerificationByHash(ctx context.Context, tokenHash string) (EmailVerification, error)
+	GetInviteByHash(ctx context.Context, tokenHash string) (Invite, error)
+	GetLoginLock(ctx context.Context, arg GetLoginLockParams) (pgtype.Timestamptz, error)
+	GetMFAChallengeByHash(ctx context.Context, tokenHash string) (MfaChallenge, error)
+	GetPasswordResetTokenByHash(ctx context.Context, tokenHash string) (GetPasswordResetTokenByHashRow, error)
+	GetPersonalAccessTokenByHash(ctx context.Context, tokenHash string) (GetPersonalAccessTokenByHashRow, error)
+	GetPostAccess(ctx context.Context, arg GetPostAccessParams) (GetPostAccessRow, error)
 	GetPostByCategoryPrivate(ctx context.Context, id uuid.UUID) ([]GetPostByCategoryPrivateRow, error)
 	GetPostByCategoryPublic(ctx context.Context, id uuid.UUID) ([]GetPostByCategoryPublicRow, error)
 	GetPostByIdPrivate(ctx context.Context, id uuid.UUID) (GetPostByIdPrivateRow, error)
 	GetPostByIdPublic(ctx context.Context, id uuid.UUID) (GetPostByIdPublicRow, error)
+	GetPostBySlugPublic(ctx context.Context, slug string) (GetPostBySlugPublicRow, error)
 	GetPostByTagPrivate(ctx context.Context, id uuid.UUID) ([]GetPostByTagPrivateRow, error)
 	GetPostByTagPublic(ctx context.Context, id uuid.UUID) ([]GetPostByTagPublicRow, error)
+	GetPostLink(ctx context.Context, id uuid.UUID) (GetPostLinkRow, error)
+	GetPostSlug(ctx context.Context, id uuid.UUID) (string, error)
+	GetPostSlugRedirectPublic(ctx context.Context, slug string) (string, error)
+	GetPostTag(ctx context.Context, id uuid.UUID) (PostsTag, error)
+	GetPostsByAuthorPublic(ctx context.Context, username string) ([]GetPostsByAuthorPublicRow, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
-	GetTag(ctx context.Context, id uuid.UUID) (Tag, error)
-	GetTagByName(ctx context.Context, name string) (Tag, error)
-	GetUser(ctx context.Context, id uuid.UUID) (User, error)
+	GetTag(ctx context.Context, id uuid.UUID) (GetTagRow, error)
+	GetUser(ctx context.Context, id uuid.UUID) (GetUserRow, error)
+	GetUserByEmail(ctx context.Context, email string) (GetUserByEmailRow, error)
 	GetUserByUsername(ctx context.Context, username string) (GetUserByUsernameRow, error)
-	ListCategories(ctx context.Context) ([]Category, error)
+	GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error)
+	GetUserTOTP(ctx context.Context, id uuid.UUID) (GetUserTOTPRow, error)
+	GetWebAuthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (WebauthnCredential, error)
+	IncrementMFAChallengeAttempts(ctx context.Context, id uuid.UUID) error
+	ListActiveSessions(ctx context.Context, username string) ([]Session, error)
+	ListAuthors(ctx context.Context) ([]ListAuthorsRow, error)
+	ListCategories(ctx context.Context) ([]ListCategoriesRow, error)
+	ListPendingInvites(ctx context.Context) ([]ListPendingInvitesRow, error)
+	ListPersonalAccessTokens(ctx context.Context, userID uuid.UUID) ([]PersonalAccessToken, error)
+	ListPostRenderWarnings(ctx context.Context) ([]ListPostRenderWarningsRow, error)
 	ListPostsPrivate(ctx context.Context) ([]ListPostsPrivateRow, error)
 	ListPostsPublic(ctx context.Context) ([]ListPostsPublicRow, error)
-	ListTags(ctx context.Context) ([]Tag, error)
+	ListStaleRenderedPosts(ctx context.Context, arg ListStaleRenderedPostsParams) ([]ListStaleRenderedPostsRow, error)
+	ListTags(ctx context.Context) ([]ListTagsRow, error)
 	ListUsers(ctx context.Context) ([]ListUsersRow, error)
+	ListWebAuthnCredentials(ctx context.Context, userID uuid.UUID) ([]WebauthnCredential, error)
+	LockActiveUsersByRole(ctx context.Context, role string) ([]uuid.UUID, error)
+	LockLogin(ctx context.Context, arg LockLoginParams) error
+	LookupCategory(ctx context.Context, arg LookupCategoryParams) (LookupCategoryRow, error)
+	LookupTag(ctx context.Context, arg LookupTagParams) (LookupTagRow, error)
+	MarkLinkingPostsStale(ctx context.Context, postID uuid.UUID) (int64, error)
+	PostSlugExists(ctx context.Context, arg PostSlugExistsParams) (bool, error)
+	RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) (LoginAttempt, error)
+	RehashUserPassword(ctx context.Context, arg RehashUserPasswordParams) (int64, error)
+	ResetLoginAttempts(ctx context.Context, arg ResetLoginAttemptsParams) error
+	RevokeInvite(ctx context.Context, id uuid.UUID) (int64, error)
+	RevokePersonalAccessToken(ctx context.Context, arg RevokePersonalAccessTokenParams) (int64, error)
+	RevokeUserPersonalAccessTokens(ctx context.Context, userID uuid.UUID) error
+	RotateSession(ctx context.Context, id uuid.UUID) (Session, error)
+	SetPostRendering(ctx context.Context, arg SetPostRenderingParams) (int64, error)
+	SetUserAvatar(ctx context.Context, arg SetUserAvatarParams) (User, error)
+	SetUserTOTPSecret(ctx context.Context, arg SetUserTOTPSecretParams) (int64, error)
+	TagSlugExists(ctx context.Context, arg TagSlugExistsParams) (bool, error)
+	TouchPersonalAccessToken(ctx context.Context, id uuid.UUID) error
+	TransferPostAuthor(ctx context.Context, arg TransferPostAuthorParams) error
+	TransferPostCoAuthors(ctx context.Context, arg TransferPostCoAuthorsParams) error
+	UpdateAuthorProfile(ctx context.Context, arg UpdateAuthorProfileParams) (User, error)
 	UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (Category, error)
 	UpdatePost(ctx context.Context, arg UpdatePostParams) (Post, error)
+	UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateWebAuthnCredentialUse(ctx context.Context, arg UpdateWebAuthnCredentialUseParams) error
+	UseEmailVerification(ctx context.Context, id uuid.UUID) (int64, error)
+	UsePasswordResetToken(ctx context.Context, id uuid.UUID) (int64, error)
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error)
+	UseTOTPStep(ctx context.Context, arg UseTOTPStepParams) (int64, error)
+	VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error)
 }
 
 var _ Querier = (*Queries)(nil)
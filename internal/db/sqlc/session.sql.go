@@ -10,8 +10,56 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const blockSession = `-- name: BlockSession :execrows
+UPDATE sessions
+SET is_blocked = TRUE
+WHERE family_id = (
+  SELECT s.family_id
+  FROM sessions AS s
+  WHERE s.id = $1
+    AND s.username = $2
+)
+`
+
+type BlockSessionParams struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+}
+
+func (q *Queries) BlockSession(ctx context.Context, arg BlockSessionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, blockSession, arg.ID, arg.Username)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const blockSessionFamily = `-- name: BlockSessionFamily :exec
+UPDATE sessions
+SET is_blocked = TRUE
+WHERE family_id = $1
+`
+
+func (q *Queries) BlockSessionFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, blockSessionFamily, familyID)
+	return err
+}
+
+const blockUserSessions = `-- name: BlockUserSessions :exec
+UPDATE sessions
+SET is_blocked = TRUE
+WHERE username = $1
+  AND is_blocked IS FALSE
+`
+
+func (q *Queries) BlockUserSessions(ctx context.Context, username string) error {
+	_, err := q.db.Exec(ctx, blockUserSessions, username)
+	return err
+}
+
 const createSession = `-- name: CreateSession :one
 INSERT INTO sessions (
   id,
@@ -20,20 +68,24 @@ INSERT INTO sessions (
   user_agent,
   client_ip,
   is_blocked,
-  expires_at
+  expires_at,
+  family_id,
+  parent_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7
-) RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, parent_id, rotated_at
 `
 
 type CreateSessionParams struct {
-	ID           uuid.UUID `json:"id"`
-	Username     string    `json:"username"`
-	RefreshToken string    `json:"refresh_token"`
-	UserAgent    string    `json:"user_agent"`
-	ClientIp     string    `json:"client_ip"`
-	IsBlocked    bool      `json:"is_blocked"`
-	ExpiresAt    time.Time `json:"expires_at"`
+	ID           uuid.UUID   `json:"id"`
+	Username     string      `json:"username"`
+	RefreshToken string      `json:"refresh_token"`
+	UserAgent    string      `json:"user_agent"`
+	ClientIp     string      `json:"client_ip"`
+	IsBlocked    bool        `json:"is_blocked"`
+	ExpiresAt    time.Time   `json:"expires_at"`
+	FamilyID     uuid.UUID   `json:"family_id"`
+	ParentID     pgtype.UUID `json:"parent_id"`
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
@@ -45,6 +97,8 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		arg.ClientIp,
 		arg.IsBlocked,
 		arg.ExpiresAt,
+		arg.FamilyID,
+		arg.ParentID,
 	)
 	var i Session
 	err := row.Scan(
@@ -56,12 +110,38 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.IsBlocked,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.RotatedAt,
 	)
 	return i, err
 }
 
+const deleteExpiredSessions = `-- name: DeleteExpiredSessions :execrows
+DELETE FROM sessions
+WHERE expires_at <= NOW()
+`
+
+func (q *Queries) DeleteExpiredSessions(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredSessions)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteUserSessions = `-- name: DeleteUserSessions :exec
+DELETE FROM sessions
+WHERE username = $1
+`
+
+func (q *Queries) DeleteUserSessions(ctx context.Context, username string) error {
+	_, err := q.db.Exec(ctx, deleteUserSessions, username)
+	return err
+}
+
 const getSession = `-- name: GetSession :one
-SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at FROM sessions
+SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, parent_id, rotated_at FROM sessions
 WHERE id = $1 LIMIT 1
 `
 
@@ -77,6 +157,78 @@ func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 		&i.IsBlocked,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.RotatedAt,
+	)
+	return i, err
+}
+
+const listActiveSessions = `-- name: ListActiveSessions :many
+SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, parent_id, rotated_at FROM sessions
+WHERE username = $1
+  AND is_blocked IS FALSE
+  AND rotated_at IS NULL
+  AND expires_at > NOW()
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListActiveSessions(ctx context.Context, username string) ([]Session, error) {
+	rows, err := q.db.Query(ctx, listActiveSessions, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Session{}
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.RefreshToken,
+			&i.UserAgent,
+			&i.ClientIp,
+			&i.IsBlocked,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.FamilyID,
+			&i.ParentID,
+			&i.RotatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rotateSession = `-- name: RotateSession :one
+UPDATE sessions
+SET rotated_at = NOW()
+WHERE id = $1
+  AND is_blocked IS FALSE
+  AND rotated_at IS NULL
+RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, family_id, parent_id, rotated_at
+`
+
+func (q *Queries) RotateSession(ctx context.Context, id uuid.UUID) (Session, error) {
+	row := q.db.QueryRow(ctx, rotateSession, id)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.RefreshToken,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.IsBlocked,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.ParentID,
+		&i.RotatedAt,
 	)
 	return i, err
 }
@@ -1,9 +1,30 @@
 package db
 
 import (
+	"context"
+
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// Store provides all functions to execute db queries and transactions
+type Store interface {
+	Querier
+	RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (RotateSessionTxResult, error)
+	EnableTOTPTx(ctx context.Context, arg EnableTOTPTxParams) error
+	DisableTOTPTx(ctx context.Context, userID uuid.UUID) error
+	ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) error
+	ChangePasswordTx(ctx context.Context, arg ChangePasswordTxParams) error
+	VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error)
+	AcceptInviteTx(ctx context.Context, arg AcceptInviteTxParams) (AcceptInviteTxResult, error)
+	CreatePostTx(ctx context.Context, arg CreatePostTxParams) (Post, error)
+	UpdatePostTx(ctx context.Context, arg UpdatePostTxParams) (Post, error)
+	UpdateUserTx(ctx context.Context, arg UpdateUserParams) (User, error)
+	DeactivateUserTx(ctx context.Context, arg DeactivateUserTxParams) error
+	PurgeUserTx(ctx context.Context, arg PurgeUserTxParams) error
+	ProvisionOIDCUserTx(ctx context.Context, arg ProvisionOIDCUserTxParams) (User, error)
+}
+
 // SQLStore provides all functions to execute SQL queries and transactions
 type SQLStore struct {
 	connPool *pgxpool.Pool
@@ -11,7 +32,7 @@ type SQLStore struct {
 }
 
 // NewStore creates a new store
-func NewStore(connPool *pgxpool.Pool) Querier {
+func NewStore(connPool *pgxpool.Pool) Store {
 	return &SQLStore{
 		connPool: connPool,
 		Queries:  New(connPool),
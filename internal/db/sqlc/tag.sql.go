@@ -7,26 +7,37 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createTag = `-- name: CreateTag :one
 INSERT INTO tags (
   name,
+  slug,
+  description,
   image_url
 ) VALUES (
-  $1,$2
-) RETURNING id, name, image_url, created_at, updated_at
+  $1,$2,$3,$4
+) RETURNING id, name, image_url, created_at, updated_at, slug, description
 `
 
 type CreateTagParams struct {
-	Name     string `json:"name"`
-	ImageUrl string `json:"image_url"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+	ImageUrl    string `json:"image_url"`
 }
 
 func (q *Queries) CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error) {
-	row := q.db.QueryRow(ctx, createTag, arg.Name, arg.ImageUrl)
+	row := q.db.QueryRow(ctx, createTag,
+		arg.Name,
+		arg.Slug,
+		arg.Description,
+		arg.ImageUrl,
+	)
 	var i Tag
 	err := row.Scan(
 		&i.ID,
@@ -34,6 +45,8 @@ func (q *Queries) CreateTag(ctx context.Context, arg CreateTagParams) (Tag, erro
 		&i.ImageUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Slug,
+		&i.Description,
 	)
 	return i, err
 }
@@ -51,6 +64,8 @@ func (q *Queries) DeleteTag(ctx context.Context, id uuid.UUID) error {
 const getTag = `-- name: GetTag :one
 SELECT id
       ,name
+      ,slug
+      ,description
       ,image_url
       ,created_at
       ,updated_at
@@ -59,36 +74,24 @@ WHERE id = $1
 LIMIT 1
 `
 
-func (q *Queries) GetTag(ctx context.Context, id uuid.UUID) (Tag, error) {
-	row := q.db.QueryRow(ctx, getTag, id)
-	var i Tag
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.ImageUrl,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+type GetTagRow struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	ImageUrl    string    `json:"image_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
-const getTagByName = `-- name: GetTagByName :one
-SELECT id
-      ,name
-      ,image_url
-      ,created_at
-      ,updated_at
-FROM tags
-WHERE name = $1 
-LIMIT 1
-`
-
-func (q *Queries) GetTagByName(ctx context.Context, name string) (Tag, error) {
-	row := q.db.QueryRow(ctx, getTagByName, name)
-	var i Tag
+func (q *Queries) GetTag(ctx context.Context, id uuid.UUID) (GetTagRow, error) {
+	row := q.db.QueryRow(ctx, getTag, id)
+	var i GetTagRow
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
+		&i.Slug,
+		&i.Description,
 		&i.ImageUrl,
 		&i.CreatedAt,
 		&i.UpdatedAt,
@@ -99,24 +102,38 @@ func (q *Queries) GetTagByName(ctx context.Context, name string) (Tag, error) {
 const listTags = `-- name: ListTags :many
 SELECT id
       ,name
+      ,slug
+      ,description
       ,image_url
       ,created_at
       ,updated_at
 FROM tags
 `
 
-func (q *Queries) ListTags(ctx context.Context) ([]Tag, error) {
+type ListTagsRow struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	ImageUrl    string    `json:"image_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (q *Queries) ListTags(ctx context.Context) ([]ListTagsRow, error) {
 	rows, err := q.db.Query(ctx, listTags)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Tag{}
+	items := []ListTagsRow{}
 	for rows.Next() {
-		var i Tag
+		var i ListTagsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Name,
+			&i.Slug,
+			&i.Description,
 			&i.ImageUrl,
 			&i.CreatedAt,
 			&i.UpdatedAt,
@@ -130,3 +147,107 @@ func (q *Queries) ListTags(ctx context.Context) ([]Tag, error) {
 	}
 	return items, nil
 }
+
+const lookupTag = `-- name: LookupTag :one
+SELECT id
+      ,name
+      ,slug
+      ,description
+      ,image_url
+      ,created_at
+      ,updated_at
+FROM tags
+WHERE id = $1
+   OR slug = $2
+LIMIT 1
+`
+
+type LookupTagParams struct {
+	ID   pgtype.UUID `json:"id"`
+	Slug pgtype.Text `json:"slug"`
+}
+
+type LookupTagRow struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	ImageUrl    string    `json:"image_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (q *Queries) LookupTag(ctx context.Context, arg LookupTagParams) (LookupTagRow, error) {
+	row := q.db.QueryRow(ctx, lookupTag, arg.ID, arg.Slug)
+	var i LookupTagRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Slug,
+		&i.Description,
+		&i.ImageUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const tagSlugExists = `-- name: TagSlugExists :one
+SELECT EXISTS (
+  SELECT 1
+  FROM tags
+  WHERE slug = $1
+    AND id <> $2
+)
+`
+
+type TagSlugExistsParams struct {
+	Slug  string    `json:"slug"`
+	TagID uuid.UUID `json:"tag_id"`
+}
+
+func (q *Queries) TagSlugExists(ctx context.Context, arg TagSlugExistsParams) (bool, error) {
+	row := q.db.QueryRow(ctx, tagSlugExists, arg.Slug, arg.TagID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const updateTag = `-- name: UpdateTag :one
+UPDATE tags
+SET
+  name = COALESCE($1, name),
+  slug = COALESCE($2, slug),
+  description = COALESCE($3, description),
+  updated_at = NOW()
+WHERE
+  id = $4
+RETURNING id, name, image_url, created_at, updated_at, slug, description
+`
+
+type UpdateTagParams struct {
+	Name        pgtype.Text `json:"name"`
+	Slug        pgtype.Text `json:"slug"`
+	Description pgtype.Text `json:"description"`
+	ID          uuid.UUID   `json:"id"`
+}
+
+func (q *Queries) UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, error) {
+	row := q.db.QueryRow(ctx, updateTag,
+		arg.Name,
+		arg.Slug,
+		arg.Description,
+		arg.ID,
+	)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.ImageUrl,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Slug,
+		&i.Description,
+	)
+	return i, err
+}
@@ -0,0 +1,282 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.22.0
+// source: totp.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countRecoveryCodes = `-- name: CountRecoveryCodes :one
+SELECT COUNT(*)
+FROM recovery_codes
+WHERE user_id = $1
+  AND used_at IS NULL
+`
+
+func (q *Queries) CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countRecoveryCodes, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createMFAChallenge = `-- name: CreateMFAChallenge :one
+INSERT INTO mfa_challenges (
+  user_id,
+  token_hash,
+  expires_at
+) VALUES (
+  $1, $2, $3
+) RETURNING id, user_id, token_hash, attempts, expires_at, created_at
+`
+
+type CreateMFAChallengeParams struct {
+	UserID    uuid.UUID `json:"user_id"`
+	TokenHash string    `json:"token_hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateMFAChallenge(ctx context.Context, arg CreateMFAChallengeParams) (MfaChallenge, error) {
+	row := q.db.QueryRow(ctx, createMFAChallenge, arg.UserID, arg.TokenHash, arg.ExpiresAt)
+	var i MfaChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TokenHash,
+		&i.Attempts,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createRecoveryCode = `-- name: CreateRecoveryCode :exec
+INSERT INTO recovery_codes (
+  user_id,
+  code_hash
+) VALUES (
+  $1, $2
+)
+`
+
+type CreateRecoveryCodeParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	CodeHash string    `json:"code_hash"`
+}
+
+func (q *Queries) CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error {
+	_, err := q.db.Exec(ctx, createRecoveryCode, arg.UserID, arg.CodeHash)
+	return err
+}
+
+const deleteExpiredMFAChallenges = `-- name: DeleteExpiredMFAChallenges :execrows
+DELETE FROM mfa_challenges
+WHERE expires_at <= NOW()
+`
+
+func (q *Queries) DeleteExpiredMFAChallenges(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredMFAChallenges)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteMFAChallenge = `-- name: DeleteMFAChallenge :execrows
+DELETE FROM mfa_challenges
+WHERE id = $1
+`
+
+func (q *Queries) DeleteMFAChallenge(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteMFAChallenge, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteRecoveryCodes = `-- name: DeleteRecoveryCodes :exec
+DELETE FROM recovery_codes
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteRecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteRecoveryCodes, userID)
+	return err
+}
+
+const disableUserTOTP = `-- name: DisableUserTOTP :exec
+UPDATE users
+SET
+  totp_secret = NULL,
+  totp_enabled_at = NULL,
+  totp_last_used_step = 0,
+  updated_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) DisableUserTOTP(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, disableUserTOTP, id)
+	return err
+}
+
+const enableUserTOTP = `-- name: EnableUserTOTP :execrows
+UPDATE users
+SET
+  totp_enabled_at = NOW(),
+  totp_last_used_step = $2,
+  updated_at = NOW()
+WHERE id = $1
+  AND totp_secret IS NOT NULL
+  AND totp_enabled_at IS NULL
+`
+
+type EnableUserTOTPParams struct {
+	ID               uuid.UUID `json:"id"`
+	TotpLastUsedStep int64     `json:"totp_last_used_step"`
+}
+
+func (q *Queries) EnableUserTOTP(ctx context.Context, arg EnableUserTOTPParams) (int64, error) {
+	result, err := q.db.Exec(ctx, enableUserTOTP, arg.ID, arg.TotpLastUsedStep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getMFAChallengeByHash = `-- name: GetMFAChallengeByHash :one
+SELECT id, user_id, token_hash, attempts, expires_at, created_at FROM mfa_challenges
+WHERE token_hash = $1
+LIMIT 1
+`
+
+func (q *Queries) GetMFAChallengeByHash(ctx context.Context, tokenHash string) (MfaChallenge, error) {
+	row := q.db.QueryRow(ctx, getMFAChallengeByHash, tokenHash)
+	var i MfaChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TokenHash,
+		&i.Attempts,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserTOTP = `-- name: GetUserTOTP :one
+SELECT u.id
+      ,u.username
+      ,u.role
+      ,u.totp_secret
+      ,u.totp_enabled_at
+      ,u.totp_last_used_step
+FROM users AS u
+WHERE u.id = $1
+LIMIT 1
+`
+
+type GetUserTOTPRow struct {
+	ID               uuid.UUID          `json:"id"`
+	Username         string             `json:"username"`
+	Role             string             `json:"role"`
+	TotpSecret       pgtype.Text        `json:"totp_secret"`
+	TotpEnabledAt    pgtype.Timestamptz `json:"totp_enabled_at"`
+	TotpLastUsedStep int64              `json:"totp_last_used_step"`
+}
+
+func (q *Queries) GetUserTOTP(ctx context.Context, id uuid.UUID) (GetUserTOTPRow, error) {
+	row := q.db.QueryRow(ctx, getUserTOTP, id)
+	var i GetUserTOTPRow
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastUsedStep,
+	)
+	return i, err
+}
+
+const incrementMFAChallengeAttempts = `-- name: IncrementMFAChallengeAttempts :exec
+UPDATE mfa_challenges
+SET attempts = attempts + 1
+WHERE id = $1
+`
+
+func (q *Queries) IncrementMFAChallengeAttempts(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, incrementMFAChallengeAttempts, id)
+	return err
+}
+
+const setUserTOTPSecret = `-- name: SetUserTOTPSecret :execrows
+UPDATE users
+SET
+  totp_secret = $2,
+  totp_last_used_step = 0,
+  updated_at = NOW()
+WHERE id = $1
+  AND totp_enabled_at IS NULL
+`
+
+type SetUserTOTPSecretParams struct {
+	ID         uuid.UUID   `json:"id"`
+	TotpSecret pgtype.Text `json:"totp_secret"`
+}
+
+func (q *Queries) SetUserTOTPSecret(ctx context.Context, arg SetUserTOTPSecretParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setUserTOTPSecret, arg.ID, arg.TotpSecret)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const useRecoveryCode = `-- name: UseRecoveryCode :execrows
+UPDATE recovery_codes
+SET used_at = NOW()
+WHERE user_id = $1
+  AND code_hash = $2
+  AND used_at IS NULL
+`
+
+type UseRecoveryCodeParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	CodeHash string    `json:"code_hash"`
+}
+
+func (q *Queries) UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useRecoveryCode, arg.UserID, arg.CodeHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const useTOTPStep = `-- name: UseTOTPStep :execrows
+UPDATE users
+SET totp_last_used_step = $2
+WHERE id = $1
+  AND totp_last_used_step < $2
+`
+
+type UseTOTPStepParams struct {
+	ID               uuid.UUID `json:"id"`
+	TotpLastUsedStep int64     `json:"totp_last_used_step"`
+}
+
+func (q *Queries) UseTOTPStep(ctx context.Context, arg UseTOTPStepParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useTOTPStep, arg.ID, arg.TotpLastUsedStep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// AcceptInviteTxParams contains the input parameters of the accept invite transaction
+type AcceptInviteTxParams struct {
+	InviteID uuid.UUID
+	User     CreateUserParams
+}
+
+// AcceptInviteTxResult is the result of the accept invite transaction
+type AcceptInviteTxResult struct {
+	User User
+}
+
+// AcceptInviteTx marks an invite as accepted and creates its user, the email is verified
+// because the invite was sent to it. It returns ErrRecordNotFound if the invite is not pending.
+func (store *SQLStore) AcceptInviteTx(ctx context.Context, arg AcceptInviteTxParams) (AcceptInviteTxResult, error) {
+	var result AcceptInviteTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		accepted, err := q.AcceptInvite(ctx, arg.InviteID)
+		if err != nil {
+			return err
+		}
+		if accepted == 0 {
+			return ErrRecordNotFound
+		}
+
+		user, err := q.CreateUser(ctx, arg.User)
+		if err != nil {
+			return err
+		}
+
+		result.User, err = q.VerifyUserEmail(ctx, VerifyUserEmailParams{
+			ID:    user.ID,
+			Email: user.Email,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ChangePasswordTxParams contains the input parameters of the change password transaction
+type ChangePasswordTxParams struct {
+	UserID         uuid.UUID
+	Username       string
+	HashedPassword string
+}
+
+// ChangePasswordTx changes the password of a user, drops its pending reset tokens and blocks every session
+func (store *SQLStore) ChangePasswordTx(ctx context.Context, arg ChangePasswordTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		_, err := q.UpdateUser(ctx, UpdateUserParams{
+			ID:       arg.UserID,
+			Password: pgtype.Text{String: arg.HashedPassword, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.DeletePasswordResetTokens(ctx, arg.UserID)
+		if err != nil {
+			return err
+		}
+
+		return q.BlockUserSessions(ctx, arg.Username)
+	})
+}
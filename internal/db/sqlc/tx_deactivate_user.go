@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DeactivateUserTxParams contains the input parameters of the deactivate user transaction
+type DeactivateUserTxParams struct {
+	UserID   uuid.UUID
+	Username string
+	// TransferTo receives the posts of the user, when it is not valid the posts are left without author
+	TransferTo pgtype.UUID
+}
+
+// DeactivateUserTx disables a user, blocks its sessions, revokes its personal access tokens,
+// transfers or anonymizes its posts and clears it as the last editor of the posts. It returns
+// ErrRecordNotFound if the user is already disabled and ErrLastAdmin if the user is the last active admin.
+func (store *SQLStore) DeactivateUserTx(ctx context.Context, arg DeactivateUserTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		err := ensureNotLastAdmin(ctx, q, arg.UserID)
+		if err != nil {
+			return err
+		}
+
+		disabled, err := q.DisableUser(ctx, arg.UserID)
+		if err != nil {
+			return err
+		}
+		if disabled == 0 {
+			return ErrRecordNotFound
+		}
+
+		err = q.BlockUserSessions(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		err = q.RevokeUserPersonalAccessTokens(ctx, arg.UserID)
+		if err != nil {
+			return err
+		}
+
+		from := pgtype.UUID{Bytes: arg.UserID, Valid: true}
+		err = q.TransferPostAuthor(ctx, TransferPostAuthorParams{
+			FromUserID: from,
+			ToUserID:   arg.TransferTo,
+		})
+		if err != nil {
+			return err
+		}
+
+		// The edits were made by the user, they are not attributed to the receiver of the posts
+		err = q.ClearPostEditor(ctx, from)
+		if err != nil {
+			return err
+		}
+
+		if arg.TransferTo.Valid {
+			err = q.TransferPostCoAuthors(ctx, TransferPostCoAuthorsParams{
+				FromUserID: arg.UserID,
+				ToUserID:   arg.TransferTo.Bytes,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return q.DeleteUserPostAuthors(ctx, arg.UserID)
+	})
+}
+
+// PurgeUserTxParams contains the input parameters of the purge user transaction
+type PurgeUserTxParams struct {
+	UserID   uuid.UUID
+	Username string
+}
+
+// PurgeUserTx deletes a disabled user with its sessions, the rest of its data is deleted by the
+// foreign keys. It returns ErrRecordNotFound if the user does not exist or is not disabled.
+func (store *SQLStore) PurgeUserTx(ctx context.Context, arg PurgeUserTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		err := q.DeleteUserSessions(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		deleted, err := q.DeleteDisabledUser(ctx, arg.UserID)
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return ErrRecordNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// CreatePostTxParams contains the input parameters of the create post transaction
+type CreatePostTxParams struct {
+	Post        CreatePostParams
+	CoAuthorIDs []uuid.UUID
+}
+
+// CreatePostTx creates a post and adds its co-authors,
+// a previous slug of another post with the same slug stops redirecting to it
+func (store *SQLStore) CreatePostTx(ctx context.Context, arg CreatePostTxParams) (Post, error) {
+	var post Post
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := q.DeletePostSlugHistory(ctx, arg.Post.Slug)
+		if err != nil {
+			return err
+		}
+
+		post, err = q.CreatePost(ctx, arg.Post)
+		if err != nil {
+			return err
+		}
+
+		return addPostAuthors(ctx, q, post.ID, arg.CoAuthorIDs)
+	})
+
+	return post, err
+}
+
+// UpdatePostTxParams contains the input parameters of the update post transaction,
+// the co-authors are only replaced when ReplaceCoAuthors is true
+type UpdatePostTxParams struct {
+	Post             UpdatePostParams
+	ReplaceCoAuthors bool
+	CoAuthorIDs      []uuid.UUID
+}
+
+// UpdatePostTx updates a post and replaces its co-authors,
+// when the slug changes the previous one is kept to redirect to the post
+func (store *SQLStore) UpdatePostTx(ctx context.Context, arg UpdatePostTxParams) (Post, error) {
+	var post Post
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		previousSlug, err := q.GetPostSlug(ctx, arg.Post.ID)
+		if err != nil {
+			return err
+		}
+
+		post, err = q.UpdatePost(ctx, arg.Post)
+		if err != nil {
+			return err
+		}
+
+		if post.Slug != previousSlug {
+			err = updatePostSlugHistory(ctx, q, post.ID, previousSlug, post.Slug)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !arg.ReplaceCoAuthors {
+			return nil
+		}
+
+		err = q.DeletePostAuthors(ctx, post.ID)
+		if err != nil {
+			return err
+		}
+
+		return addPostAuthors(ctx, q, post.ID, arg.CoAuthorIDs)
+	})
+
+	return post, err
+}
+
+// updatePostSlugHistory keeps the previous slug of a post, the new slug is removed
+// from the history in case it was used before by this or another post
+func updatePostSlugHistory(ctx context.Context, q *Queries, postID uuid.UUID, previousSlug string, slug string) error {
+	err := q.DeletePostSlugHistory(ctx, slug)
+	if err != nil {
+		return err
+	}
+
+	return q.AddPostSlugHistory(ctx, AddPostSlugHistoryParams{
+		Slug:   previousSlug,
+		PostID: postID,
+	})
+}
+
+func addPostAuthors(ctx context.Context, q *Queries, postID uuid.UUID, userIDs []uuid.UUID) error {
+	for _, userID := range userIDs {
+		err := q.AddPostAuthor(ctx, AddPostAuthorParams{
+			PostID: postID,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ProvisionOIDCUserTxParams contains the input parameters of the provision OIDC user transaction
+type ProvisionOIDCUserTxParams struct {
+	User        CreateUserParams
+	DisplayName pgtype.Text
+	Issuer      string
+	Subject     string
+}
+
+// ProvisionOIDCUserTx creates the user of an identity of an OpenID Connect provider and links them,
+// the email is verified because the provider already verified it
+func (store *SQLStore) ProvisionOIDCUserTx(ctx context.Context, arg ProvisionOIDCUserTxParams) (User, error) {
+	var result User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		user, err := q.CreateUser(ctx, arg.User)
+		if err != nil {
+			return err
+		}
+
+		result, err = q.VerifyUserEmail(ctx, VerifyUserEmailParams{
+			ID:    user.ID,
+			Email: user.Email,
+		})
+		if err != nil {
+			return err
+		}
+
+		if arg.DisplayName.Valid {
+			result, err = q.UpdateAuthorProfile(ctx, UpdateAuthorProfileParams{
+				ID:          user.ID,
+				DisplayName: arg.DisplayName,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = q.CreateUserIdentity(ctx, CreateUserIdentityParams{
+			UserID:  user.ID,
+			Issuer:  arg.Issuer,
+			Subject: arg.Subject,
+		})
+		return err
+	})
+
+	return result, err
+}
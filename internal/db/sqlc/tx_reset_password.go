@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ResetPasswordTxParams contains the input parameters of the reset password transaction
+type ResetPasswordTxParams struct {
+	TokenID        uuid.UUID
+	UserID         uuid.UUID
+	Username       string
+	HashedPassword string
+}
+
+// ResetPasswordTx uses a password reset token, changes the password of its user and blocks every session.
+// It returns ErrRecordNotFound if the token was already used or expired.
+func (store *SQLStore) ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		used, err := q.UsePasswordResetToken(ctx, arg.TokenID)
+		if err != nil {
+			return err
+		}
+		if used == 0 {
+			return ErrRecordNotFound
+		}
+
+		_, err = q.UpdateUser(ctx, UpdateUserParams{
+			ID:       arg.UserID,
+			Password: pgtype.Text{String: arg.HashedPassword, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.DeletePasswordResetTokens(ctx, arg.UserID)
+		if err != nil {
+			return err
+		}
+
+		return q.BlockUserSessions(ctx, arg.Username)
+	})
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// RotateSessionTxParams contains the input parameters of the rotate session transaction
+type RotateSessionTxParams struct {
+	SessionID  uuid.UUID
+	NewSession CreateSessionParams
+}
+
+// RotateSessionTxResult is the result of the rotate session transaction
+type RotateSessionTxResult struct {
+	Session Session
+}
+
+// RotateSessionTx marks a session as rotated and creates the session that replaces it.
+// It returns ErrRecordNotFound if the session was already rotated or blocked.
+func (store *SQLStore) RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (RotateSessionTxResult, error) {
+	var result RotateSessionTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		_, err = q.RotateSession(ctx, arg.SessionID)
+		if err != nil {
+			return err
+		}
+
+		result.Session, err = q.CreateSession(ctx, arg.NewSession)
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// EnableTOTPTxParams contains the input parameters of the enable TOTP transaction
+type EnableTOTPTxParams struct {
+	UserID             uuid.UUID
+	Step               int64
+	RecoveryCodeHashes []string
+}
+
+// EnableTOTPTx enables the pending TOTP secret of a user and replaces the recovery codes.
+// It returns ErrRecordNotFound if the user has no pending secret.
+func (store *SQLStore) EnableTOTPTx(ctx context.Context, arg EnableTOTPTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		enabled, err := q.EnableUserTOTP(ctx, EnableUserTOTPParams{
+			ID:               arg.UserID,
+			TotpLastUsedStep: arg.Step,
+		})
+		if err != nil {
+			return err
+		}
+		if enabled == 0 {
+			return ErrRecordNotFound
+		}
+
+		err = q.DeleteRecoveryCodes(ctx, arg.UserID)
+		if err != nil {
+			return err
+		}
+
+		for _, codeHash := range arg.RecoveryCodeHashes {
+			err = q.CreateRecoveryCode(ctx, CreateRecoveryCodeParams{
+				UserID:   arg.UserID,
+				CodeHash: codeHash,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DisableTOTPTx removes the TOTP secret of a user and its recovery codes
+func (store *SQLStore) DisableTOTPTx(ctx context.Context, userID uuid.UUID) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		err := q.DisableUserTOTP(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		return q.DeleteRecoveryCodes(ctx, userID)
+	})
+}
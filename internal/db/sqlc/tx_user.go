@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// adminRole is the role of the users that manage the blog, the same as util.AdminRole
+const adminRole = "admin"
+
+// ErrLastAdmin is returned when a change would leave the blog without an active admin
+var ErrLastAdmin = errors.New("the last admin can not be deleted or demoted")
+
+// ensureNotLastAdmin locks the active admins until the transaction ends and returns ErrLastAdmin
+// if the user is the only one, so concurrent demotions can not leave the blog without admins
+func ensureNotLastAdmin(ctx context.Context, q *Queries, userID uuid.UUID) error {
+	admins, err := q.LockActiveUsersByRole(ctx, adminRole)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range admins {
+		if id == userID && len(admins) == 1 {
+			return ErrLastAdmin
+		}
+	}
+	return nil
+}
+
+// UpdateUserTx updates a user, it returns ErrLastAdmin when it demotes the last active admin
+func (store *SQLStore) UpdateUserTx(ctx context.Context, arg UpdateUserParams) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if arg.Role.Valid && arg.Role.String != adminRole {
+			err := ensureNotLastAdmin(ctx, q, arg.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		var err error
+		user, err = q.UpdateUser(ctx, arg)
+		return err
+	})
+
+	return user, err
+}
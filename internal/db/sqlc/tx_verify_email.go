@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// VerifyEmailTxParams contains the input parameters of the verify email transaction
+type VerifyEmailTxParams struct {
+	VerificationID uuid.UUID
+	UserID         uuid.UUID
+	Email          string
+}
+
+// VerifyEmailTxResult is the result of the verify email transaction
+type VerifyEmailTxResult struct {
+	User User
+}
+
+// VerifyEmailTx uses an email verification and sets its address as the verified email of the user.
+// It returns ErrRecordNotFound if the verification was already used or expired.
+func (store *SQLStore) VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error) {
+	var result VerifyEmailTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		used, err := q.UseEmailVerification(ctx, arg.VerificationID)
+		if err != nil {
+			return err
+		}
+		if used == 0 {
+			return ErrRecordNotFound
+		}
+
+		result.User, err = q.VerifyUserEmail(ctx, VerifyUserEmailParams{
+			ID:    arg.UserID,
+			Email: arg.Email,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.DeleteEmailVerifications(ctx, arg.UserID)
+	})
+
+	return result, err
+}
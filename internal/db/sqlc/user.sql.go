@@ -17,20 +17,27 @@ const createUser = `-- name: CreateUser :one
 INSERT INTO users (
   username,
   email,
-  password
+  password,
+  role
 ) VALUES (
-  $1, $2, $3
-) RETURNING id, username, email, password, created_at, updated_at
+  $1, $2, $3, $4
+) RETURNING id, username, email, password, created_at, updated_at, role, totp_secret, totp_enabled_at, totp_last_used_step, email_verified_at, display_name, bio, website, social_links, avatar_url, disabled_at
 `
 
 type CreateUserParams struct {
 	Username string `json:"username"`
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	Role     string `json:"role"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRow(ctx, createUser, arg.Username, arg.Email, arg.Password)
+	row := q.db.QueryRow(ctx, createUser,
+		arg.Username,
+		arg.Email,
+		arg.Password,
+		arg.Role,
+	)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -39,18 +46,65 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastUsedStep,
+		&i.EmailVerifiedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Website,
+		&i.SocialLinks,
+		&i.AvatarUrl,
+		&i.DisabledAt,
 	)
 	return i, err
 }
 
-const deleteUser = `-- name: DeleteUser :exec
+const deleteDisabledUser = `-- name: DeleteDisabledUser :execrows
 DELETE FROM users
 WHERE id = $1
+  AND disabled_at IS NOT NULL
 `
 
-func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteUser, id)
-	return err
+func (q *Queries) DeleteDisabledUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteDisabledUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const disableUser = `-- name: DisableUser :execrows
+UPDATE users
+SET disabled_at = NOW()
+   ,updated_at = NOW()
+WHERE id = $1
+  AND disabled_at IS NULL
+`
+
+func (q *Queries) DisableUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, disableUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const enableUser = `-- name: EnableUser :execrows
+UPDATE users
+SET disabled_at = NULL
+   ,updated_at = NOW()
+WHERE id = $1
+  AND disabled_at IS NOT NULL
+`
+
+func (q *Queries) EnableUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, enableUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const getUser = `-- name: GetUser :one
@@ -60,14 +114,31 @@ SELECT u.id
       ,u.password 
       ,u.created_at
       ,u.updated_at
+      ,u.role
+      ,u.email_verified_at
+      ,u.disabled_at
+      ,u.totp_enabled_at
 FROM users as u
 WHERE u.id = $1 
 LIMIT 1
 `
 
-func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (User, error) {
+type GetUserRow struct {
+	ID              uuid.UUID          `json:"id"`
+	Username        string             `json:"username"`
+	Email           string             `json:"email"`
+	Password        string             `json:"password"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+	Role            string             `json:"role"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
+	DisabledAt      pgtype.Timestamptz `json:"disabled_at"`
+	TotpEnabledAt   pgtype.Timestamptz `json:"totp_enabled_at"`
+}
+
+func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (GetUserRow, error) {
 	row := q.db.QueryRow(ctx, getUser, id)
-	var i User
+	var i GetUserRow
 	err := row.Scan(
 		&i.ID,
 		&i.Username,
@@ -75,6 +146,42 @@ func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+		&i.EmailVerifiedAt,
+		&i.DisabledAt,
+		&i.TotpEnabledAt,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT u.id
+      ,u.username
+      ,u.email
+      ,u.email_verified_at
+      ,u.disabled_at
+FROM users as u
+WHERE u.email = $1
+LIMIT 1
+`
+
+type GetUserByEmailRow struct {
+	ID              uuid.UUID          `json:"id"`
+	Username        string             `json:"username"`
+	Email           string             `json:"email"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
+	DisabledAt      pgtype.Timestamptz `json:"disabled_at"`
+}
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEmailRow, error) {
+	row := q.db.QueryRow(ctx, getUserByEmail, email)
+	var i GetUserByEmailRow
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.EmailVerifiedAt,
+		&i.DisabledAt,
 	)
 	return i, err
 }
@@ -83,21 +190,40 @@ const getUserByUsername = `-- name: GetUserByUsername :one
 SELECT u.id
       ,u.username
       ,u.password
+      ,u.email
+      ,u.role
+      ,u.totp_enabled_at
+      ,u.email_verified_at
+      ,u.disabled_at
 FROM users as u
 WHERE u.username = $1 
 LIMIT 1
 `
 
 type GetUserByUsernameRow struct {
-	ID       uuid.UUID `json:"id"`
-	Username string    `json:"username"`
-	Password string    `json:"password"`
+	ID              uuid.UUID          `json:"id"`
+	Username        string             `json:"username"`
+	Password        string             `json:"password"`
+	Email           string             `json:"email"`
+	Role            string             `json:"role"`
+	TotpEnabledAt   pgtype.Timestamptz `json:"totp_enabled_at"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
+	DisabledAt      pgtype.Timestamptz `json:"disabled_at"`
 }
 
 func (q *Queries) GetUserByUsername(ctx context.Context, username string) (GetUserByUsernameRow, error) {
 	row := q.db.QueryRow(ctx, getUserByUsername, username)
 	var i GetUserByUsernameRow
-	err := row.Scan(&i.ID, &i.Username, &i.Password)
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Password,
+		&i.Email,
+		&i.Role,
+		&i.TotpEnabledAt,
+		&i.EmailVerifiedAt,
+		&i.DisabledAt,
+	)
 	return i, err
 }
 
@@ -105,15 +231,21 @@ const listUsers = `-- name: ListUsers :many
 SELECT u.id
       ,u.username
       ,u.email
+      ,u.role
+      ,u.email_verified_at
+      ,u.disabled_at
       ,u.created_at
 FROM users as u
 `
 
 type ListUsersRow struct {
-	ID        uuid.UUID `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
+	ID              uuid.UUID          `json:"id"`
+	Username        string             `json:"username"`
+	Email           string             `json:"email"`
+	Role            string             `json:"role"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
+	DisabledAt      pgtype.Timestamptz `json:"disabled_at"`
+	CreatedAt       time.Time          `json:"created_at"`
 }
 
 func (q *Queries) ListUsers(ctx context.Context) ([]ListUsersRow, error) {
@@ -129,6 +261,9 @@ func (q *Queries) ListUsers(ctx context.Context) ([]ListUsersRow, error) {
 			&i.ID,
 			&i.Username,
 			&i.Email,
+			&i.Role,
+			&i.EmailVerifiedAt,
+			&i.DisabledAt,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -141,22 +276,75 @@ func (q *Queries) ListUsers(ctx context.Context) ([]ListUsersRow, error) {
 	return items, nil
 }
 
+const lockActiveUsersByRole = `-- name: LockActiveUsersByRole :many
+SELECT id
+FROM users
+WHERE role = $1
+  AND disabled_at IS NULL
+ORDER BY id
+FOR UPDATE
+`
+
+func (q *Queries) LockActiveUsersByRole(ctx context.Context, role string) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, lockActiveUsersByRole, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rehashUserPassword = `-- name: RehashUserPassword :execrows
+UPDATE users
+SET password = $1
+   ,updated_at = NOW()
+WHERE id = $2
+  AND password = $3
+`
+
+type RehashUserPasswordParams struct {
+	NewPassword string    `json:"new_password"`
+	ID          uuid.UUID `json:"id"`
+	OldPassword string    `json:"old_password"`
+}
+
+func (q *Queries) RehashUserPassword(ctx context.Context, arg RehashUserPasswordParams) (int64, error) {
+	result, err := q.db.Exec(ctx, rehashUserPassword, arg.NewPassword, arg.ID, arg.OldPassword)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
 SET
   password = COALESCE($1, password),
   updated_at = NOW(),
   username = COALESCE($2, username),
-  email = COALESCE($3, email)
+  email = COALESCE($3, email),
+  role = COALESCE($4, role)
 WHERE
-  id = $4
-RETURNING id, username, email, password, created_at, updated_at
+  id = $5
+RETURNING id, username, email, password, created_at, updated_at, role, totp_secret, totp_enabled_at, totp_last_used_step, email_verified_at, display_name, bio, website, social_links, avatar_url, disabled_at
 `
 
 type UpdateUserParams struct {
 	Password pgtype.Text `json:"password"`
 	Username pgtype.Text `json:"username"`
 	Email    pgtype.Text `json:"email"`
+	Role     pgtype.Text `json:"role"`
 	ID       uuid.UUID   `json:"id"`
 }
 
@@ -165,6 +353,7 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		arg.Password,
 		arg.Username,
 		arg.Email,
+		arg.Role,
 		arg.ID,
 	)
 	var i User
@@ -175,6 +364,17 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Password,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabledAt,
+		&i.TotpLastUsedStep,
+		&i.EmailVerifiedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Website,
+		&i.SocialLinks,
+		&i.AvatarUrl,
+		&i.DisabledAt,
 	)
 	return i, err
 }
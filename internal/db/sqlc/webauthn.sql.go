@@ -0,0 +1,220 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.22.0
+// source: webauthn.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const consumeWebAuthnSession = `-- name: ConsumeWebAuthnSession :one
+DELETE FROM webauthn_sessions
+WHERE id = $1
+  AND ceremony = $2
+RETURNING id, user_id, ceremony, session_data, expires_at, created_at
+`
+
+type ConsumeWebAuthnSessionParams struct {
+	ID       uuid.UUID `json:"id"`
+	Ceremony string    `json:"ceremony"`
+}
+
+func (q *Queries) ConsumeWebAuthnSession(ctx context.Context, arg ConsumeWebAuthnSessionParams) (WebauthnSession, error) {
+	row := q.db.QueryRow(ctx, consumeWebAuthnSession, arg.ID, arg.Ceremony)
+	var i WebauthnSession
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Ceremony,
+		&i.SessionData,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createWebAuthnCredential = `-- name: CreateWebAuthnCredential :one
+INSERT INTO webauthn_credentials (
+  user_id,
+  name,
+  credential_id,
+  credential
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, user_id, name, credential_id, credential, last_used_at, created_at
+`
+
+type CreateWebAuthnCredentialParams struct {
+	UserID       uuid.UUID `json:"user_id"`
+	Name         string    `json:"name"`
+	CredentialID []byte    `json:"credential_id"`
+	Credential   []byte    `json:"credential"`
+}
+
+func (q *Queries) CreateWebAuthnCredential(ctx context.Context, arg CreateWebAuthnCredentialParams) (WebauthnCredential, error) {
+	row := q.db.QueryRow(ctx, createWebAuthnCredential,
+		arg.UserID,
+		arg.Name,
+		arg.CredentialID,
+		arg.Credential,
+	)
+	var i WebauthnCredential
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CredentialID,
+		&i.Credential,
+		&i.LastUsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createWebAuthnSession = `-- name: CreateWebAuthnSession :one
+INSERT INTO webauthn_sessions (
+  user_id,
+  ceremony,
+  session_data,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4
+) RETURNING id, user_id, ceremony, session_data, expires_at, created_at
+`
+
+type CreateWebAuthnSessionParams struct {
+	UserID      pgtype.UUID `json:"user_id"`
+	Ceremony    string      `json:"ceremony"`
+	SessionData []byte      `json:"session_data"`
+	ExpiresAt   time.Time   `json:"expires_at"`
+}
+
+func (q *Queries) CreateWebAuthnSession(ctx context.Context, arg CreateWebAuthnSessionParams) (WebauthnSession, error) {
+	row := q.db.QueryRow(ctx, createWebAuthnSession,
+		arg.UserID,
+		arg.Ceremony,
+		arg.SessionData,
+		arg.ExpiresAt,
+	)
+	var i WebauthnSession
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Ceremony,
+		&i.SessionData,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteExpiredWebAuthnSessions = `-- name: DeleteExpiredWebAuthnSessions :execrows
+DELETE FROM webauthn_sessions
+WHERE expires_at <= NOW()
+`
+
+func (q *Queries) DeleteExpiredWebAuthnSessions(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredWebAuthnSessions)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteWebAuthnCredential = `-- name: DeleteWebAuthnCredential :execrows
+DELETE FROM webauthn_credentials
+WHERE id = $1
+  AND user_id = $2
+`
+
+type DeleteWebAuthnCredentialParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteWebAuthnCredential(ctx context.Context, arg DeleteWebAuthnCredentialParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteWebAuthnCredential, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getWebAuthnCredentialByCredentialID = `-- name: GetWebAuthnCredentialByCredentialID :one
+SELECT id, user_id, name, credential_id, credential, last_used_at, created_at FROM webauthn_credentials
+WHERE credential_id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetWebAuthnCredentialByCredentialID(ctx context.Context, credentialID []byte) (WebauthnCredential, error) {
+	row := q.db.QueryRow(ctx, getWebAuthnCredentialByCredentialID, credentialID)
+	var i WebauthnCredential
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CredentialID,
+		&i.Credential,
+		&i.LastUsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listWebAuthnCredentials = `-- name: ListWebAuthnCredentials :many
+SELECT id, user_id, name, credential_id, credential, last_used_at, created_at FROM webauthn_credentials
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListWebAuthnCredentials(ctx context.Context, userID uuid.UUID) ([]WebauthnCredential, error) {
+	rows, err := q.db.Query(ctx, listWebAuthnCredentials, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []WebauthnCredential{}
+	for rows.Next() {
+		var i WebauthnCredential
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.CredentialID,
+			&i.Credential,
+			&i.LastUsedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateWebAuthnCredentialUse = `-- name: UpdateWebAuthnCredentialUse :exec
+UPDATE webauthn_credentials
+SET
+  credential = $2,
+  last_used_at = NOW()
+WHERE id = $1
+`
+
+type UpdateWebAuthnCredentialUseParams struct {
+	ID         uuid.UUID `json:"id"`
+	Credential []byte    `json:"credential"`
+}
+
+func (q *Queries) UpdateWebAuthnCredentialUse(ctx context.Context, arg UpdateWebAuthnCredentialUseParams) error {
+	_, err := q.db.Exec(ctx, updateWebAuthnCredentialUse, arg.ID, arg.Credential)
+	return err
+}
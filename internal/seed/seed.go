@@ -24,8 +24,15 @@ func New(config util.Config, store *db.Queries) (*Initial, error) {
 }
 
 func (initial *Initial) Run() error {
-	//create users admin
-	adminUser := createInitialUser(initial, "jairoAdmin")
+	//create users admin, the rest of the users are onboarded with invites
+	var username string
+	fmt.Println("Enter the admin username:")
+	_, err := fmt.Scanln(&username)
+	if err != nil {
+		log.Fatalln("Can not read username:", err)
+	}
+
+	adminUser := createInitialUser(initial, username)
 	fmt.Println("user creared", adminUser.Username)
 
 	return nil
@@ -48,7 +55,21 @@ func createInitialUser(initial *Initial, userName string) db.User {
 		log.Fatalln("Can not read email:", err)
 	}
 
-	hashedPassword, err := util.HashPassword(passwordS)
+	policy, err := util.NewPasswordPolicy(initial.config.PasswordMinLength, initial.config.PasswordDenylist)
+	if err != nil {
+		log.Fatalln("Can not load password policy:", err)
+	}
+	err = policy.Validate(passwordS)
+	if err != nil {
+		log.Fatalln("Invalid password:", err)
+	}
+
+	hasher, err := util.NewPasswordHasher(initial.config.Argon2Params())
+	if err != nil {
+		log.Fatalln("Can not create password hasher:", err)
+	}
+
+	hashedPassword, err := hasher.Hash(passwordS)
 	if err != nil {
 		log.Fatalln("Error hashed passwoed:", err)
 	}
@@ -57,12 +78,22 @@ func createInitialUser(initial *Initial, userName string) db.User {
 		Username: userName,
 		Email:    email,
 		Password: hashedPassword,
+		Role:     util.AdminRole,
 	}
 
 	user, err := initial.store.CreateUser(ctx, newUserParams)
 	if err != nil {
 		log.Fatalln("Can not create user:", err)
 	}
+
+	// The email was typed by whoever runs the seed, there is no need to confirm it
+	user, err = initial.store.VerifyUserEmail(ctx, db.VerifyUserEmailParams{
+		ID:    user.ID,
+		Email: user.Email,
+	})
+	if err != nil {
+		log.Fatalln("Can not verify user email:", err)
+	}
 	log.Println("Created user:", user.Username)
 
 	return user
@@ -0,0 +1,25 @@
+package mail
+
+import (
+	"context"
+	"log"
+)
+
+// LogMailer writes the messages to a log instead of sending them, it is only meant for
+// development and tests, where there is no mail server
+type LogMailer struct {
+	logger *log.Logger
+}
+
+// NewLogMailer creates a mailer that writes to the logger, or to the standard logger when it is nil
+func NewLogMailer(logger *log.Logger) *LogMailer {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &LogMailer{logger: logger}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	m.logger.Printf("email not sent to %s, subject: %s\n%s\n", msg.To, msg.Subject, msg.Body)
+	return nil
+}
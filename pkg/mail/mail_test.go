@@ -0,0 +1,251 @@
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"log"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type smtpSecurity int
+
+const (
+	smtpPlain smtpSecurity = iota
+	smtpSTARTTLS
+	smtpImplicitTLS
+)
+
+// testCertificate creates a self-signed certificate for 127.0.0.1 and the pool that trusts it
+func testCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool
+}
+
+// fakeSMTPServer accepts one message without authentication and returns its data and whether
+// it was received over TLS, the mailer is configured to trust the certificate of the server
+func fakeSMTPServer(t *testing.T, security smtpSecurity) (SMTPConfig, func(*SMTPMailer), <-chan string) {
+	cert, pool := testCertificate(t)
+	tlsConf := &tls.Config{Certificates: []tls.Certificate{cert}}
+
+	var listener net.Listener
+	var err error
+	if security == smtpImplicitTLS {
+		listener, err = tls.Listen("tcp", "127.0.0.1:0", tlsConf)
+	} else {
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+	}
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { conn.Close() }()
+
+		_, encrypted := conn.(*tls.Conn)
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+		var data strings.Builder
+		inData := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					if !encrypted {
+						data.WriteString("X-Plain-Text: true\r\n")
+					}
+					received <- data.String()
+					reply("250 OK")
+					continue
+				}
+				data.WriteString(line)
+				continue
+			}
+
+			switch command := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(command, "EHLO"):
+				if security == smtpSTARTTLS && !encrypted {
+					reply("250-localhost")
+					reply("250 STARTTLS")
+					continue
+				}
+				reply("250 localhost")
+			case command == "STARTTLS":
+				reply("220 ready to start TLS")
+				tlsConn := tls.Server(conn, tlsConf)
+				if err := tlsConn.Handshake(); err != nil {
+					return
+				}
+				conn = tlsConn
+				encrypted = true
+				reader = bufio.NewReader(conn)
+			case command == "DATA":
+				inData = true
+				reply("354 go ahead")
+			case command == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	portNumber, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	trust := func(mailer *SMTPMailer) {
+		mailer.tlsConf.RootCAs = pool
+		mailer.implicitTLS = security == smtpImplicitTLS
+	}
+	return SMTPConfig{Host: host, Port: portNumber, From: "blog@example.com"}, trust, received
+}
+
+func sendTestMessage(t *testing.T, cnf SMTPConfig, trust func(*SMTPMailer)) error {
+	mailer, err := NewSMTPMailer(cnf)
+	require.NoError(t, err)
+	trust(mailer.(*SMTPMailer))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return mailer.Send(ctx, Message{
+		To:      "user@example.com",
+		Subject: "Reset your password",
+		Body:    "Hola, ¿olvidaste tu contraseña?",
+	})
+}
+
+func TestSMTPMailer(t *testing.T) {
+	cnf, trust, received := fakeSMTPServer(t, smtpSTARTTLS)
+
+	err := sendTestMessage(t, cnf, trust)
+	require.NoError(t, err)
+
+	data := <-received
+	require.NotContains(t, data, "X-Plain-Text")
+	require.Contains(t, data, "From: blog@example.com\r\n")
+	require.Contains(t, data, "To: user@example.com\r\n")
+	require.Contains(t, data, "Subject: Reset your password\r\n")
+	require.Contains(t, data, "Content-Type: text/plain; charset=utf-8\r\n")
+	require.Contains(t, data, "=C2=BFolvidaste tu contrase=C3=B1a?")
+}
+
+func TestSMTPMailerImplicitTLS(t *testing.T) {
+	cnf, trust, received := fakeSMTPServer(t, smtpImplicitTLS)
+
+	err := sendTestMessage(t, cnf, trust)
+	require.NoError(t, err)
+
+	data := <-received
+	require.NotContains(t, data, "X-Plain-Text")
+	require.Contains(t, data, "To: user@example.com\r\n")
+}
+
+func TestSMTPMailerRequiresSTARTTLS(t *testing.T) {
+	cnf, trust, received := fakeSMTPServer(t, smtpPlain)
+
+	err := sendTestMessage(t, cnf, trust)
+	require.ErrorIs(t, err, errSTARTTLSNotOffered)
+	require.Empty(t, received)
+}
+
+func TestSMTPMailerAllowInsecure(t *testing.T) {
+	cnf, trust, received := fakeSMTPServer(t, smtpPlain)
+	cnf.AllowInsecure = true
+
+	err := sendTestMessage(t, cnf, trust)
+	require.NoError(t, err)
+
+	data := <-received
+	require.Contains(t, data, "X-Plain-Text: true\r\n")
+	require.Contains(t, data, "To: user@example.com\r\n")
+}
+
+func TestSMTPMailerPorts(t *testing.T) {
+	mailer, err := NewSMTPMailer(SMTPConfig{Host: "smtp.example.com", From: "blog@example.com"})
+	require.NoError(t, err)
+	require.Equal(t, "smtp.example.com:587", mailer.(*SMTPMailer).addr)
+	require.False(t, mailer.(*SMTPMailer).implicitTLS)
+
+	mailer, err = NewSMTPMailer(SMTPConfig{Host: "smtp.example.com", Port: 465, From: "blog@example.com"})
+	require.NoError(t, err)
+	require.True(t, mailer.(*SMTPMailer).implicitTLS)
+}
+
+func TestSMTPMailerHeaderInjection(t *testing.T) {
+	mailer, err := NewSMTPMailer(SMTPConfig{Host: "localhost", From: "blog@example.com"})
+	require.NoError(t, err)
+
+	err = mailer.Send(context.Background(), Message{
+		To:      "user@example.com\r\nBcc: other@example.com",
+		Subject: "Hello",
+	})
+	require.ErrorIs(t, err, errHeaderInjection)
+}
+
+func TestMemoryMailer(t *testing.T) {
+	mailer := NewMemoryMailer()
+
+	msg := Message{To: "user@example.com", Subject: "Hello", Body: "Body"}
+	err := mailer.Send(context.Background(), msg)
+	require.NoError(t, err)
+
+	messages := mailer.Messages()
+	require.Len(t, messages, 1)
+	require.Equal(t, msg, messages[0])
+}
+
+func TestLogMailer(t *testing.T) {
+	var output bytes.Buffer
+	mailer := NewLogMailer(log.New(&output, "", 0))
+
+	err := mailer.Send(context.Background(), Message{To: "user@example.com", Subject: "Hello", Body: "Body"})
+	require.NoError(t, err)
+	require.Equal(t, "email not sent to user@example.com, subject: Hello\nBody\n", output.String())
+}
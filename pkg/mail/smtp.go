@@ -0,0 +1,175 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// smtpsPort is the port of SMTP over implicit TLS, the connection is encrypted before the greeting
+const smtpsPort = 465
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	// AllowInsecure sends the messages in plain text when the server does not offer STARTTLS,
+	// only meant for local relays in development
+	AllowInsecure bool
+}
+
+type SMTPMailer struct {
+	addr          string
+	host          string
+	auth          smtp.Auth
+	from          string
+	tlsConf       *tls.Config
+	dialer        net.Dialer
+	implicitTLS   bool
+	allowInsecure bool
+}
+
+var (
+	errHeaderInjection    = errors.New("mail headers can not contain line breaks")
+	errSTARTTLSNotOffered = errors.New("the SMTP server does not offer STARTTLS, set SMTP_ALLOW_INSECURE to send without TLS")
+)
+
+func NewSMTPMailer(cnf SMTPConfig) (Mailer, error) {
+	if len(cnf.Host) == 0 || len(cnf.From) == 0 {
+		return nil, errors.New("the SMTP host and the from address are required")
+	}
+
+	port := cnf.Port
+	if port == 0 {
+		port = 587
+	}
+
+	mailer := &SMTPMailer{
+		addr:          net.JoinHostPort(cnf.Host, fmt.Sprint(port)),
+		host:          cnf.Host,
+		from:          cnf.From,
+		tlsConf:       &tls.Config{ServerName: cnf.Host},
+		dialer:        net.Dialer{Timeout: 10 * time.Second},
+		implicitTLS:   port == smtpsPort,
+		allowInsecure: cnf.AllowInsecure,
+	}
+	if len(cnf.Username) > 0 {
+		mailer.auth = smtp.PlainAuth("", cnf.Username, cnf.Password, cnf.Host)
+	}
+
+	return mailer, nil
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	data, err := m.buildMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	conn, err := m.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot connect to the SMTP server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if !m.implicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(m.tlsConf); err != nil {
+				return err
+			}
+		} else if !m.allowInsecure {
+			return errSTARTTLSNotOffered
+		}
+	}
+
+	if m.auth != nil {
+		if err := client.Auth(m.auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(m.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// dial connects to the SMTP server, the connection to the implicit TLS port is encrypted from the start
+func (m *SMTPMailer) dial(ctx context.Context) (net.Conn, error) {
+	if m.implicitTLS {
+		dialer := tls.Dialer{NetDialer: &m.dialer, Config: m.tlsConf}
+		return dialer.DialContext(ctx, "tcp", m.addr)
+	}
+	return m.dialer.DialContext(ctx, "tcp", m.addr)
+}
+
+// buildMessage formats the message with its headers and a quoted-printable UTF-8 body
+func (m *SMTPMailer) buildMessage(msg Message) ([]byte, error) {
+	for _, header := range []string{msg.To, msg.Subject} {
+		if strings.ContainsAny(header, "\r\n") {
+			return nil, errHeaderInjection
+		}
+	}
+
+	messageID := make([]byte, 16)
+	if _, err := rand.Read(messageID); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", m.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(messageID), m.host)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	buf.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(msg.Body)); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
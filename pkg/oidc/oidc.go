@@ -0,0 +1,154 @@
+// Package oidc signs users in with an OpenID Connect provider using the authorization code flow with PKCE
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// LoginTimeout is how long a login started with BeginLogin can be finished
+const LoginTimeout = 10 * time.Minute
+
+var (
+	ErrMissingIDToken = errors.New("the provider did not return an id token")
+	ErrNonceMismatch  = errors.New("the id token nonce does not match the login")
+	ErrMissingEmail   = errors.New("the id token has no email")
+)
+
+// Config contains the client registration on the provider
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+// Provider is an OpenID Connect provider discovered from its issuer URL
+type Provider struct {
+	issuer   string
+	oauth2   oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+// New discovers the endpoints and the signing keys of the provider
+func New(ctx context.Context, config Config) (*Provider, error) {
+	provider, err := gooidc.NewProvider(ctx, config.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("cannot discover the OIDC provider: %w", err)
+	}
+
+	return &Provider{
+		issuer: config.IssuerURL,
+		oauth2: oauth2.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			Endpoint:     provider.Endpoint(),
+			RedirectURL:  config.RedirectURL,
+			Scopes:       []string{gooidc.ScopeOpenID, "email", "profile"},
+		},
+		verifier: provider.Verifier(&gooidc.Config{ClientID: config.ClientID}),
+	}, nil
+}
+
+// Issuer returns the issuer URL of the provider, the subjects are only unique within it
+func (provider *Provider) Issuer() string {
+	return provider.issuer
+}
+
+// AuthRequest is a login sent to the provider, State, Nonce and CodeVerifier
+// must be kept by the server until the provider redirects back
+type AuthRequest struct {
+	URL          string
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+// BeginLogin returns the URL of the provider where the user authenticates
+func (provider *Provider) BeginLogin() (AuthRequest, error) {
+	state, err := randomValue()
+	if err != nil {
+		return AuthRequest{}, err
+	}
+
+	nonce, err := randomValue()
+	if err != nil {
+		return AuthRequest{}, err
+	}
+
+	codeVerifier := oauth2.GenerateVerifier()
+
+	return AuthRequest{
+		URL:          provider.oauth2.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier)),
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+	}, nil
+}
+
+// Identity is the user authenticated by the provider
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// FinishLogin exchanges the authorization code, verifies the id token and returns the identity in it
+func (provider *Provider) FinishLogin(ctx context.Context, code string, codeVerifier string, nonce string) (Identity, error) {
+	token, err := provider.oauth2.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return Identity{}, fmt.Errorf("cannot exchange the authorization code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return Identity{}, ErrMissingIDToken
+	}
+
+	idToken, err := provider.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return Identity{}, fmt.Errorf("invalid id token: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(nonce)) != 1 {
+		return Identity{}, ErrNonceMismatch
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	err = idToken.Claims(&claims)
+	if err != nil {
+		return Identity{}, fmt.Errorf("invalid id token claims: %w", err)
+	}
+
+	if len(claims.Email) == 0 {
+		return Identity{}, ErrMissingEmail
+	}
+
+	return Identity{
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
+
+func randomValue() (string, error) {
+	value := make([]byte, 32)
+	if _, err := rand.Read(value); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(value), nil
+}
@@ -0,0 +1,270 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testClientID     = "blog"
+	testClientSecret = "secret"
+	testRedirectURL  = "http://localhost:3000/login/callback"
+	testKeyID        = "test-key"
+)
+
+// mockProvider is a minimal OpenID Connect provider: the test plays the user on the
+// authorization endpoint by calling authorize, and the client uses the token endpoint
+type mockProvider struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]mockAuthorization
+}
+
+type mockAuthorization struct {
+	challenge string
+	nonce     string
+	claims    map[string]any
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	mock := &mockProvider{t: t, key: key, codes: map[string]mockAuthorization{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", mock.discovery)
+	mux.HandleFunc("/keys", mock.keys)
+	mux.HandleFunc("/token", mock.token)
+	mock.server = httptest.NewServer(mux)
+	t.Cleanup(mock.server.Close)
+
+	return mock
+}
+
+func (mock *mockProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]any{
+		"issuer":                                mock.server.URL,
+		"authorization_endpoint":                mock.server.URL + "/authorize",
+		"token_endpoint":                        mock.server.URL + "/token",
+		"jwks_uri":                              mock.server.URL + "/keys",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (mock *mockProvider) keys(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+		Key:       &mock.key.PublicKey,
+		KeyID:     testKeyID,
+		Algorithm: string(jose.RS256),
+		Use:       "sig",
+	}}})
+}
+
+// authorize checks the login URL and returns the code the provider would redirect back with
+func (mock *mockProvider) authorize(authURL string, claims map[string]any) (code string, state string) {
+	parsed, err := url.Parse(authURL)
+	require.NoError(mock.t, err)
+	query := parsed.Query()
+
+	require.Equal(mock.t, mock.server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	require.Equal(mock.t, "code", query.Get("response_type"))
+	require.Equal(mock.t, testClientID, query.Get("client_id"))
+	require.Equal(mock.t, testRedirectURL, query.Get("redirect_uri"))
+	require.Equal(mock.t, "S256", query.Get("code_challenge_method"))
+	require.NotEmpty(mock.t, query.Get("code_challenge"))
+	require.NotEmpty(mock.t, query.Get("nonce"))
+
+	code = base64.RawURLEncoding.EncodeToString([]byte(time.Now().String()))
+	mock.mu.Lock()
+	mock.codes[code] = mockAuthorization{
+		challenge: query.Get("code_challenge"),
+		nonce:     query.Get("nonce"),
+		claims:    claims,
+	}
+	mock.mu.Unlock()
+
+	return code, query.Get("state")
+}
+
+func (mock *mockProvider) token(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok || clientID != testClientID || clientSecret != testClientSecret {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+
+	mock.mu.Lock()
+	authorization, ok := mock.codes[r.PostFormValue("code")]
+	delete(mock.codes, r.PostFormValue("code"))
+	mock.mu.Unlock()
+
+	if !ok || r.PostFormValue("grant_type") != "authorization_code" {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	challenge := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(challenge[:]) != authorization.challenge {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	claims := map[string]any{
+		"iss":   mock.server.URL,
+		"aud":   testClientID,
+		"sub":   "user-1",
+		"nonce": authorization.nonce,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+	}
+	for name, value := range authorization.claims {
+		claims[name] = value
+	}
+
+	writeJSON(w, map[string]any{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"expires_in":   60,
+		"id_token":     mock.sign(claims),
+	})
+}
+
+func (mock *mockProvider) sign(claims map[string]any) string {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: mock.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", testKeyID),
+	)
+	require.NoError(mock.t, err)
+
+	payload, err := json.Marshal(claims)
+	require.NoError(mock.t, err)
+
+	signature, err := signer.Sign(payload)
+	require.NoError(mock.t, err)
+
+	token, err := signature.CompactSerialize()
+	require.NoError(mock.t, err)
+	return token
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
+
+func newTestProvider(t *testing.T, mock *mockProvider) *Provider {
+	provider, err := New(context.Background(), Config{
+		IssuerURL:    mock.server.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+	})
+	require.NoError(t, err)
+	require.Equal(t, mock.server.URL, provider.Issuer())
+	return provider
+}
+
+func TestLogin(t *testing.T) {
+	mock := newMockProvider(t)
+	provider := newTestProvider(t, mock)
+
+	request, err := provider.BeginLogin()
+	require.NoError(t, err)
+	require.NotEmpty(t, request.State)
+	require.NotEmpty(t, request.Nonce)
+	require.NotEmpty(t, request.CodeVerifier)
+
+	code, state := mock.authorize(request.URL, map[string]any{
+		"email":          "jane@example.com",
+		"email_verified": true,
+		"name":           "Jane Doe",
+	})
+	require.Equal(t, request.State, state)
+
+	identity, err := provider.FinishLogin(context.Background(), code, request.CodeVerifier, request.Nonce)
+	require.NoError(t, err)
+	require.Equal(t, Identity{
+		Subject:       "user-1",
+		Email:         "jane@example.com",
+		EmailVerified: true,
+		Name:          "Jane Doe",
+	}, identity)
+
+	// The code can only be used once
+	_, err = provider.FinishLogin(context.Background(), code, request.CodeVerifier, request.Nonce)
+	require.Error(t, err)
+}
+
+func TestLoginWrongCodeVerifier(t *testing.T) {
+	mock := newMockProvider(t)
+	provider := newTestProvider(t, mock)
+
+	request, err := provider.BeginLogin()
+	require.NoError(t, err)
+
+	other, err := provider.BeginLogin()
+	require.NoError(t, err)
+
+	code, _ := mock.authorize(request.URL, map[string]any{"email": "jane@example.com"})
+	_, err = provider.FinishLogin(context.Background(), code, other.CodeVerifier, request.Nonce)
+	require.Error(t, err)
+}
+
+func TestLoginWrongNonce(t *testing.T) {
+	mock := newMockProvider(t)
+	provider := newTestProvider(t, mock)
+
+	request, err := provider.BeginLogin()
+	require.NoError(t, err)
+
+	code, _ := mock.authorize(request.URL, map[string]any{"email": "jane@example.com"})
+	_, err = provider.FinishLogin(context.Background(), code, request.CodeVerifier, "other nonce")
+	require.ErrorIs(t, err, ErrNonceMismatch)
+}
+
+func TestLoginInvalidIDToken(t *testing.T) {
+	mock := newMockProvider(t)
+	provider := newTestProvider(t, mock)
+
+	testCases := []struct {
+		name   string
+		claims map[string]any
+		err    error
+	}{
+		{name: "OtherAudience", claims: map[string]any{"email": "jane@example.com", "aud": "other"}},
+		{name: "OtherIssuer", claims: map[string]any{"email": "jane@example.com", "iss": "https://other.example.com"}},
+		{name: "Expired", claims: map[string]any{"email": "jane@example.com", "exp": time.Now().Add(-time.Minute).Unix()}},
+		{name: "NoEmail", claims: map[string]any{}, err: ErrMissingEmail},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := provider.BeginLogin()
+			require.NoError(t, err)
+
+			code, _ := mock.authorize(request.URL, tc.claims)
+			_, err = provider.FinishLogin(context.Background(), code, request.CodeVerifier, request.Nonce)
+			require.Error(t, err)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			}
+		})
+	}
+}
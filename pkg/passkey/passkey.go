@@ -0,0 +1,203 @@
+// Package passkey implements the WebAuthn ceremonies to register passkeys and log in with them.
+// The session data of a ceremony is returned as JSON so it can be stored between the begin
+// and the finish requests.
+package passkey
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+// CeremonyTimeout is the time a user has to complete a ceremony
+const CeremonyTimeout = 5 * time.Minute
+
+// ErrUnknownCredential is returned when a login uses a credential that is not registered
+var ErrUnknownCredential = errors.New("unknown passkey")
+
+// Config is the relying party configuration
+type Config struct {
+	RPID          string
+	RPDisplayName string
+	RPOrigins     []string
+}
+
+// WebAuthn runs the registration and login ceremonies
+type WebAuthn struct {
+	webAuthn *webauthn.WebAuthn
+}
+
+// New creates a new WebAuthn
+func New(config Config) (*WebAuthn, error) {
+	timeout := webauthn.TimeoutConfig{
+		Enforce:    true,
+		Timeout:    CeremonyTimeout,
+		TimeoutUVD: CeremonyTimeout,
+	}
+
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          config.RPID,
+		RPDisplayName: config.RPDisplayName,
+		RPOrigins:     config.RPOrigins,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        timeout,
+			Registration: timeout,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid webauthn configuration: %w", err)
+	}
+
+	return &WebAuthn{webAuthn: webAuthn}, nil
+}
+
+// User is a user with its registered credentials
+type User struct {
+	ID          uuid.UUID
+	Name        string
+	Credentials []webauthn.Credential
+}
+
+// WebAuthnID is the user handle, the user ID keeps it free of personal information
+func (user *User) WebAuthnID() []byte {
+	return user.ID[:]
+}
+
+func (user *User) WebAuthnName() string {
+	return user.Name
+}
+
+func (user *User) WebAuthnDisplayName() string {
+	return user.Name
+}
+
+func (user *User) WebAuthnCredentials() []webauthn.Credential {
+	return user.Credentials
+}
+
+func (user *User) WebAuthnIcon() string {
+	return ""
+}
+
+// BeginRegistration returns the options for the authenticator to create a passkey
+// and the session data of the ceremony
+func (w *WebAuthn) BeginRegistration(user *User) (*protocol.CredentialCreation, []byte, error) {
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(user.Credentials))
+	for _, credential := range user.Credentials {
+		exclusions = append(exclusions, credential.Descriptor())
+	}
+
+	options, session, err := w.webAuthn.BeginRegistration(
+		user,
+		webauthn.WithExclusions(exclusions),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementPreferred),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sessionData, err := json.Marshal(session)
+	return options, sessionData, err
+}
+
+// FinishRegistration verifies the response of the authenticator and returns the new credential
+func (w *WebAuthn) FinishRegistration(user *User, sessionData []byte, response []byte) (*webauthn.Credential, error) {
+	session, err := unmarshalSession(sessionData)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return nil, describeError(err)
+	}
+
+	credential, err := w.webAuthn.CreateCredential(user, session, parsed)
+	return credential, describeError(err)
+}
+
+// BeginLogin returns the options for the authenticator to sign in and the session data of the ceremony.
+// Without a user any discoverable passkey of the site can be used.
+func (w *WebAuthn) BeginLogin(user *User) (*protocol.CredentialAssertion, []byte, error) {
+	var options *protocol.CredentialAssertion
+	var session *webauthn.SessionData
+	var err error
+
+	if user == nil {
+		options, session, err = w.webAuthn.BeginDiscoverableLogin()
+	} else {
+		options, session, err = w.webAuthn.BeginLogin(user)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sessionData, err := json.Marshal(session)
+	return options, sessionData, err
+}
+
+// FinishLogin verifies the response of the authenticator, findUser loads the user that owns a credential.
+// It returns the user and the credential with its updated sign count.
+func (w *WebAuthn) FinishLogin(
+	sessionData []byte,
+	response []byte,
+	findUser func(credentialID, userHandle []byte) (*User, error),
+) (*User, *webauthn.Credential, error) {
+	session, err := unmarshalSession(sessionData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return nil, nil, describeError(err)
+	}
+
+	user, err := findUser(parsed.RawID, parsed.Response.UserHandle)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A login started for a specific user must be finished by the same user
+	if len(session.UserID) > 0 && !bytes.Equal(session.UserID, user.WebAuthnID()) {
+		return nil, nil, ErrUnknownCredential
+	}
+
+	if len(session.UserID) == 0 {
+		session.UserID = user.WebAuthnID()
+	}
+
+	credential, err := w.webAuthn.ValidateLogin(user, session, parsed)
+	if err != nil {
+		return nil, nil, describeError(err)
+	}
+
+	if credential.Authenticator.CloneWarning {
+		return nil, nil, errors.New("the passkey sign count went backwards, it may have been cloned")
+	}
+
+	return user, credential, nil
+}
+
+func unmarshalSession(sessionData []byte) (webauthn.SessionData, error) {
+	var session webauthn.SessionData
+	if err := json.Unmarshal(sessionData, &session); err != nil {
+		return session, fmt.Errorf("invalid session data: %w", err)
+	}
+	return session, nil
+}
+
+// describeError adds the details of a protocol error, its message alone is too generic
+func describeError(err error) error {
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) && len(protocolErr.DevInfo) > 0 {
+		return fmt.Errorf("%s: %s", protocolErr.Details, protocolErr.DevInfo)
+	}
+	return err
+}
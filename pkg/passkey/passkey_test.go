@@ -0,0 +1,253 @@
+package passkey
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testRPID   = "blog.example.com"
+	testOrigin = "https://blog.example.com"
+
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttestedData = 0x40
+)
+
+var b64 = base64.RawURLEncoding
+
+// softAuthenticator is a software authenticator with one ES256 credential
+type softAuthenticator struct {
+	credentialID []byte
+	privateKey   *ecdsa.PrivateKey
+	userHandle   []byte
+	signCount    uint32
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	credentialID := make([]byte, 16)
+	_, err = rand.Read(credentialID)
+	require.NoError(t, err)
+
+	return &softAuthenticator{credentialID: credentialID, privateKey: privateKey}
+}
+
+func (a *softAuthenticator) clientData(t *testing.T, ceremony string, challenge string) []byte {
+	clientData, err := json.Marshal(map[string]string{
+		"type":      ceremony,
+		"challenge": challenge,
+		"origin":    testOrigin,
+	})
+	require.NoError(t, err)
+	return clientData
+}
+
+func (a *softAuthenticator) authData(flags byte, attestedData []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(testRPID))
+
+	data := append([]byte{}, rpIDHash[:]...)
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.signCount)
+	return append(data, attestedData...)
+}
+
+// create answers navigator.credentials.create with a none attestation
+func (a *softAuthenticator) create(t *testing.T, challenge string, userHandle []byte) []byte {
+	a.userHandle = userHandle
+
+	coseKey, err := cbor.Marshal(map[int]interface{}{
+		1:  2,  // kty: EC2
+		3:  -7, // alg: ES256
+		-1: 1,  // crv: P-256
+		-2: a.privateKey.X.FillBytes(make([]byte, 32)),
+		-3: a.privateKey.Y.FillBytes(make([]byte, 32)),
+	})
+	require.NoError(t, err)
+
+	attestedData := make([]byte, 16) // AAGUID
+	attestedData = binary.BigEndian.AppendUint16(attestedData, uint16(len(a.credentialID)))
+	attestedData = append(attestedData, a.credentialID...)
+	attestedData = append(attestedData, coseKey...)
+
+	attestationObject, err := cbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": a.authData(flagUserPresent|flagUserVerified|flagAttestedData, attestedData),
+	})
+	require.NoError(t, err)
+
+	response, err := json.Marshal(map[string]interface{}{
+		"id":    b64.EncodeToString(a.credentialID),
+		"rawId": b64.EncodeToString(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    b64.EncodeToString(a.clientData(t, "webauthn.create", challenge)),
+			"attestationObject": b64.EncodeToString(attestationObject),
+		},
+	})
+	require.NoError(t, err)
+	return response
+}
+
+// get answers navigator.credentials.get
+func (a *softAuthenticator) get(t *testing.T, challenge string) []byte {
+	a.signCount++
+
+	clientData := a.clientData(t, "webauthn.get", challenge)
+	authData := a.authData(flagUserPresent|flagUserVerified, nil)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.privateKey, digest[:])
+	require.NoError(t, err)
+
+	response, err := json.Marshal(map[string]interface{}{
+		"id":    b64.EncodeToString(a.credentialID),
+		"rawId": b64.EncodeToString(a.credentialID),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    b64.EncodeToString(clientData),
+			"authenticatorData": b64.EncodeToString(authData),
+			"signature":         b64.EncodeToString(signature),
+			"userHandle":        b64.EncodeToString(a.userHandle),
+		},
+	})
+	require.NoError(t, err)
+	return response
+}
+
+func newTestWebAuthn(t *testing.T) *WebAuthn {
+	w, err := New(Config{
+		RPID:          testRPID,
+		RPDisplayName: "Personal Blog",
+		RPOrigins:     []string{testOrigin},
+	})
+	require.NoError(t, err)
+	return w
+}
+
+func registerTestPasskey(t *testing.T, w *WebAuthn, user *User, authenticator *softAuthenticator) {
+	options, sessionData, err := w.BeginRegistration(user)
+	require.NoError(t, err)
+	require.Equal(t, testRPID, options.Response.RelyingParty.ID)
+
+	response := authenticator.create(t, options.Response.Challenge.String(), []byte(options.Response.User.ID.(protocol.URLEncodedBase64)))
+
+	credential, err := w.FinishRegistration(user, sessionData, response)
+	require.NoError(t, err)
+	require.Equal(t, authenticator.credentialID, credential.ID)
+
+	user.Credentials = append(user.Credentials, *credential)
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	w := newTestWebAuthn(t)
+	authenticator := newSoftAuthenticator(t)
+	user := &User{ID: uuid.New(), Name: "admin"}
+
+	registerTestPasskey(t, w, user, authenticator)
+
+	findUser := func(credentialID, userHandle []byte) (*User, error) {
+		require.Equal(t, authenticator.credentialID, credentialID)
+		return user, nil
+	}
+
+	for _, loginUser := range []*User{user, nil} {
+		options, sessionData, err := w.BeginLogin(loginUser)
+		require.NoError(t, err)
+
+		response := authenticator.get(t, options.Response.Challenge.String())
+
+		found, credential, err := w.FinishLogin(sessionData, response, findUser)
+		require.NoError(t, err)
+		require.Equal(t, user.ID, found.ID)
+		require.Equal(t, authenticator.signCount, credential.Authenticator.SignCount)
+
+		user.Credentials = []webauthn.Credential{*credential}
+	}
+}
+
+func TestLoginWrongChallenge(t *testing.T) {
+	w := newTestWebAuthn(t)
+	authenticator := newSoftAuthenticator(t)
+	user := &User{ID: uuid.New(), Name: "admin"}
+
+	registerTestPasskey(t, w, user, authenticator)
+
+	_, sessionData, err := w.BeginLogin(user)
+	require.NoError(t, err)
+
+	response := authenticator.get(t, b64.EncodeToString([]byte("another challenge")))
+
+	_, _, err = w.FinishLogin(sessionData, response, func(credentialID, userHandle []byte) (*User, error) {
+		return user, nil
+	})
+	require.Error(t, err)
+}
+
+func TestLoginOtherUser(t *testing.T) {
+	w := newTestWebAuthn(t)
+	authenticator := newSoftAuthenticator(t)
+	user := &User{ID: uuid.New(), Name: "admin"}
+	other := &User{ID: uuid.New(), Name: "author"}
+
+	registerTestPasskey(t, w, user, authenticator)
+
+	// The login was started for another user
+	options, sessionData, err := w.BeginLogin(&User{ID: other.ID, Name: other.Name, Credentials: user.Credentials})
+	require.NoError(t, err)
+
+	response := authenticator.get(t, options.Response.Challenge.String())
+
+	_, _, err = w.FinishLogin(sessionData, response, func(credentialID, userHandle []byte) (*User, error) {
+		return user, nil
+	})
+	require.ErrorIs(t, err, ErrUnknownCredential)
+}
+
+func TestLoginClonedCredential(t *testing.T) {
+	w := newTestWebAuthn(t)
+	authenticator := newSoftAuthenticator(t)
+	user := &User{ID: uuid.New(), Name: "admin"}
+
+	registerTestPasskey(t, w, user, authenticator)
+
+	options, sessionData, err := w.BeginLogin(user)
+	require.NoError(t, err)
+
+	authenticator.signCount = 10
+	response := authenticator.get(t, options.Response.Challenge.String())
+
+	_, credential, err := w.FinishLogin(sessionData, response, func(credentialID, userHandle []byte) (*User, error) {
+		return user, nil
+	})
+	require.NoError(t, err)
+	user.Credentials = []webauthn.Credential{*credential}
+
+	// A copy of the key with an older counter
+	options, sessionData, err = w.BeginLogin(user)
+	require.NoError(t, err)
+
+	authenticator.signCount = 3
+	response = authenticator.get(t, options.Response.Challenge.String())
+
+	_, _, err = w.FinishLogin(sessionData, response, func(credentialID, userHandle []byte) (*User, error) {
+		return user, nil
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,227 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	youTubeID    = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	vimeoID      = regexp.MustCompile(`^[0-9]{1,12}$`)
+	gistUser     = regexp.MustCompile(`^[A-Za-z0-9-]{1,39}$`)
+	gistID       = regexp.MustCompile(`^[0-9a-f]{1,40}$`)
+	gistFile     = regexp.MustCompile(`^[A-Za-z0-9._-]{1,255}$`)
+	calloutTypes = map[string]bool{"note": true, "tip": true, "info": true, "warning": true, "danger": true}
+)
+
+// ErrPostNotFound is returned by a PostResolver when there is no post with the ID
+var ErrPostNotFound = errors.New("post not found")
+
+// PostLink is where a post can be read
+type PostLink struct {
+	Title     string
+	URL       string
+	Published bool
+}
+
+// PostResolver finds the current link of the posts for the post shortcode
+type PostResolver interface {
+	ResolvePost(ctx context.Context, id uuid.UUID) (PostLink, error)
+}
+
+// embedFrame is the markup shared by the embedded players
+func embedFrame(provider string, src string, title string) string {
+	return fmt.Sprintf(
+		`<div class="shortcode-embed shortcode-%s"><iframe src="%s" title="%s" loading="lazy" `+
+			`allow="accelerometer; autoplay; clipboard-write; encrypted-media; gyroscope; picture-in-picture; fullscreen" `+
+			`referrerpolicy="strict-origin-when-cross-origin" allowfullscreen></iframe></div>`,
+		provider, html.EscapeString(src), html.EscapeString(title),
+	)
+}
+
+// expandYouTube embeds a YouTube video with privacy-enhanced mode: {{< youtube id title="..." >}}
+func expandYouTube(ctx context.Context, call *Call) (string, error) {
+	id := call.Arg("id", 0)
+	if !youTubeID.MatchString(id) {
+		return "", invalidShortcode("invalid YouTube video id %q", id)
+	}
+
+	title := call.Arg("title", 1)
+	if len(title) == 0 {
+		title = "YouTube video"
+	}
+	return embedFrame("youtube", "https://www.youtube-nocookie.com/embed/"+id, title), nil
+}
+
+// expandVimeo embeds a Vimeo video: {{< vimeo id title="..." >}}
+func expandVimeo(ctx context.Context, call *Call) (string, error) {
+	id := call.Arg("id", 0)
+	if !vimeoID.MatchString(id) {
+		return "", invalidShortcode("invalid Vimeo video id %q", id)
+	}
+
+	title := call.Arg("title", 1)
+	if len(title) == 0 {
+		title = "Vimeo video"
+	}
+	return embedFrame("vimeo", "https://player.vimeo.com/video/"+id+"?dnt=1", title), nil
+}
+
+// expandGist embeds a GitHub gist: {{< gist user id file="..." >}}. The script of the gist
+// runs in a sandboxed frame, so it can not reach the page that shows the post.
+func expandGist(ctx context.Context, call *Call) (string, error) {
+	user := call.Arg("user", 0)
+	if !gistUser.MatchString(user) {
+		return "", invalidShortcode("invalid GitHub user %q", user)
+	}
+	id := call.Arg("id", 1)
+	if !gistID.MatchString(id) {
+		return "", invalidShortcode("invalid gist id %q", id)
+	}
+
+	gistURL := "https://gist.github.com/" + user + "/" + id
+	script := gistURL + ".js"
+	file := call.Arg("file", 2)
+	if len(file) > 0 {
+		if !gistFile.MatchString(file) {
+			return "", invalidShortcode("invalid gist file %q", file)
+		}
+		script += "?file=" + url.QueryEscape(file)
+	}
+
+	document := `<base target="_blank"><script src="` + html.EscapeString(script) + `"></script>`
+	return fmt.Sprintf(
+		`<div class="shortcode-embed shortcode-gist"><iframe srcdoc="%s" title="Gist %s" loading="lazy" `+
+			`sandbox="allow-scripts allow-popups allow-popups-to-escape-sandbox"></iframe>`+
+			`<a href="%s" rel="nofollow">View the gist on GitHub</a></div>`,
+		html.EscapeString(document), html.EscapeString(id), html.EscapeString(gistURL),
+	), nil
+}
+
+// expandCallout wraps its content in a box: {{< callout type="warning" title="..." >}}content{{< /callout >}}
+func expandCallout(ctx context.Context, call *Call) (string, error) {
+	kind := call.Arg("type", 0)
+	if len(kind) == 0 {
+		kind = "note"
+	}
+	if !calloutTypes[kind] {
+		return "", invalidShortcode("unknown callout type %q", kind)
+	}
+	if !call.HasInner {
+		call.Warnf("callout without content")
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, `<aside class="callout callout-%s" role="note">`, kind)
+	if title := call.Arg("title", 1); len(title) > 0 {
+		fmt.Fprintf(&builder, `<p class="callout-title">%s</p>`, html.EscapeString(title))
+	}
+	fmt.Fprintf(&builder, `<div class="callout-body">%s</div></aside>`, call.Inner)
+	return builder.String(), nil
+}
+
+// expandFigure shows an image with a caption: {{< figure src="..." alt="..." caption="..." >}},
+// the content of the paired form is the caption
+func expandFigure(ctx context.Context, call *Call) (string, error) {
+	src := call.Arg("src", 0)
+	if !isSafeImageURL(src) {
+		return "", invalidShortcode("invalid image source %q", src)
+	}
+
+	alt := call.Arg("alt", 1)
+	if len(alt) == 0 {
+		call.Warnf("figure without alternative text")
+	}
+
+	caption := html.EscapeString(call.Arg("caption", 2))
+	if call.HasInner {
+		caption = inlineHTML(call.Inner)
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, `<figure class="shortcode-figure"><img src="%s" alt="%s" loading="lazy">`, html.EscapeString(src), html.EscapeString(alt))
+	if len(caption) > 0 {
+		fmt.Fprintf(&builder, `<figcaption>%s</figcaption>`, caption)
+	}
+	builder.WriteString(`</figure>`)
+	return builder.String(), nil
+}
+
+// isSafeImageURL accepts absolute http URLs and paths of the same site
+func isSafeImageURL(src string) bool {
+	if len(src) == 0 {
+		return false
+	}
+
+	parsed, err := url.Parse(src)
+	if err != nil {
+		return false
+	}
+	if parsed.Scheme == "http" || parsed.Scheme == "https" {
+		return len(parsed.Host) > 0
+	}
+	return len(parsed.Scheme) == 0 && len(parsed.Host) == 0 && !strings.HasPrefix(src, "//")
+}
+
+// postShortcode links to another post by ID: {{< post id >}}, {{< post id text="..." >}}
+// or {{< post id >}}text{{< /post >}}. The link follows the current slug of the post.
+// An unpublished post is not linked nor named, only the text is shown, or the shortcode
+// itself when there is no text.
+type postShortcode struct {
+	posts PostResolver
+}
+
+func (shortcode postShortcode) Expand(ctx context.Context, call *Call) (string, error) {
+	id, err := uuid.Parse(call.Arg("id", 0))
+	if err != nil {
+		return "", invalidShortcode("invalid post id %q", call.Arg("id", 0))
+	}
+
+	link, err := shortcode.posts.ResolvePost(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrPostNotFound) {
+			return "", invalidShortcode("post %s not found", id)
+		}
+		return "", err
+	}
+
+	text := html.EscapeString(call.Arg("text", 1))
+	if call.HasInner && len(call.Inner) > 0 {
+		text = inlineHTML(call.Inner)
+	}
+
+	if !link.Published {
+		if len(text) == 0 {
+			return "", invalidShortcode("post %s is not published", id)
+		}
+		call.Warnf("post %s is not published", id)
+		return text, nil
+	}
+
+	if len(text) == 0 {
+		text = html.EscapeString(link.Title)
+	}
+
+	return fmt.Sprintf(`<a href="%s" class="shortcode-post">%s</a>`, html.EscapeString(link.URL), text), nil
+}
+
+// inlineHTML removes the paragraph that wraps a rendered text of a single line
+func inlineHTML(inner string) string {
+	inner = strings.TrimSpace(inner)
+	content, ok := strings.CutPrefix(inner, "<p>")
+	if !ok {
+		return inner
+	}
+	content, ok = strings.CutSuffix(content, "</p>")
+	if !ok || strings.Contains(content, "<p>") {
+		return inner
+	}
+	return content
+}
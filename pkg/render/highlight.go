@@ -0,0 +1,57 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// DefaultTheme is the theme of the highlighted code when none is configured
+const DefaultTheme = "github"
+
+// highlightClassPrefix namespaces the classes of the highlighted code, the sanitizer
+// only keeps the classes with this prefix so the source can not use the classes of the site
+const highlightClassPrefix = "hl-"
+
+var highlightClasses = regexp.MustCompile(`^` + highlightClassPrefix + `[a-z0-9]+( ` + highlightClassPrefix + `[a-z0-9]+)*$`)
+
+var ErrUnknownTheme = errors.New("unknown highlight theme")
+
+// highlightOptions are the options of the formatter of the code blocks, the stylesheet is made with the same ones
+func highlightOptions() []chromahtml.Option {
+	return []chromahtml.Option{
+		chromahtml.WithClasses(true),
+		chromahtml.ClassPrefix(highlightClassPrefix),
+		chromahtml.LineNumbersInTable(true),
+	}
+}
+
+// Themes returns the names of the themes of the highlighted code
+func Themes() []string {
+	return styles.Names()
+}
+
+// IsTheme returns true if there is a theme with the given name
+func IsTheme(theme string) bool {
+	_, ok := styles.Registry[theme]
+	return ok
+}
+
+// ThemeCSS returns the stylesheet of a theme for the highlighted code
+func ThemeCSS(theme string) (string, error) {
+	style, ok := styles.Registry[theme]
+	if !ok {
+		return "", fmt.Errorf("%w %s", ErrUnknownTheme, theme)
+	}
+
+	var css strings.Builder
+	err := chromahtml.New(highlightOptions()...).WriteCSS(&css, style)
+	if err != nil {
+		return "", err
+	}
+	return css.String(), nil
+}
@@ -0,0 +1,54 @@
+package render
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHighlightsCode(t *testing.T) {
+	renderer := NewRenderer(nil)
+
+	document, err := renderer.Render(context.Background(), FormatMarkdown, "```go\nfunc main() {}\n```\n")
+	require.NoError(t, err)
+
+	require.Contains(t, document.HTML, `<pre class="hl-chroma"><code>`)
+	require.Contains(t, document.HTML, `<span class="hl-kd">func</span>`)
+	require.NotContains(t, document.HTML, "style=")
+	require.NotContains(t, document.HTML, "hl-lntable")
+}
+
+func TestRenderHighlightLines(t *testing.T) {
+	renderer := NewRenderer(nil)
+
+	source := "```go {linenos=true, hl_lines=[2]}\npackage main\nfunc main() {}\n```\n"
+	document, err := renderer.Render(context.Background(), FormatMarkdown, source)
+	require.NoError(t, err)
+
+	require.Contains(t, document.HTML, `<table class="hl-lntable">`)
+	require.Contains(t, document.HTML, `<span class="hl-lnt">1`)
+	require.Contains(t, document.HTML, `<span class="hl-line hl-hl">`)
+}
+
+func TestRenderHighlightClasses(t *testing.T) {
+	renderer := NewRenderer(nil)
+
+	document, err := renderer.Render(context.Background(), FormatHTML, `<span class="hl-k">a</span><span class="hl-k admin">b</span><div class="hidden">c</div>`)
+	require.NoError(t, err)
+	require.Equal(t, `<span class="hl-k">a</span><span>b</span><div>c</div>`, document.HTML)
+}
+
+func TestThemeCSS(t *testing.T) {
+	require.True(t, IsTheme(DefaultTheme))
+	require.Contains(t, Themes(), DefaultTheme)
+
+	css, err := ThemeCSS("monokai")
+	require.NoError(t, err)
+	require.Contains(t, css, ".hl-chroma .hl-kd")
+	require.Contains(t, css, "#272822")
+
+	_, err = ThemeCSS("unknown")
+	require.ErrorIs(t, err, ErrUnknownTheme)
+	require.False(t, IsTheme("unknown"))
+}
@@ -0,0 +1,151 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
+
+// textOnlyElements can not hold the HTML of a shortcode, the shortcodes in them are shown as text
+var textOnlyElements = map[string]string{
+	"a":    "a link",
+	"code": "code",
+	"pre":  "code",
+	"kbd":  "code",
+	"samp": "code",
+	"h1":   "a heading",
+	"h2":   "a heading",
+	"h3":   "a heading",
+	"h4":   "a heading",
+	"h5":   "a heading",
+	"h6":   "a heading",
+}
+
+// shortcodePlaceholder is what a placeholder of the rendered source stands for
+type shortcodePlaceholder struct {
+	// html is the expanded shortcode, or its escaped source when it was not expanded
+	html string
+	// source is the shortcode as it is written in the post
+	source   string
+	expanded bool
+	call     *Call
+	reported bool
+}
+
+// htmlToken is a token of the sanitized HTML, raw is its text as it is in the HTML
+type htmlToken struct {
+	kind nethtml.TokenType
+	raw  string
+	tag  string
+}
+
+// addPlaceholder returns a new placeholder for a shortcode
+func (state *renderState) addPlaceholder(placeholder shortcodePlaceholder) string {
+	name := fmt.Sprintf("%si%dz", state.prefix, len(state.placeholders))
+	state.placeholders[name] = placeholder
+	return name
+}
+
+// sourceText replaces the placeholders of a text with the source of their shortcodes
+func (state *renderState) sourceText(text string) string {
+	return state.pattern.ReplaceAllStringFunc(text, func(name string) string {
+		placeholder, ok := state.placeholders[name]
+		if !ok {
+			return name
+		}
+		return placeholder.source
+	})
+}
+
+// resolvePlaceholders replaces the placeholders of the sanitized HTML. Only the placeholders
+// in the text of the document are expanded, in an attribute or in textOnlyElements they are
+// replaced by the escaped source of the shortcode, so the markup around them stays valid.
+func (state *renderState) resolvePlaceholders(document string) string {
+	if !strings.Contains(document, state.prefix) {
+		return document
+	}
+
+	var tokens []htmlToken
+	tokenizer := nethtml.NewTokenizer(strings.NewReader(document))
+	for {
+		kind := tokenizer.Next()
+		if kind == nethtml.ErrorToken {
+			break
+		}
+		token := htmlToken{kind: kind, raw: string(tokenizer.Raw())}
+		if kind == nethtml.StartTagToken || kind == nethtml.EndTagToken {
+			name, _ := tokenizer.TagName()
+			token.tag = string(name)
+		}
+		tokens = append(tokens, token)
+	}
+
+	var output strings.Builder
+	var open []string
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		switch token.kind {
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken:
+			// A shortcode alone in a line is a paragraph of markdown, it takes the place of the paragraph
+			if token.raw == "<p>" && len(open) == 0 && i+2 < len(tokens) && tokens[i+2].raw == "</p>" {
+				placeholder, ok := state.placeholders[tokens[i+1].raw]
+				if ok && placeholder.expanded && state.format == FormatMarkdown {
+					output.WriteString(placeholder.html)
+					i += 2
+					continue
+				}
+			}
+
+			if _, ok := textOnlyElements[token.tag]; ok && token.kind == nethtml.StartTagToken {
+				open = append(open, token.tag)
+			}
+			output.WriteString(state.pattern.ReplaceAllStringFunc(token.raw, func(name string) string {
+				return state.placeholderText(name, "an attribute")
+			}))
+
+		case nethtml.EndTagToken:
+			if len(open) > 0 && open[len(open)-1] == token.tag {
+				open = open[:len(open)-1]
+			}
+			output.WriteString(token.raw)
+
+		case nethtml.TextToken:
+			output.WriteString(state.pattern.ReplaceAllStringFunc(token.raw, func(name string) string {
+				if len(open) > 0 {
+					return state.placeholderText(name, textOnlyElements[open[len(open)-1]])
+				}
+				placeholder, ok := state.placeholders[name]
+				if !ok {
+					return name
+				}
+				return placeholder.html
+			}))
+
+		default:
+			output.WriteString(token.raw)
+		}
+	}
+	return output.String()
+}
+
+// placeholderText returns the escaped source of the shortcode of a placeholder that can not be
+// expanded where it is, a warning tells that the shortcode was not expanded
+func (state *renderState) placeholderText(name string, where string) string {
+	placeholder, ok := state.placeholders[name]
+	if !ok {
+		return name
+	}
+
+	if placeholder.expanded && !placeholder.reported {
+		state.warnings = append(state.warnings, Warning{
+			Line:      placeholder.call.Line,
+			Shortcode: placeholder.call.Name,
+			Message:   "shortcode can not be used in " + where + ", it is shown as text",
+		})
+		placeholder.reported = true
+		state.placeholders[name] = placeholder
+	}
+	return html.EscapeString(placeholder.source)
+}
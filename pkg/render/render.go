@@ -0,0 +1,322 @@
+// Package render turns the source of the posts into sanitized HTML with a table of contents
+package render
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+// Version changes every time the output of the renderer changes,
+// the posts rendered with an older version have to be rendered again
+const Version = 4
+
+// Source formats of the posts
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// anchorClass is the class of the links added to the headings
+const anchorClass = "anchor"
+
+var ErrUnsupportedFormat = errors.New("unsupported content format")
+
+// IsSupportedFormat returns true if the format is one of the source formats
+func IsSupportedFormat(format string) bool {
+	return format == FormatMarkdown || format == FormatHTML
+}
+
+// Heading is an entry of the table of contents, ID is the anchor of the heading in the HTML
+type Heading struct {
+	Level int    `json:"level"`
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+}
+
+// Document is a rendered post, Warnings are the problems found in its shortcodes
+type Document struct {
+	HTML     string
+	TOC      []Heading
+	Warnings []Warning
+}
+
+// Renderer renders Markdown and HTML sources, it is safe for concurrent use
+type Renderer struct {
+	markdown   goldmark.Markdown
+	policy     *bluemonday.Policy
+	shortcodes *Registry
+}
+
+// NewRenderer creates a renderer of GitHub Flavored Markdown whose output only keeps the
+// HTML that is safe to embed in a page, the raw HTML of the source goes through the same policy.
+// The fenced code blocks are highlighted with classes, so the theme is chosen by the stylesheet
+// of ThemeCSS, their info string takes the attributes linenos, linenostart and hl_lines:
+//
+//	```go {linenos=true, hl_lines=[2, "4-6"]}
+//
+// The shortcodes of the registry are expanded after the sanitization, without a registry they are all unknown.
+// In attributes, headings, links and code they are shown as text with a warning.
+func NewRenderer(shortcodes *Registry) *Renderer {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			extension.Footnote,
+			highlighting.NewHighlighting(
+				highlighting.WithFormatOptions(highlightOptions()...),
+			),
+		),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+		goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+	)
+
+	return &Renderer{
+		markdown:   markdown,
+		policy:     newPolicy(),
+		shortcodes: shortcodes,
+	}
+}
+
+// newPolicy allows the user generated content plus the heading anchors, the task list checkboxes
+// and the classes of the highlighted code
+func newPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	// The anchors of the headings are links to the same page
+	policy.RequireNoFollowOnLinks(false)
+	policy.RequireNoFollowOnFullyQualifiedLinks(true)
+	policy.AllowAttrs("id").Matching(regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^` + anchorClass + `$`)).OnElements("a")
+	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+	policy.AllowAttrs("class").Matching(highlightClasses).OnElements("div", "pre", "code", "span", "table", "td")
+	return policy
+}
+
+// Render renders the source of a post in the given format and expands its shortcodes
+func (renderer *Renderer) Render(ctx context.Context, format string, source string) (Document, error) {
+	if !IsSupportedFormat(format) {
+		return Document{}, fmt.Errorf("%w %s", ErrUnsupportedFormat, format)
+	}
+
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return Document{}, err
+	}
+
+	nodes, warnings := parseShortcodes(source)
+	prefix := "shortcode" + hex.EncodeToString(nonce)
+	state := &renderState{
+		ctx:          ctx,
+		format:       format,
+		prefix:       prefix,
+		pattern:      regexp.MustCompile(prefix + `i[0-9]+z`),
+		placeholders: map[string]shortcodePlaceholder{},
+		warnings:     warnings,
+	}
+	// The shortcodes of the headings take part in the anchors with their source
+	state.ids = &headingIDs{used: map[string]bool{}, text: state.sourceText}
+
+	output, toc, err := renderer.renderNodes(state, nodes)
+	if err != nil {
+		return Document{}, err
+	}
+
+	if state.warnings == nil {
+		state.warnings = []Warning{}
+	}
+	sort.SliceStable(state.warnings, func(i, j int) bool {
+		return state.warnings[i].Line < state.warnings[j].Line
+	})
+	return Document{HTML: output, TOC: toc, Warnings: state.warnings}, nil
+}
+
+// renderState is shared by the content of a post and the content of its paired shortcodes
+type renderState struct {
+	ctx    context.Context
+	format string
+	ids    *headingIDs
+	// prefix makes the placeholders of the shortcodes unique to the rendering
+	prefix       string
+	pattern      *regexp.Regexp
+	placeholders map[string]shortcodePlaceholder
+	warnings     []Warning
+}
+
+// renderNodes renders the source with a placeholder for every shortcode, the placeholders
+// are replaced by the shortcodes after the sanitization so their HTML is kept as it is
+func (renderer *Renderer) renderNodes(state *renderState, nodes []shortcodeNode) (string, []Heading, error) {
+	var source strings.Builder
+
+	for _, node := range nodes {
+		if node.call == nil && !node.literal {
+			source.WriteString(node.text)
+			continue
+		}
+
+		placeholder := shortcodePlaceholder{html: html.EscapeString(node.text), source: node.text}
+		if node.call != nil {
+			var err error
+			placeholder.html, placeholder.expanded, err = renderer.expandShortcode(state, node)
+			if err != nil {
+				return "", nil, err
+			}
+			placeholder.source = node.source()
+			placeholder.call = node.call
+		}
+		source.WriteString(state.addPlaceholder(placeholder))
+	}
+
+	var document Document
+	var err error
+	if state.format == FormatMarkdown {
+		document, err = renderer.renderMarkdown(state, []byte(source.String()))
+		if err != nil {
+			return "", nil, err
+		}
+	} else {
+		document = Document{HTML: renderer.policy.Sanitize(source.String()), TOC: []Heading{}}
+	}
+
+	return state.resolvePlaceholders(document.HTML), document.TOC, nil
+}
+
+// expandShortcode renders the content of a shortcode and expands it, the unknown and invalid
+// shortcodes are kept as text with a warning and are not expanded
+func (renderer *Renderer) expandShortcode(state *renderState, node shortcodeNode) (string, bool, error) {
+	call := node.call
+	if call.HasInner {
+		inner, _, err := renderer.renderNodes(state, node.children)
+		if err != nil {
+			return "", false, err
+		}
+		call.Inner = inner
+	}
+
+	literal := html.EscapeString(node.open) + call.Inner + html.EscapeString(node.close)
+
+	shortcode, ok := renderer.shortcodes.get(call.Name)
+	if !ok {
+		call.Warnf("unknown shortcode")
+		state.warnings = append(state.warnings, call.warnings...)
+		return literal, false, nil
+	}
+
+	expanded, err := shortcode.Expand(state.ctx, call)
+	state.warnings = append(state.warnings, call.warnings...)
+	if err != nil {
+		if errors.Is(err, ErrInvalidShortcode) {
+			state.warnings = append(state.warnings, Warning{Line: call.Line, Shortcode: call.Name, Message: err.Error()})
+			return literal, false, nil
+		}
+		return "", false, fmt.Errorf("cannot expand shortcode %s: %w", call.Name, err)
+	}
+	return expanded, true, nil
+}
+
+func (renderer *Renderer) renderMarkdown(state *renderState, source []byte) (Document, error) {
+	context := parser.NewContext(parser.WithIDs(state.ids))
+	root := renderer.markdown.Parser().Parse(text.NewReader(source), parser.WithContext(context))
+
+	toc := []Heading{}
+	err := ast.Walk(root, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := node.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+
+		id, ok := heading.AttributeString("id")
+		if !ok {
+			return ast.WalkSkipChildren, nil
+		}
+		anchor := string(id.([]byte))
+
+		toc = append(toc, Heading{
+			Level: heading.Level,
+			ID:    anchor,
+			Text:  state.sourceText(nodeText(heading, source)),
+		})
+
+		link := ast.NewLink()
+		link.Destination = []byte("#" + anchor)
+		link.SetAttributeString("class", []byte(anchorClass))
+		link.AppendChild(link, ast.NewString([]byte("#")))
+		heading.AppendChild(heading, link)
+
+		return ast.WalkSkipChildren, nil
+	})
+	if err != nil {
+		return Document{}, err
+	}
+
+	var output bytes.Buffer
+	err = renderer.markdown.Renderer().Render(&output, source, root)
+	if err != nil {
+		return Document{}, err
+	}
+
+	return Document{
+		HTML: renderer.policy.SanitizeReader(&output).String(),
+		TOC:  toc,
+	}, nil
+}
+
+// nodeText returns the plain text of the inline children of a node
+func nodeText(node ast.Node, source []byte) string {
+	var builder strings.Builder
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		switch child := child.(type) {
+		case *ast.Text:
+			builder.Write(child.Segment.Value(source))
+			if child.SoftLineBreak() {
+				builder.WriteByte(' ')
+			}
+		case *ast.String:
+			builder.Write(child.Value)
+		default:
+			builder.WriteString(nodeText(child, source))
+		}
+	}
+	return builder.String()
+}
+
+// headingIDs makes the anchors of the headings of a document from their slugs
+type headingIDs struct {
+	used map[string]bool
+	// text replaces the placeholders of the heading before it is slugified
+	text func(string) string
+}
+
+func (ids *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	base := util.Slugify(ids.text(string(value)))
+	if len(base) == 0 {
+		base = "section"
+	}
+
+	id := base
+	for n := 1; ids.used[id]; n++ {
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+	ids.used[id] = true
+	return []byte(id)
+}
+
+func (ids *headingIDs) Put(value []byte) {
+	ids.used[string(value)] = true
+}
@@ -0,0 +1,81 @@
+package render
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	renderer := NewRenderer(nil)
+
+	source := "# Hello *World*\n\nSome **text**.\n\n## Ñandú `code`\n\n### Hello World\n\n- [x] done\n"
+	document, err := renderer.Render(context.Background(), FormatMarkdown, source)
+	require.NoError(t, err)
+
+	require.Contains(t, document.HTML, `<h1 id="hello-world">Hello <em>World</em><a href="#hello-world" class="anchor">#</a></h1>`)
+	require.Contains(t, document.HTML, `<p>Some <strong>text</strong>.</p>`)
+	require.Contains(t, document.HTML, `<h2 id="nandu-code">`)
+	require.Contains(t, document.HTML, `<h3 id="hello-world-1">`)
+	require.Contains(t, document.HTML, `<input checked="" disabled="" type="checkbox">`)
+
+	require.Equal(t, []Heading{
+		{Level: 1, ID: "hello-world", Text: "Hello World"},
+		{Level: 2, ID: "nandu-code", Text: "Ñandú code"},
+		{Level: 3, ID: "hello-world-1", Text: "Hello World"},
+	}, document.TOC)
+}
+
+func TestRenderSanitizesHTML(t *testing.T) {
+	renderer := NewRenderer(nil)
+
+	testCases := []struct {
+		name   string
+		format string
+		source string
+	}{
+		{name: "Markdown", format: FormatMarkdown, source: "Hi\n\n<script>alert(1)</script>\n\n<img src=\"a.png\" onerror=\"alert(1)\">\n\n[link](javascript:alert(1))\n"},
+		{name: "HTML", format: FormatHTML, source: `<p>Hi</p><script>alert(1)</script><img src="a.png" onerror="alert(1)"><a href="javascript:alert(1)">link</a>`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			document, err := renderer.Render(context.Background(), tc.format, tc.source)
+			require.NoError(t, err)
+
+			require.Contains(t, document.HTML, "Hi")
+			require.Contains(t, document.HTML, `<img src="a.png">`)
+			require.NotContains(t, document.HTML, "<script")
+			require.NotContains(t, document.HTML, "onerror")
+			require.NotContains(t, document.HTML, "javascript:")
+		})
+	}
+}
+
+func TestRenderExternalLinks(t *testing.T) {
+	renderer := NewRenderer(nil)
+
+	document, err := renderer.Render(context.Background(), FormatMarkdown, "[site](https://example.com)\n")
+	require.NoError(t, err)
+	require.Contains(t, document.HTML, `rel="nofollow"`)
+}
+
+func TestRenderHTMLHasNoTOC(t *testing.T) {
+	renderer := NewRenderer(nil)
+
+	document, err := renderer.Render(context.Background(), FormatHTML, "<h1>Title</h1>")
+	require.NoError(t, err)
+	require.Equal(t, "<h1>Title</h1>", document.HTML)
+	require.Empty(t, document.TOC)
+}
+
+func TestRenderUnsupportedFormat(t *testing.T) {
+	renderer := NewRenderer(nil)
+
+	_, err := renderer.Render(context.Background(), "rst", "Title\n=====")
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+	require.False(t, IsSupportedFormat("rst"))
+	require.True(t, IsSupportedFormat(FormatMarkdown))
+	require.True(t, IsSupportedFormat(FormatHTML))
+}
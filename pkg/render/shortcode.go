@@ -0,0 +1,387 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Delimiters of the shortcodes, {{< name arg key="value" >}} and the paired form
+// {{< name >}}inner content{{< /name >}}. {{</* name */>}} is written as a literal shortcode.
+const (
+	shortcodeOpen         = "{{<"
+	shortcodeClose        = ">}}"
+	shortcodeCommentOpen  = "{{</*"
+	shortcodeCommentClose = "*/>}}"
+)
+
+var shortcodeName = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
+
+// ErrInvalidShortcode is returned by the shortcodes that can not expand their arguments,
+// the shortcode is kept as text and the error is reported as a warning of the document
+var ErrInvalidShortcode = errors.New("invalid shortcode")
+
+// Shortcode expands a shortcode of the post content into HTML. The HTML is not sanitized,
+// so the arguments and the text of the shortcode have to be escaped by Expand.
+type Shortcode interface {
+	Expand(ctx context.Context, call *Call) (string, error)
+}
+
+// ShortcodeFunc is a function that implements Shortcode
+type ShortcodeFunc func(ctx context.Context, call *Call) (string, error)
+
+func (f ShortcodeFunc) Expand(ctx context.Context, call *Call) (string, error) {
+	return f(ctx, call)
+}
+
+// Registry holds the shortcodes that can be used in the posts by name
+type Registry struct {
+	shortcodes map[string]Shortcode
+}
+
+// NewRegistry creates a registry with the built-in shortcodes, the post shortcode
+// is only registered when there is a resolver of the posts
+func NewRegistry(posts PostResolver) *Registry {
+	registry := &Registry{shortcodes: map[string]Shortcode{}}
+	registry.Register("youtube", ShortcodeFunc(expandYouTube))
+	registry.Register("vimeo", ShortcodeFunc(expandVimeo))
+	registry.Register("gist", ShortcodeFunc(expandGist))
+	registry.Register("callout", ShortcodeFunc(expandCallout))
+	registry.Register("figure", ShortcodeFunc(expandFigure))
+	if posts != nil {
+		registry.Register("post", postShortcode{posts: posts})
+	}
+	return registry
+}
+
+// Register adds a shortcode to the registry, it replaces the shortcode with the same name
+func (registry *Registry) Register(name string, shortcode Shortcode) {
+	if !shortcodeName.MatchString(name) {
+		panic(fmt.Sprintf("render: invalid shortcode name %q", name))
+	}
+	registry.shortcodes[name] = shortcode
+}
+
+// Names returns the names of the registered shortcodes
+func (registry *Registry) Names() []string {
+	names := make([]string, 0, len(registry.shortcodes))
+	for name := range registry.shortcodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (registry *Registry) get(name string) (Shortcode, bool) {
+	if registry == nil {
+		return nil, false
+	}
+	shortcode, ok := registry.shortcodes[name]
+	return shortcode, ok
+}
+
+// Warning is a problem found while rendering a post that did not stop the rendering
+type Warning struct {
+	Line      int    `json:"line"`
+	Shortcode string `json:"shortcode,omitempty"`
+	Message   string `json:"message"`
+}
+
+// Call is a use of a shortcode in a post
+type Call struct {
+	Name string
+	// Args are the arguments with a name and Positional the ones without it
+	Args       map[string]string
+	Positional []string
+	// Inner is the sanitized HTML of the content of a paired shortcode
+	Inner    string
+	HasInner bool
+	Line     int
+
+	warnings []Warning
+}
+
+// Arg returns the argument with the given name or else the positional argument at the given position
+func (call *Call) Arg(name string, position int) string {
+	if value, ok := call.Args[name]; ok {
+		return value
+	}
+	if position >= 0 && position < len(call.Positional) {
+		return call.Positional[position]
+	}
+	return ""
+}
+
+// Warnf adds a warning about the call to the document
+func (call *Call) Warnf(format string, args ...any) {
+	call.warnings = append(call.warnings, Warning{
+		Line:      call.Line,
+		Shortcode: call.Name,
+		Message:   fmt.Sprintf(format, args...),
+	})
+}
+
+// invalidShortcode creates an ErrInvalidShortcode with the reason
+func invalidShortcode(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidShortcode, fmt.Sprintf(format, args...))
+}
+
+// shortcodeNode is a piece of the source, either text or a shortcode with its content
+type shortcodeNode struct {
+	text string
+	// literal is text that is written as it is in the output instead of being rendered
+	literal bool
+
+	call     *Call
+	open     string
+	close    string
+	children []shortcodeNode
+}
+
+// source returns the node as it is written in the post
+func (node shortcodeNode) source() string {
+	if node.call == nil {
+		return node.text
+	}
+
+	var builder strings.Builder
+	builder.WriteString(node.open)
+	for _, child := range node.children {
+		builder.WriteString(child.source())
+	}
+	builder.WriteString(node.close)
+	return builder.String()
+}
+
+// shortcodeToken is a tag found by the scanner
+type shortcodeToken struct {
+	raw        string
+	line       int
+	name       string
+	args       map[string]string
+	positional []string
+	closing    bool
+	selfClosed bool
+}
+
+// parseShortcodes splits the source in text and shortcodes, a shortcode with a closing tag
+// takes the content in between, the malformed tags are kept as text with a warning
+func parseShortcodes(source string) ([]shortcodeNode, []Warning) {
+	var pieces []any
+	var warnings []Warning
+
+	line := 1
+	for len(source) > 0 {
+		start := strings.Index(source, shortcodeOpen)
+		if start < 0 {
+			pieces = append(pieces, shortcodeNode{text: source})
+			break
+		}
+		if start > 0 {
+			pieces = append(pieces, shortcodeNode{text: source[:start]})
+			line += strings.Count(source[:start], "\n")
+			source = source[start:]
+		}
+
+		if strings.HasPrefix(source, shortcodeCommentOpen) {
+			end := strings.Index(source, shortcodeCommentClose)
+			if end >= 0 {
+				raw := source[:end+len(shortcodeCommentClose)]
+				text := shortcodeOpen + raw[len(shortcodeCommentOpen):end] + shortcodeClose
+				pieces = append(pieces, shortcodeNode{text: text, literal: true})
+				line += strings.Count(raw, "\n")
+				source = source[len(raw):]
+				continue
+			}
+		}
+
+		end := strings.Index(source, shortcodeClose)
+		if end < 0 {
+			warnings = append(warnings, Warning{Line: line, Message: "shortcode is not closed"})
+			pieces = append(pieces, shortcodeNode{text: shortcodeOpen, literal: true})
+			source = source[len(shortcodeOpen):]
+			continue
+		}
+
+		raw := source[:end+len(shortcodeClose)]
+		token, err := parseShortcodeTag(raw)
+		if err != nil {
+			warnings = append(warnings, Warning{Line: line, Message: err.Error()})
+			pieces = append(pieces, shortcodeNode{text: raw, literal: true})
+		} else {
+			token.line = line
+			pieces = append(pieces, token)
+		}
+		line += strings.Count(raw, "\n")
+		source = source[len(raw):]
+	}
+
+	nodes, extra := buildShortcodeTree(pieces)
+	return nodes, append(warnings, extra...)
+}
+
+// buildShortcodeTree pairs the opening tags with their closing tags
+func buildShortcodeTree(pieces []any) ([]shortcodeNode, []Warning) {
+	var nodes []shortcodeNode
+	var warnings []Warning
+
+	for i := 0; i < len(pieces); i++ {
+		token, ok := pieces[i].(shortcodeToken)
+		if !ok {
+			nodes = append(nodes, pieces[i].(shortcodeNode))
+			continue
+		}
+
+		if token.closing {
+			warnings = append(warnings, Warning{Line: token.line, Shortcode: token.name, Message: "closing tag without an opening tag"})
+			nodes = append(nodes, shortcodeNode{text: token.raw, literal: true})
+			continue
+		}
+
+		node := shortcodeNode{
+			call: &Call{
+				Name:       token.name,
+				Args:       token.args,
+				Positional: token.positional,
+				Line:       token.line,
+			},
+			open: token.raw,
+		}
+
+		if !token.selfClosed {
+			if end := matchingShortcodeClose(pieces, i); end > 0 {
+				var extra []Warning
+				node.children, extra = buildShortcodeTree(pieces[i+1 : end])
+				warnings = append(warnings, extra...)
+				node.close = pieces[end].(shortcodeToken).raw
+				node.call.HasInner = true
+				i = end
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, warnings
+}
+
+// matchingShortcodeClose returns the index of the closing tag of the tag at start, or -1 when there is none
+func matchingShortcodeClose(pieces []any, start int) int {
+	name := pieces[start].(shortcodeToken).name
+	depth := 0
+	for i := start + 1; i < len(pieces); i++ {
+		token, ok := pieces[i].(shortcodeToken)
+		if !ok || token.name != name {
+			continue
+		}
+		switch {
+		case token.closing && depth == 0:
+			return i
+		case token.closing:
+			depth--
+		case !token.selfClosed:
+			depth++
+		}
+	}
+	return -1
+}
+
+// parseShortcodeTag parses a tag like {{< name arg key="value" >}}, {{< name />}} or {{< /name >}}
+func parseShortcodeTag(raw string) (shortcodeToken, error) {
+	token := shortcodeToken{raw: raw, args: map[string]string{}}
+
+	body := strings.TrimSpace(raw[len(shortcodeOpen) : len(raw)-len(shortcodeClose)])
+	if strings.HasPrefix(body, "/") {
+		token.closing = true
+		body = strings.TrimSpace(body[1:])
+	} else if strings.HasSuffix(body, "/") {
+		token.selfClosed = true
+		body = strings.TrimSpace(body[:len(body)-1])
+	}
+
+	fields, err := splitShortcodeArgs(body)
+	if err != nil {
+		return token, err
+	}
+	if len(fields) == 0 || !shortcodeName.MatchString(fields[0]) {
+		return token, errors.New("shortcode without a valid name")
+	}
+	token.name = fields[0]
+
+	if token.closing {
+		if len(fields) > 1 {
+			return token, fmt.Errorf("closing tag of %s with arguments", token.name)
+		}
+		return token, nil
+	}
+
+	for _, field := range fields[1:] {
+		key, value, found := strings.Cut(field, "=")
+		if found && shortcodeName.MatchString(key) {
+			value, err = unquoteShortcodeArg(value)
+			if err != nil {
+				return token, err
+			}
+			token.args[key] = value
+			continue
+		}
+
+		value, err = unquoteShortcodeArg(field)
+		if err != nil {
+			return token, err
+		}
+		token.positional = append(token.positional, value)
+	}
+	return token, nil
+}
+
+// splitShortcodeArgs splits the body of a tag by the spaces that are not quoted
+func splitShortcodeArgs(body string) ([]string, error) {
+	var fields []string
+	var field strings.Builder
+	quoted := false
+	escaped := false
+
+	for _, r := range body {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case !quoted && (r == ' ' || r == '\t' || r == '\n' || r == '\r'):
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+			continue
+		}
+		field.WriteRune(r)
+	}
+
+	if quoted {
+		return nil, errors.New("shortcode argument with an unterminated quote")
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields, nil
+}
+
+func unquoteShortcodeArg(value string) (string, error) {
+	if !strings.HasPrefix(value, `"`) {
+		if strings.Contains(value, `"`) {
+			return "", fmt.Errorf("invalid shortcode argument %s", value)
+		}
+		return value, nil
+	}
+
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid shortcode argument %s", value)
+	}
+	return unquoted, nil
+}
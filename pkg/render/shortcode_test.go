@@ -0,0 +1,234 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"html"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type testPostResolver map[uuid.UUID]PostLink
+
+func (resolver testPostResolver) ResolvePost(ctx context.Context, id uuid.UUID) (PostLink, error) {
+	link, ok := resolver[id]
+	if !ok {
+		return PostLink{}, ErrPostNotFound
+	}
+	return link, nil
+}
+
+func renderShortcodes(t *testing.T, registry *Registry, format string, source string) Document {
+	document, err := NewRenderer(registry).Render(context.Background(), format, source)
+	require.NoError(t, err)
+	return document
+}
+
+func TestShortcodeEmbeds(t *testing.T) {
+	registry := NewRegistry(nil)
+
+	testCases := []struct {
+		name     string
+		source   string
+		contains string
+	}{
+		{name: "YouTube", source: "{{< youtube dQw4w9WgXcQ >}}", contains: `<iframe src="https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ" title="YouTube video"`},
+		{name: "YouTubeNamed", source: `{{< youtube id="dQw4w9WgXcQ" title="A \"talk\"" >}}`, contains: `title="A &#34;talk&#34;"`},
+		{name: "Vimeo", source: "{{< vimeo 76979871 >}}", contains: `<iframe src="https://player.vimeo.com/video/76979871?dnt=1"`},
+		{name: "Gist", source: `{{< gist octocat 6cad326836d38bd3a7ae file="hello.go" >}}`, contains: `sandbox="allow-scripts allow-popups allow-popups-to-escape-sandbox"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			document := renderShortcodes(t, registry, FormatMarkdown, "Before\n\n"+tc.source+"\n\nAfter\n")
+			require.Contains(t, document.HTML, tc.contains)
+			require.NotContains(t, document.HTML, "{{&lt;")
+			require.NotContains(t, document.HTML, "<p><div")
+			require.Empty(t, document.Warnings)
+		})
+	}
+}
+
+func TestShortcodeGistScriptIsSandboxed(t *testing.T) {
+	document := renderShortcodes(t, NewRegistry(nil), FormatHTML, "{{< gist octocat 6cad326836d38bd3a7ae >}}")
+	require.NotContains(t, document.HTML, "<script")
+	require.Contains(t, html.UnescapeString(document.HTML), `<script src="https://gist.github.com/octocat/6cad326836d38bd3a7ae.js"></script>`)
+}
+
+func TestShortcodeCallout(t *testing.T) {
+	source := "{{< callout warning title=\"<Careful>\" >}}\n## Inside\n\nSome **text** {{< youtube dQw4w9WgXcQ >}}\n\n<script>alert(1)</script>\n{{< /callout >}}\n\n## Inside\n"
+	document := renderShortcodes(t, NewRegistry(nil), FormatMarkdown, source)
+
+	require.Contains(t, document.HTML, `<aside class="callout callout-warning" role="note"><p class="callout-title">&lt;Careful&gt;</p><div class="callout-body">`)
+	require.Contains(t, document.HTML, `<p>Some <strong>text</strong> <div class="shortcode-embed shortcode-youtube">`)
+	require.NotContains(t, document.HTML, "<script")
+	require.Empty(t, document.Warnings)
+
+	// The headings of the content share the anchors of the post
+	require.Contains(t, document.HTML, `<h2 id="inside">`)
+	require.Contains(t, document.HTML, `<h2 id="inside-1">`)
+	require.Equal(t, []Heading{{Level: 2, ID: "inside-1", Text: "Inside"}}, document.TOC)
+}
+
+func TestShortcodeFigure(t *testing.T) {
+	registry := NewRegistry(nil)
+
+	document := renderShortcodes(t, registry, FormatMarkdown, `{{< figure src="/images/a.png" alt="A <b>" caption="The caption" >}}`)
+	require.Equal(t, `<figure class="shortcode-figure"><img src="/images/a.png" alt="A &lt;b&gt;" loading="lazy"><figcaption>The caption</figcaption></figure>`+"\n", document.HTML)
+
+	document = renderShortcodes(t, registry, FormatMarkdown, `{{< figure src="https://example.com/a.png" alt="A" >}}A *rendered* caption{{< /figure >}}`)
+	require.Contains(t, document.HTML, `<figcaption>A <em>rendered</em> caption</figcaption>`)
+
+	document = renderShortcodes(t, registry, FormatMarkdown, `{{< figure src="javascript:alert(1)" alt="A" >}}`)
+	require.NotContains(t, document.HTML, "<figure")
+	require.Len(t, document.Warnings, 1)
+	require.Contains(t, document.Warnings[0].Message, "invalid image source")
+}
+
+func TestShortcodePost(t *testing.T) {
+	published := uuid.New()
+	draft := uuid.New()
+	registry := NewRegistry(testPostResolver{
+		published: {Title: "Hello <World>", URL: "https://blog.example.com/posts/hello-world", Published: true},
+		draft:     {Title: "Draft", URL: "https://blog.example.com/posts/draft"},
+	})
+
+	document := renderShortcodes(t, registry, FormatMarkdown, "Read {{< post "+published.String()+" >}}.\n")
+	require.Equal(t, "<p>Read <a href=\"https://blog.example.com/posts/hello-world\" class=\"shortcode-post\">Hello &lt;World&gt;</a>.</p>\n", document.HTML)
+	require.Empty(t, document.Warnings)
+
+	document = renderShortcodes(t, registry, FormatMarkdown, "{{< post "+published.String()+" >}}this *post*{{< /post >}}")
+	require.Contains(t, document.HTML, `class="shortcode-post">this <em>post</em></a>`)
+
+	// An unpublished post is neither linked nor named
+	document = renderShortcodes(t, registry, FormatMarkdown, "Read the {{< post "+draft.String()+" text=\"draft\" >}}.\n")
+	require.Equal(t, "<p>Read the draft.</p>\n", document.HTML)
+	require.Equal(t, []Warning{{Line: 1, Shortcode: "post", Message: "post " + draft.String() + " is not published"}}, document.Warnings)
+
+	document = renderShortcodes(t, registry, FormatMarkdown, "{{< post "+draft.String()+" >}}")
+	require.NotContains(t, document.HTML, "<a")
+	require.NotContains(t, document.HTML, "Draft")
+	require.Contains(t, document.HTML, "{{&lt; post "+draft.String()+" &gt;}}")
+	require.Len(t, document.Warnings, 1)
+	require.Contains(t, document.Warnings[0].Message, "is not published")
+
+	document = renderShortcodes(t, registry, FormatMarkdown, "{{< post "+uuid.NewString()+" >}}")
+	require.NotContains(t, document.HTML, "<a")
+	require.Len(t, document.Warnings, 1)
+	require.Contains(t, document.Warnings[0].Message, "not found")
+}
+
+func TestShortcodeWarnings(t *testing.T) {
+	source := "# Title\n\n{{< unknown a=\"<b>\" >}}\n\n{{< youtube bad >}}\n\n{{< /callout >}}\n\n{{< open\n"
+	document := renderShortcodes(t, NewRegistry(nil), FormatMarkdown, source)
+
+	require.Contains(t, document.HTML, `<p>{{&lt; unknown a=&#34;&lt;b&gt;&#34; &gt;}}</p>`)
+	require.Contains(t, document.HTML, `<p>{{&lt; youtube bad &gt;}}</p>`)
+	require.NotContains(t, document.HTML, "<iframe")
+	require.Equal(t, []Heading{{Level: 1, ID: "title", Text: "Title"}}, document.TOC)
+
+	require.Len(t, document.Warnings, 4)
+	require.Equal(t, Warning{Line: 3, Shortcode: "unknown", Message: "unknown shortcode"}, document.Warnings[0])
+	require.Equal(t, 5, document.Warnings[1].Line)
+	require.Contains(t, document.Warnings[1].Message, ErrInvalidShortcode.Error())
+	require.Equal(t, Warning{Line: 7, Shortcode: "callout", Message: "closing tag without an opening tag"}, document.Warnings[2])
+	require.Equal(t, Warning{Line: 9, Message: "shortcode is not closed"}, document.Warnings[3])
+}
+
+func TestShortcodeLiteral(t *testing.T) {
+	document := renderShortcodes(t, NewRegistry(nil), FormatMarkdown, "```\n{{</* youtube dQw4w9WgXcQ */>}}\n```\n")
+	require.Equal(t, "<pre><code>{{&lt; youtube dQw4w9WgXcQ &gt;}}\n</code></pre>\n", document.HTML)
+	require.Empty(t, document.Warnings)
+}
+
+func TestShortcodeInHeading(t *testing.T) {
+	registry := NewRegistry(nil)
+	source := "## Watch {{< youtube dQw4w9WgXcQ >}}\n"
+
+	document := renderShortcodes(t, registry, FormatMarkdown, source)
+	require.Equal(t, `<h2 id="watch-youtube-dqw4w9wgxcq">Watch {{&lt; youtube dQw4w9WgXcQ &gt;}}`+
+		`<a href="#watch-youtube-dqw4w9wgxcq" class="anchor">#</a></h2>`+"\n", document.HTML)
+	require.Equal(t, []Heading{{Level: 2, ID: "watch-youtube-dqw4w9wgxcq", Text: "Watch {{< youtube dQw4w9WgXcQ >}}"}}, document.TOC)
+	require.Equal(t, []Warning{{Line: 1, Shortcode: "youtube", Message: "shortcode can not be used in a heading, it is shown as text"}}, document.Warnings)
+
+	// The anchors do not change between renderings
+	require.Equal(t, document, renderShortcodes(t, registry, FormatMarkdown, source))
+}
+
+func TestShortcodeInLink(t *testing.T) {
+	id := uuid.New()
+	registry := NewRegistry(testPostResolver{
+		id: {Title: "Hello", URL: "https://blog.example.com/posts/hello", Published: true},
+	})
+
+	document := renderShortcodes(t, registry, FormatMarkdown, "[read]({{< post "+id.String()+" >}})\n")
+	require.Equal(t, `<p><a href="{{&lt; post `+id.String()+` &gt;}}">read</a></p>`+"\n", document.HTML)
+	require.Equal(t, []Warning{{Line: 1, Shortcode: "post", Message: "shortcode can not be used in an attribute, it is shown as text"}}, document.Warnings)
+
+	document = renderShortcodes(t, registry, FormatHTML, `<a href="{{< post `+id.String()+` >}}">read</a>`)
+	require.Equal(t, `<a href="{{&lt; post `+id.String()+` &gt;}}">read</a>`, document.HTML)
+	require.Len(t, document.Warnings, 1)
+
+	document = renderShortcodes(t, registry, FormatHTML, `<a href="https://example.com">{{< youtube dQw4w9WgXcQ >}}</a>`)
+	require.NotContains(t, document.HTML, "<iframe")
+	require.Contains(t, document.HTML, `>{{&lt; youtube dQw4w9WgXcQ &gt;}}</a>`)
+	require.Equal(t, []Warning{{Line: 1, Shortcode: "youtube", Message: "shortcode can not be used in a link, it is shown as text"}}, document.Warnings)
+}
+
+func TestShortcodeInCode(t *testing.T) {
+	registry := NewRegistry(nil)
+
+	document := renderShortcodes(t, registry, FormatMarkdown, "Use `{{< youtube dQw4w9WgXcQ >}}` to embed a video\n")
+	require.Equal(t, "<p>Use <code>{{&lt; youtube dQw4w9WgXcQ &gt;}}</code> to embed a video</p>\n", document.HTML)
+	require.Equal(t, []Warning{{Line: 1, Shortcode: "youtube", Message: "shortcode can not be used in code, it is shown as text"}}, document.Warnings)
+
+	document = renderShortcodes(t, registry, FormatHTML, `<p><code>{{< youtube dQw4w9WgXcQ >}}</code></p>`)
+	require.Equal(t, `<p><code>{{&lt; youtube dQw4w9WgXcQ &gt;}}</code></p>`, document.HTML)
+	require.Len(t, document.Warnings, 1)
+
+	// The paired shortcodes are shown with their source, not with their rendered content
+	document = renderShortcodes(t, registry, FormatMarkdown, "`{{< callout >}}**bold**{{< /callout >}}`\n")
+	require.Equal(t, "<p><code>{{&lt; callout &gt;}}**bold**{{&lt; /callout &gt;}}</code></p>\n", document.HTML)
+}
+
+func TestShortcodeCustom(t *testing.T) {
+	registry := NewRegistry(nil)
+	registry.Register("shout", ShortcodeFunc(func(ctx context.Context, call *Call) (string, error) {
+		if len(call.Positional) == 0 {
+			return "", invalidShortcode("nothing to shout")
+		}
+		return "<strong>" + html.EscapeString(call.Arg("text", 0)) + "!</strong>", nil
+	}))
+	require.Contains(t, registry.Names(), "shout")
+	require.NotContains(t, registry.Names(), "post")
+
+	document := renderShortcodes(t, registry, FormatHTML, `<p>{{< shout "<hey>" >}}</p>`)
+	require.Equal(t, `<p><strong>&lt;hey&gt;!</strong></p>`, document.HTML)
+
+	failing := errors.New("storage is down")
+	registry.Register("broken", ShortcodeFunc(func(ctx context.Context, call *Call) (string, error) {
+		return "", failing
+	}))
+	_, err := NewRenderer(registry).Render(context.Background(), FormatMarkdown, "{{< broken >}}")
+	require.ErrorIs(t, err, failing)
+}
+
+func TestParseShortcodeTag(t *testing.T) {
+	token, err := parseShortcodeTag(`{{< figure "a b.png" alt="A \"quoted\" alt" data-x=1 />}}`)
+	require.NoError(t, err)
+	require.Equal(t, "figure", token.name)
+	require.True(t, token.selfClosed)
+	require.Equal(t, []string{"a b.png"}, token.positional)
+	require.Equal(t, map[string]string{"alt": `A "quoted" alt`, "data-x": "1"}, token.args)
+
+	token, err = parseShortcodeTag(`{{< /figure >}}`)
+	require.NoError(t, err)
+	require.True(t, token.closing)
+
+	for _, raw := range []string{`{{< >}}`, `{{< 1name >}}`, `{{< name "open >}}`, `{{< name a"b >}}`, `{{< /name arg >}}`} {
+		_, err = parseShortcodeTag(raw)
+		require.Error(t, err, raw)
+	}
+}
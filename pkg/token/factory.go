@@ -0,0 +1,41 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"fmt"
+)
+
+// Token types that can be selected in the configuration
+const (
+	PasetoLocalType  = "paseto_local"
+	PasetoPublicType = "paseto_public"
+	JWTType          = "jwt"
+)
+
+// NewMaker creates the Maker of a token type. The key is the symmetric key for PASETO v2.local
+// and a PKCS #8 PEM private key for the asymmetric token types.
+func NewMaker(tokenType string, key string) (Maker, error) {
+	switch tokenType {
+	case "", PasetoLocalType:
+		return NewPasetoMaker(key)
+	case PasetoPublicType:
+		privateKey, err := ParsePrivateKeyPEM([]byte(key))
+		if err != nil {
+			return nil, err
+		}
+
+		ed25519Key, ok := privateKey.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("invalid private key: %s tokens need an Ed25519 key", PasetoPublicType)
+		}
+		return NewPasetoPublicMaker(ed25519Key)
+	case JWTType:
+		privateKey, err := ParsePrivateKeyPEM([]byte(key))
+		if err != nil {
+			return nil, err
+		}
+		return NewJWTMaker(privateKey)
+	default:
+		return nil, fmt.Errorf("unsupported token type %s", tokenType)
+	}
+}
@@ -0,0 +1,126 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// JWTMaker is a JSON Web Token maker, tokens are signed with EdDSA (Ed25519) or RS256 (RSA)
+type JWTMaker struct {
+	method     jwt.SigningMethod
+	privateKey crypto.Signer
+	keyID      string
+}
+
+type jwtClaims struct {
+	Role string `json:"role"`
+	Type Type   `json:"token_type"`
+	jwt.RegisteredClaims
+}
+
+// NewJWTMaker creates a new JWTMaker, the signing method depends on the type of the private key
+func NewJWTMaker(privateKey crypto.Signer) (PublicKeyMaker, error) {
+	var method jwt.SigningMethod
+	switch key := privateKey.(type) {
+	case ed25519.PrivateKey:
+		method = jwt.SigningMethodEdDSA
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("invalid key size: RSA keys must have at least 2048 bits")
+		}
+		method = jwt.SigningMethodRS256
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", privateKey)
+	}
+
+	maker := &JWTMaker{
+		method:     method,
+		privateKey: privateKey,
+	}
+	maker.keyID = newJSONWebKey(privateKey.Public(), method.Alg(), "").KeyID
+
+	return maker, nil
+}
+
+// CreateToken creates a new token for a specific username, role, type and duration
+func (maker *JWTMaker) CreateToken(username string, role string, tokenType Type, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, tokenType, duration)
+	if err != nil {
+		return "", payload, err
+	}
+
+	claims := jwtClaims{
+		Role: payload.Role,
+		Type: payload.Type,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        payload.ID.String(),
+			Subject:   payload.Username,
+			IssuedAt:  jwt.NewNumericDate(payload.IssuedAt),
+			ExpiresAt: jwt.NewNumericDate(payload.ExpiredAt),
+		},
+	}
+
+	jwtToken := jwt.NewWithClaims(maker.method, claims)
+	jwtToken.Header["kid"] = maker.keyID
+
+	token, err := jwtToken.SignedString(maker.privateKey)
+	return token, payload, err
+}
+
+// VerifyToken checks if the token is valid or not
+func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
+	claims := &jwtClaims{}
+
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return maker.privateKey.Public(), nil
+	}, jwt.WithValidMethods([]string{maker.method.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	payload := &Payload{
+		ID:        tokenID,
+		Username:  claims.Subject,
+		Role:      claims.Role,
+		Type:      claims.Type,
+		IssuedAt:  claims.IssuedAt.Time,
+		ExpiredAt: claims.ExpiresAt.Time,
+	}
+	return payload, nil
+}
+
+// PublicKeys returns the key that verifies the tokens
+func (maker *JWTMaker) PublicKeys() JSONWebKeySet {
+	return JSONWebKeySet{
+		Keys: []JSONWebKey{newJSONWebKey(maker.privateKey.Public(), maker.method.Alg(), maker.keyID)},
+	}
+}
+
+func (maker *JWTMaker) setKeyID(keyID string) {
+	maker.keyID = keyID
+}
+
+func (maker *JWTMaker) readKeyID(token string) (string, error) {
+	jwtToken, _, err := jwt.NewParser().ParseUnverified(token, &jwtClaims{})
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+
+	keyID, _ := jwtToken.Header["kid"].(string)
+	return keyID, nil
+}
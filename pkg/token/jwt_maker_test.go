@@ -0,0 +1,120 @@
+package token
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRSAKey(t *testing.T) *rsa.PrivateKey {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return privateKey
+}
+
+func TestJWTMaker(t *testing.T) {
+	testCases := []struct {
+		name    string
+		key     crypto.Signer
+		keyType string
+	}{
+		{name: "EdDSA", key: newTestEd25519Key(t), keyType: "OKP"},
+		{name: "RS256", key: newTestRSAKey(t), keyType: "RSA"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			maker, err := NewJWTMaker(tc.key)
+			require.NoError(t, err)
+
+			username := util.RandomString(6)
+			role := util.AdminRole
+			duration := time.Minute
+
+			issuedAt := time.Now()
+			expiredAt := issuedAt.Add(duration)
+
+			token, payload, err := maker.CreateToken(username, role, AccessToken, duration)
+			require.NoError(t, err)
+			require.NotEmpty(t, token)
+			require.NotEmpty(t, payload)
+
+			payload, err = maker.VerifyToken(token)
+			require.NoError(t, err)
+
+			require.NotZero(t, payload.ID)
+			require.Equal(t, username, payload.Username)
+			require.Equal(t, role, payload.Role)
+			require.Equal(t, AccessToken, payload.Type)
+			require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+			require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+
+			keys := maker.PublicKeys()
+			require.Len(t, keys.Keys, 1)
+			require.Equal(t, tc.name, keys.Keys[0].Algorithm)
+			require.Equal(t, tc.keyType, keys.Keys[0].KeyType)
+
+			// Expired tokens
+			token, _, err = maker.CreateToken(username, role, AccessToken, -time.Minute)
+			require.NoError(t, err)
+
+			payload, err = maker.VerifyToken(token)
+			require.EqualError(t, err, ErrExpiredToken.Error())
+			require.Nil(t, payload)
+		})
+	}
+}
+
+func TestJWTNoneAlgorithm(t *testing.T) {
+	maker, err := NewJWTMaker(newTestEd25519Key(t))
+	require.NoError(t, err)
+
+	payload, err := NewPayload(util.RandomString(6), util.AdminRole, AccessToken, time.Minute)
+	require.NoError(t, err)
+
+	claims := jwt.RegisteredClaims{
+		ID:        payload.ID.String(),
+		Subject:   payload.Username,
+		ExpiresAt: jwt.NewNumericDate(payload.ExpiredAt),
+	}
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, claims)
+	token, err := jwtToken.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	payload, err = maker.VerifyToken(token)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestNewMaker(t *testing.T) {
+	maker, err := NewMaker(PasetoLocalType, util.RandomString(32))
+	require.NoError(t, err)
+	require.IsType(t, &PasetoMaker{}, maker)
+
+	pemKey, err := EncodePrivateKeyPEM(newTestEd25519Key(t))
+	require.NoError(t, err)
+
+	maker, err = NewMaker(PasetoPublicType, string(pemKey))
+	require.NoError(t, err)
+	require.IsType(t, &PasetoPublicMaker{}, maker)
+
+	maker, err = NewMaker(JWTType, string(pemKey))
+	require.NoError(t, err)
+	require.IsType(t, &JWTMaker{}, maker)
+
+	pemKey, err = EncodePrivateKeyPEM(newTestRSAKey(t))
+	require.NoError(t, err)
+
+	_, err = NewMaker(PasetoPublicType, string(pemKey))
+	require.Error(t, err)
+
+	_, err = NewMaker("unknown", util.RandomString(32))
+	require.Error(t, err)
+}
@@ -0,0 +1,319 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aead/chacha20poly1305"
+)
+
+// KeyringKey is a key of the keyring
+type KeyringKey struct {
+	ID        string     `json:"id"`
+	Secret    string     `json:"secret"`
+	CreatedAt time.Time  `json:"created_at"`
+	RetiresAt *time.Time `json:"retires_at,omitempty"`
+}
+
+// Retired checks if the key can no longer verify tokens
+func (key KeyringKey) Retired(now time.Time) bool {
+	return key.RetiresAt != nil && !now.Before(*key.RetiresAt)
+}
+
+// Keyring is a set of keys of the same token type. The active key signs the new tokens,
+// the other keys still verify tokens until they retire.
+type Keyring struct {
+	TokenType   string       `json:"token_type"`
+	ActiveKeyID string       `json:"active_key_id"`
+	Keys        []KeyringKey `json:"keys"`
+}
+
+// LoadKeyring reads a keyring from a JSON file
+func LoadKeyring(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	keyring := &Keyring{}
+	if err := json.Unmarshal(data, keyring); err != nil {
+		return nil, fmt.Errorf("invalid keyring file: %w", err)
+	}
+	return keyring, nil
+}
+
+// Save writes the keyring to a JSON file that only the owner can read
+func (keyring *Keyring) Save(path string) error {
+	data, err := json.MarshalIndent(keyring, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".keyring-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// ActiveKey returns the key that signs the new tokens
+func (keyring *Keyring) ActiveKey() (KeyringKey, error) {
+	for _, key := range keyring.Keys {
+		if key.ID == keyring.ActiveKeyID {
+			return key, nil
+		}
+	}
+	return KeyringKey{}, fmt.Errorf("active key %s is not in the keyring", keyring.ActiveKeyID)
+}
+
+// AddKey adds a key to the keyring without promoting it
+func (keyring *Keyring) AddKey(secret string) (KeyringKey, error) {
+	keyID, err := newKeyID()
+	if err != nil {
+		return KeyringKey{}, err
+	}
+
+	key := KeyringKey{
+		ID:        keyID,
+		Secret:    secret,
+		CreatedAt: time.Now().UTC(),
+	}
+	keyring.Keys = append(keyring.Keys, key)
+	return key, nil
+}
+
+// NewKey generates a new key and adds it without promoting it, the servers verify its tokens
+// once they load the keyring again. When several servers share the keyring it is promoted
+// after all of them have it, otherwise a server would reject the tokens signed by the others.
+func (keyring *Keyring) NewKey() (KeyringKey, error) {
+	useRSA := false
+	if previous, err := keyring.ActiveKey(); err == nil && keyring.TokenType == JWTType {
+		privateKey, err := ParsePrivateKeyPEM([]byte(previous.Secret))
+		if err == nil {
+			_, useRSA = privateKey.(*rsa.PrivateKey)
+		}
+	}
+
+	secret, err := GenerateKey(keyring.TokenType, useRSA)
+	if err != nil {
+		return KeyringKey{}, err
+	}
+
+	return keyring.AddKey(secret)
+}
+
+// Promote makes a key of the keyring the active one. The previous active key retires after
+// retireAfter, that should be the lifetime of the longest lived token. Retired keys are removed.
+func (keyring *Keyring) Promote(keyID string, retireAfter time.Duration) (KeyringKey, error) {
+	now := time.Now().UTC()
+
+	var promoted *KeyringKey
+	for i := range keyring.Keys {
+		if keyring.Keys[i].ID == keyID {
+			promoted = &keyring.Keys[i]
+		}
+	}
+	if promoted == nil {
+		return KeyringKey{}, fmt.Errorf("key %s is not in the keyring", keyID)
+	}
+	if promoted.Retired(now) {
+		return KeyringKey{}, fmt.Errorf("key %s is retired", keyID)
+	}
+	if keyID == keyring.ActiveKeyID {
+		return KeyringKey{}, fmt.Errorf("key %s is already the active key", keyID)
+	}
+	// The key signs again, so it must not retire
+	promoted.RetiresAt = nil
+
+	retiresAt := now.Add(retireAfter)
+	keys := make([]KeyringKey, 0, len(keyring.Keys))
+	var key KeyringKey
+	for _, k := range keyring.Keys {
+		if k.ID == keyring.ActiveKeyID && k.RetiresAt == nil {
+			k.RetiresAt = &retiresAt
+		}
+		if k.Retired(now) {
+			continue
+		}
+		if k.ID == keyID {
+			key = k
+		}
+		keys = append(keys, k)
+	}
+
+	keyring.Keys = keys
+	keyring.ActiveKeyID = keyID
+	return key, nil
+}
+
+// Rotate generates a new key and promotes it at once, only safe when a single server uses the keyring
+func (keyring *Keyring) Rotate(retireAfter time.Duration) (KeyringKey, error) {
+	key, err := keyring.NewKey()
+	if err != nil {
+		return KeyringKey{}, err
+	}
+	return keyring.Promote(key.ID, retireAfter)
+}
+
+// GenerateKey generates a new secret for a token type: a symmetric key for PASETO v2.local
+// and an Ed25519 or RSA private key in PKCS #8 PEM format for the asymmetric types
+func GenerateKey(tokenType string, useRSA bool) (string, error) {
+	switch tokenType {
+	case "", PasetoLocalType:
+		// base64 keeps the key printable, 24 random bytes are exactly KeySize characters
+		buf := make([]byte, chacha20poly1305.KeySize*3/4)
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		return base64.RawURLEncoding.EncodeToString(buf), nil
+	case PasetoPublicType, JWTType:
+		if useRSA {
+			privateKey, err := rsa.GenerateKey(rand.Reader, 3072)
+			if err != nil {
+				return "", err
+			}
+			pemKey, err := EncodePrivateKeyPEM(privateKey)
+			return string(pemKey), err
+		}
+
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return "", err
+		}
+		pemKey, err := EncodePrivateKeyPEM(privateKey)
+		return string(pemKey), err
+	default:
+		return "", fmt.Errorf("unsupported token type %s", tokenType)
+	}
+}
+
+func newKeyID() (string, error) {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// KeyringMaker is a token maker that signs with the active key of a keyring
+// and verifies with the key whose ID is carried in the token
+type KeyringMaker struct {
+	activeKeyID string
+	makers      map[string]keyedMaker
+	keys        []KeyringKey
+}
+
+// NewKeyringMaker creates a new KeyringMaker
+func NewKeyringMaker(keyring *Keyring) (PublicKeyMaker, error) {
+	if len(keyring.Keys) == 0 {
+		return nil, errors.New("the keyring has no keys")
+	}
+
+	active, err := keyring.ActiveKey()
+	if err != nil {
+		return nil, err
+	}
+	if active.Retired(time.Now()) {
+		return nil, fmt.Errorf("active key %s is retired", active.ID)
+	}
+
+	maker := &KeyringMaker{
+		activeKeyID: active.ID,
+		makers:      make(map[string]keyedMaker, len(keyring.Keys)),
+		keys:        keyring.Keys,
+	}
+
+	for _, key := range keyring.Keys {
+		keyMaker, err := NewMaker(keyring.TokenType, key.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %s: %w", key.ID, err)
+		}
+
+		keyed := keyMaker.(keyedMaker)
+		keyed.setKeyID(key.ID)
+		maker.makers[key.ID] = keyed
+	}
+
+	return maker, nil
+}
+
+// CreateToken creates a new token with the active key
+func (maker *KeyringMaker) CreateToken(username string, role string, tokenType Type, duration time.Duration) (string, *Payload, error) {
+	return maker.makers[maker.activeKeyID].CreateToken(username, role, tokenType, duration)
+}
+
+// VerifyToken checks if the token is valid or not with the key that created it
+func (maker *KeyringMaker) VerifyToken(token string) (*Payload, error) {
+	keyID, err := maker.makers[maker.activeKeyID].readKeyID(token)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	// Tokens created before the keyring was introduced have no key ID
+	if len(keyID) == 0 {
+		verifyErr := ErrInvalidToken
+		for _, key := range maker.keys {
+			if key.Retired(now) {
+				continue
+			}
+
+			payload, err := maker.makers[key.ID].VerifyToken(token)
+			if err == nil {
+				return payload, nil
+			}
+			if errors.Is(err, ErrExpiredToken) {
+				verifyErr = err
+			}
+		}
+		return nil, verifyErr
+	}
+
+	for _, key := range maker.keys {
+		if key.ID == keyID && !key.Retired(now) {
+			return maker.makers[key.ID].VerifyToken(token)
+		}
+	}
+	return nil, ErrInvalidToken
+}
+
+// PublicKeys returns the keys that are not retired, empty for symmetric keys
+func (maker *KeyringMaker) PublicKeys() JSONWebKeySet {
+	set := JSONWebKeySet{Keys: []JSONWebKey{}}
+
+	now := time.Now()
+	for _, key := range maker.keys {
+		if key.Retired(now) {
+			continue
+		}
+
+		if publicKeyMaker, ok := maker.makers[key.ID].(PublicKeyMaker); ok {
+			set.Keys = append(set.Keys, publicKeyMaker.PublicKeys().Keys...)
+		}
+	}
+	return set
+}
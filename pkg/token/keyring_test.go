@@ -0,0 +1,172 @@
+package token
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeyring(t *testing.T, tokenType string) *Keyring {
+	keyring := &Keyring{TokenType: tokenType}
+	_, err := keyring.Rotate(time.Hour)
+	require.NoError(t, err)
+	return keyring
+}
+
+func TestKeyringRotation(t *testing.T) {
+	for _, tokenType := range []string{PasetoLocalType, PasetoPublicType, JWTType} {
+		t.Run(tokenType, func(t *testing.T) {
+			keyring := newTestKeyring(t, tokenType)
+
+			oldMaker, err := NewKeyringMaker(keyring)
+			require.NoError(t, err)
+
+			username := util.RandomString(6)
+			oldToken, _, err := oldMaker.CreateToken(username, util.AuthorRole, AccessToken, time.Minute)
+			require.NoError(t, err)
+
+			oldKeyID := keyring.ActiveKeyID
+			newKey, err := keyring.Rotate(time.Hour)
+			require.NoError(t, err)
+			require.Equal(t, newKey.ID, keyring.ActiveKeyID)
+			require.NotEqual(t, oldKeyID, newKey.ID)
+			require.Len(t, keyring.Keys, 2)
+
+			maker, err := NewKeyringMaker(keyring)
+			require.NoError(t, err)
+
+			// Tokens of the previous key are valid until it retires
+			payload, err := maker.VerifyToken(oldToken)
+			require.NoError(t, err)
+			require.Equal(t, username, payload.Username)
+
+			newToken, _, err := maker.CreateToken(username, util.AuthorRole, AccessToken, time.Minute)
+			require.NoError(t, err)
+
+			keyID, err := maker.(*KeyringMaker).makers[oldKeyID].readKeyID(newToken)
+			require.NoError(t, err)
+			require.Equal(t, newKey.ID, keyID)
+
+			payload, err = maker.VerifyToken(newToken)
+			require.NoError(t, err)
+			require.Equal(t, username, payload.Username)
+
+			// The old maker does not know the new key
+			_, err = oldMaker.VerifyToken(newToken)
+			require.EqualError(t, err, ErrInvalidToken.Error())
+
+			if tokenType == PasetoLocalType {
+				require.Empty(t, maker.PublicKeys().Keys)
+			} else {
+				require.Len(t, maker.PublicKeys().Keys, 2)
+			}
+
+			// Retire the old key
+			retired := time.Now().Add(-time.Second)
+			keyring.Keys[0].RetiresAt = &retired
+
+			maker, err = NewKeyringMaker(keyring)
+			require.NoError(t, err)
+
+			_, err = maker.VerifyToken(oldToken)
+			require.EqualError(t, err, ErrInvalidToken.Error())
+
+			_, err = keyring.Rotate(time.Hour)
+			require.NoError(t, err)
+			require.Len(t, keyring.Keys, 2)
+			require.NotEqual(t, oldKeyID, keyring.Keys[0].ID)
+		})
+	}
+}
+
+func TestKeyringAddAndPromote(t *testing.T) {
+	keyring := newTestKeyring(t, PasetoPublicType)
+	oldKeyID := keyring.ActiveKeyID
+
+	key, err := keyring.NewKey()
+	require.NoError(t, err)
+	require.Equal(t, oldKeyID, keyring.ActiveKeyID)
+	require.Len(t, keyring.Keys, 2)
+	require.Nil(t, key.RetiresAt)
+
+	// A server that loaded the keyring after the key was added still signs with the old key
+	addedMaker, err := NewKeyringMaker(keyring)
+	require.NoError(t, err)
+	username := util.RandomString(6)
+	oldToken, _, err := addedMaker.CreateToken(username, util.AuthorRole, AccessToken, time.Minute)
+	require.NoError(t, err)
+
+	_, err = keyring.Promote("unknown", time.Hour)
+	require.Error(t, err)
+	_, err = keyring.Promote(oldKeyID, time.Hour)
+	require.Error(t, err)
+
+	promoted, err := keyring.Promote(key.ID, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, key.ID, promoted.ID)
+	require.Equal(t, key.ID, keyring.ActiveKeyID)
+	require.NotNil(t, keyring.Keys[0].RetiresAt)
+
+	promotedMaker, err := NewKeyringMaker(keyring)
+	require.NoError(t, err)
+	newToken, _, err := promotedMaker.CreateToken(username, util.AuthorRole, AccessToken, time.Minute)
+	require.NoError(t, err)
+
+	// Both servers verify the tokens of each other while the promotion rolls out
+	_, err = addedMaker.VerifyToken(newToken)
+	require.NoError(t, err)
+	_, err = promotedMaker.VerifyToken(oldToken)
+	require.NoError(t, err)
+}
+
+func TestKeyringTokenWithoutKeyID(t *testing.T) {
+	key := util.RandomString(32)
+	legacyMaker, err := NewPasetoMaker(key)
+	require.NoError(t, err)
+
+	legacyToken, _, err := legacyMaker.CreateToken(util.RandomString(6), util.AuthorRole, AccessToken, time.Minute)
+	require.NoError(t, err)
+
+	keyring := &Keyring{TokenType: PasetoLocalType}
+	imported, err := keyring.AddKey(key)
+	require.NoError(t, err)
+	keyring.ActiveKeyID = imported.ID
+
+	_, err = keyring.Rotate(time.Hour)
+	require.NoError(t, err)
+
+	maker, err := NewKeyringMaker(keyring)
+	require.NoError(t, err)
+
+	_, err = maker.VerifyToken(legacyToken)
+	require.NoError(t, err)
+}
+
+func TestKeyringSaveAndLoad(t *testing.T) {
+	keyring := newTestKeyring(t, JWTType)
+	path := filepath.Join(t.TempDir(), "keyring.json")
+
+	err := keyring.Save(path)
+	require.NoError(t, err)
+
+	loaded, err := LoadKeyring(path)
+	require.NoError(t, err)
+	require.Equal(t, keyring.TokenType, loaded.TokenType)
+	require.Equal(t, keyring.ActiveKeyID, loaded.ActiveKeyID)
+	require.Len(t, loaded.Keys, 1)
+	require.Equal(t, keyring.Keys[0].Secret, loaded.Keys[0].Secret)
+}
+
+func TestKeyringWithoutActiveKey(t *testing.T) {
+	_, err := NewKeyringMaker(&Keyring{TokenType: PasetoLocalType})
+	require.Error(t, err)
+
+	keyring := newTestKeyring(t, PasetoLocalType)
+	keyring.ActiveKeyID = "unknown"
+
+	_, err = NewKeyringMaker(keyring)
+	require.Error(t, err)
+}
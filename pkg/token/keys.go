@@ -0,0 +1,118 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// JSONWebKey is the public part of a signing key in the JWK format (RFC 7517)
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// JSONWebKeySet is a set of public keys that verify tokens
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// PublicKeyMaker is a Maker whose tokens can be verified by other services with its public keys
+type PublicKeyMaker interface {
+	Maker
+
+	// PublicKeys returns the keys that verify the tokens of the maker
+	PublicKeys() JSONWebKeySet
+}
+
+// ParsePrivateKeyPEM parses an Ed25519 or RSA private key in PKCS #8 PEM format
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid private key: no PEM block found")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	switch key := key.(type) {
+	case ed25519.PrivateKey:
+		return key, nil
+	case *rsa.PrivateKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+}
+
+// EncodePrivateKeyPEM encodes a private key in PKCS #8 PEM format
+func EncodePrivateKeyPEM(key crypto.Signer) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// newJSONWebKey returns the JWK of a public key, without a key ID the thumbprint of the key is used
+func newJSONWebKey(publicKey crypto.PublicKey, algorithm string, keyID string) JSONWebKey {
+	jwk := JSONWebKey{
+		Use:       "sig",
+		Algorithm: algorithm,
+	}
+
+	switch key := publicKey.(type) {
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(key)
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	}
+
+	jwk.KeyID = keyID
+	if len(jwk.KeyID) == 0 {
+		jwk.KeyID = jwkThumbprint(jwk)
+	}
+	return jwk
+}
+
+// jwkThumbprint computes the RFC 7638 thumbprint of a key, used as its default key ID
+func jwkThumbprint(jwk JSONWebKey) string {
+	var members []byte
+	switch jwk.KeyType {
+	case "OKP":
+		members, _ = json.Marshal(struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Curve, jwk.KeyType, jwk.X})
+	case "RSA":
+		members, _ = json.Marshal(struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.KeyType, jwk.N})
+	}
+
+	sum := sha256.Sum256(members)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -4,9 +4,24 @@ import "time"
 
 // Maker is an interface for managing tokens
 type Maker interface {
-	// CreateToken creates a new token for a specific username and duration
-	CreateToken(username string, duration time.Duration) (string, *Payload, error)
+	// CreateToken creates a new token for a specific username, role, type and duration
+	CreateToken(username string, role string, tokenType Type, duration time.Duration) (string, *Payload, error)
 
 	// VerifyToken checks if the token is valid or not
 	VerifyToken(token string) (*Payload, error)
 }
+
+// keyedMaker is a Maker whose tokens carry the ID of the key that created them
+type keyedMaker interface {
+	Maker
+
+	setKeyID(keyID string)
+
+	// readKeyID returns the key ID of a token before verifying it, empty if the token has none
+	readKeyID(token string) (string, error)
+}
+
+// tokenFooter is the PASETO footer with the ID of the key that created the token
+type tokenFooter struct {
+	KeyID string `json:"kid"`
+}
@@ -0,0 +1,38 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// PersonalAccessTokenPrefix identifies the personal access tokens among other bearer tokens
+const PersonalAccessTokenPrefix = "pbt_"
+
+const opaqueTokenSize = 32
+
+// NewOpaqueToken creates a new random token with a specific prefix.
+// It returns the token, that is only shown once, and the hash that must be stored instead.
+func NewOpaqueToken(prefix string) (string, string, error) {
+	buf := make([]byte, opaqueTokenSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("cannot generate token: %w", err)
+	}
+
+	token := prefix + base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashOpaqueToken(token), nil
+}
+
+// HashOpaqueToken returns the hash of an opaque token used to look it up
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsPersonalAccessToken checks if the token looks like a personal access token
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
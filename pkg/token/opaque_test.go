@@ -0,0 +1,23 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpaqueToken(t *testing.T) {
+	token, hash, err := NewOpaqueToken(PersonalAccessTokenPrefix)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.True(t, IsPersonalAccessToken(token))
+	require.Equal(t, hash, HashOpaqueToken(token))
+	require.NotContains(t, hash, token)
+
+	otherToken, otherHash, err := NewOpaqueToken(PersonalAccessTokenPrefix)
+	require.NoError(t, err)
+	require.NotEqual(t, token, otherToken)
+	require.NotEqual(t, hash, otherHash)
+
+	require.False(t, IsPersonalAccessToken("v2.local.token"))
+}
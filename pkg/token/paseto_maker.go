@@ -12,6 +12,7 @@ import (
 type PasetoMaker struct {
 	paseto       *paseto.V2
 	symmetricKey []byte
+	keyID        string
 }
 
 // NewPasetoMaker creates a new PasetoMaker
@@ -28,14 +29,19 @@ func NewPasetoMaker(symmetricKey string) (Maker, error) {
 	return maker, nil
 }
 
-// CreateToken creates a new token for a specific username and duration
-func (maker *PasetoMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, duration)
+// CreateToken creates a new token for a specific username, role, type and duration
+func (maker *PasetoMaker) CreateToken(username string, role string, tokenType Type, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, tokenType, duration)
 	if err != nil {
 		return "", payload, err
 	}
 
-	token, err := maker.paseto.Encrypt(maker.symmetricKey, payload, nil)
+	var footer interface{}
+	if len(maker.keyID) > 0 {
+		footer = tokenFooter{KeyID: maker.keyID}
+	}
+
+	token, err := maker.paseto.Encrypt(maker.symmetricKey, payload, footer)
 	return token, payload, err
 }
 
@@ -55,3 +61,15 @@ func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
 
 	return payload, nil
 }
+
+func (maker *PasetoMaker) setKeyID(keyID string) {
+	maker.keyID = keyID
+}
+
+func (maker *PasetoMaker) readKeyID(token string) (string, error) {
+	footer := tokenFooter{}
+	if err := paseto.ParseFooter(token, &footer); err != nil {
+		return "", ErrInvalidToken
+	}
+	return footer.KeyID, nil
+}
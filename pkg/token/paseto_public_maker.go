@@ -0,0 +1,118 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+)
+
+// PasetoPublicAlgorithm is the algorithm announced in the public keys of the PASETO v4.public maker
+const PasetoPublicAlgorithm = "v4.public"
+
+// PasetoPublicMaker is a PASETO v4.public token maker, tokens are signed with an Ed25519 key
+type PasetoPublicMaker struct {
+	secretKey paseto.V4AsymmetricSecretKey
+	publicKey paseto.V4AsymmetricPublicKey
+	parser    paseto.Parser
+	keyID     string
+}
+
+// NewPasetoPublicMaker creates a new PasetoPublicMaker
+func NewPasetoPublicMaker(privateKey ed25519.PrivateKey) (PublicKeyMaker, error) {
+	secretKey, err := paseto.NewV4AsymmetricSecretKeyFromEd25519(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	maker := &PasetoPublicMaker{
+		secretKey: secretKey,
+		publicKey: secretKey.Public(),
+		// The expiration is checked by Payload.Valid to return ErrExpiredToken
+		parser: paseto.NewParserWithoutExpiryCheck(),
+	}
+
+	return maker, nil
+}
+
+// CreateToken creates a new token for a specific username, role, type and duration
+func (maker *PasetoPublicMaker) CreateToken(username string, role string, tokenType Type, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, tokenType, duration)
+	if err != nil {
+		return "", payload, err
+	}
+
+	claims, err := json.Marshal(payload)
+	if err != nil {
+		return "", payload, err
+	}
+
+	token, err := paseto.NewTokenFromClaimsJSON(claims, nil)
+	if err != nil {
+		return "", payload, err
+	}
+	token.SetJti(payload.ID.String())
+	token.SetSubject(payload.Username)
+	token.SetIssuedAt(payload.IssuedAt)
+	token.SetExpiration(payload.ExpiredAt)
+
+	if len(maker.keyID) > 0 {
+		footer, err := json.Marshal(tokenFooter{KeyID: maker.keyID})
+		if err != nil {
+			return "", payload, err
+		}
+		token.SetFooter(footer)
+	}
+
+	return token.V4Sign(maker.secretKey, nil), payload, nil
+}
+
+// VerifyToken checks if the token is valid or not
+func (maker *PasetoPublicMaker) VerifyToken(token string) (*Payload, error) {
+	parsed, err := maker.parser.ParseV4Public(maker.publicKey, token, nil)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	payload := &Payload{}
+	err = json.Unmarshal(parsed.ClaimsJSON(), payload)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	err = payload.Valid()
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// PublicKeys returns the Ed25519 key that verifies the tokens
+func (maker *PasetoPublicMaker) PublicKeys() JSONWebKeySet {
+	publicKey := ed25519.PublicKey(maker.publicKey.ExportBytes())
+	return JSONWebKeySet{
+		Keys: []JSONWebKey{newJSONWebKey(publicKey, PasetoPublicAlgorithm, maker.keyID)},
+	}
+}
+
+func (maker *PasetoPublicMaker) setKeyID(keyID string) {
+	maker.keyID = keyID
+}
+
+func (maker *PasetoPublicMaker) readKeyID(token string) (string, error) {
+	data, err := maker.parser.UnsafeParseFooter(paseto.V4Public, token)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	if len(data) == 0 {
+		return "", nil
+	}
+
+	footer := tokenFooter{}
+	if err := json.Unmarshal(data, &footer); err != nil {
+		return "", ErrInvalidToken
+	}
+	return footer.KeyID, nil
+}
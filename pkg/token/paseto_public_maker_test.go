@@ -0,0 +1,82 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/JairoRiver/personal_blog_backend/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEd25519Key(t *testing.T) ed25519.PrivateKey {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return privateKey
+}
+
+func TestPasetoPublicMaker(t *testing.T) {
+	maker, err := NewPasetoPublicMaker(newTestEd25519Key(t))
+	require.NoError(t, err)
+
+	username := util.RandomString(6)
+	role := util.EditorRole
+	duration := time.Minute
+
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(duration)
+
+	token, payload, err := maker.CreateToken(username, role, AccessToken, duration)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, payload)
+	require.Contains(t, token, "v4.public.")
+
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+
+	require.NotZero(t, payload.ID)
+	require.Equal(t, username, payload.Username)
+	require.Equal(t, role, payload.Role)
+	require.Equal(t, AccessToken, payload.Type)
+	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+
+	keys := maker.PublicKeys()
+	require.Len(t, keys.Keys, 1)
+	require.Equal(t, "OKP", keys.Keys[0].KeyType)
+	require.Equal(t, "Ed25519", keys.Keys[0].Curve)
+	require.NotEmpty(t, keys.Keys[0].KeyID)
+}
+
+func TestExpiredPasetoPublicToken(t *testing.T) {
+	maker, err := NewPasetoPublicMaker(newTestEd25519Key(t))
+	require.NoError(t, err)
+
+	token, payload, err := maker.CreateToken(util.RandomString(6), util.AuthorRole, AccessToken, -time.Minute)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.NotEmpty(t, payload)
+
+	payload, err = maker.VerifyToken(token)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestPasetoPublicTokenWrongKey(t *testing.T) {
+	maker, err := NewPasetoPublicMaker(newTestEd25519Key(t))
+	require.NoError(t, err)
+
+	otherMaker, err := NewPasetoPublicMaker(newTestEd25519Key(t))
+	require.NoError(t, err)
+
+	token, _, err := otherMaker.CreateToken(util.RandomString(6), util.AuthorRole, AccessToken, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.Error(t, err)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
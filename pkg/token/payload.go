@@ -13,16 +13,26 @@ var (
 	ErrExpiredToken = errors.New("token has expired")
 )
 
+// Type tells what a token can be used for, so a refresh token is not accepted as an access token
+type Type string
+
+const (
+	AccessToken  Type = "access"
+	RefreshToken Type = "refresh"
+)
+
 // Payload contains the payload data of the token
 type Payload struct {
 	ID        uuid.UUID `json:"id"`
 	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	Type      Type      `json:"type"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
 }
 
-// NewPayload creates a new token payload with a specific username and duration
-func NewPayload(username string, duration time.Duration) (*Payload, error) {
+// NewPayload creates a new token payload with a specific username, role, type and duration
+func NewPayload(username string, role string, tokenType Type, duration time.Duration) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -31,6 +41,8 @@ func NewPayload(username string, duration time.Duration) (*Payload, error) {
 	payload := &Payload{
 		ID:        tokenID,
 		Username:  username,
+		Role:      role,
+		Type:      tokenType,
 		IssuedAt:  time.Now(),
 		ExpiredAt: time.Now().Add(duration),
 	}
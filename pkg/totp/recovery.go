@@ -0,0 +1,40 @@
+package totp
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+)
+
+// RecoveryCodeCount is the number of recovery codes generated for a user
+const RecoveryCodeCount = 10
+
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// GenerateRecoveryCodes generates one-time codes that replace a TOTP code when the
+// authenticator is lost, formatted as xxxxx-xxxxx
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		buf := make([]byte, 10)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("cannot generate recovery code: %w", err)
+		}
+
+		var sb strings.Builder
+		for j, b := range buf {
+			if j == 5 {
+				sb.WriteByte('-')
+			}
+			// The modulo bias of 256 over 31 characters is negligible here
+			sb.WriteByte(recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)])
+		}
+		codes = append(codes, sb.String())
+	}
+	return codes, nil
+}
+
+// NormalizeRecoveryCode lowercases a recovery code typed by a user and removes its spaces
+func NormalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.Join(strings.Fields(code), ""))
+}
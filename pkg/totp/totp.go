@@ -0,0 +1,113 @@
+// Package totp implements the time-based one-time passwords of RFC 6238
+// that authenticator apps generate.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// Period is the time step of the codes
+	Period = 30 * time.Second
+	// Digits is the length of the codes
+	Digits = 6
+	// skew is the number of steps before and after the current one that are accepted
+	// to tolerate clock drift
+	skew = 1
+
+	secretSize = 20
+)
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret generates a new random secret encoded in base32
+func GenerateSecret() (string, error) {
+	buf := make([]byte, secretSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("cannot generate secret: %w", err)
+	}
+	return secretEncoding.EncodeToString(buf), nil
+}
+
+// ProvisioningURI returns the otpauth URI that authenticator apps read from a QR code
+func ProvisioningURI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer + ":" + account)
+
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(Digits))
+	params.Set("period", fmt.Sprint(int(Period.Seconds())))
+
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// Step returns the time step of t
+func Step(t time.Time) int64 {
+	return t.Unix() / int64(Period.Seconds())
+}
+
+// GenerateCode returns the code of a secret at time t
+func GenerateCode(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return generateCode(key, uint64(Step(t)), Digits), nil
+}
+
+// Validate checks a code against the secret at time t, it returns the step the code belongs to
+// so the caller can reject codes that were already used
+func Validate(secret, code string, t time.Time) (int64, bool) {
+	key, err := decodeSecret(secret)
+	if err != nil || len(code) != Digits {
+		return 0, false
+	}
+
+	current := Step(t)
+	for step := current - skew; step <= current+skew; step++ {
+		expected := generateCode(key, uint64(step), Digits)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	key, err := secretEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret: %w", err)
+	}
+	return key, nil
+}
+
+// generateCode is the HOTP algorithm of RFC 4226 with HMAC-SHA1
+func generateCode(key []byte, counter uint64, digits int) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
@@ -0,0 +1,88 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Test vectors of RFC 6238 appendix B for HMAC-SHA1
+func TestRFC6238Vectors(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	testCases := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "94287082"},
+		{unix: 1111111109, code: "07081804"},
+		{unix: 1111111111, code: "14050471"},
+		{unix: 1234567890, code: "89005924"},
+		{unix: 2000000000, code: "69279037"},
+		{unix: 20000000000, code: "65353130"},
+	}
+
+	for _, tc := range testCases {
+		step := Step(time.Unix(tc.unix, 0))
+		require.Equal(t, tc.code, generateCode(key, uint64(step), 8))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, 32)
+
+	now := time.Now()
+	code, err := GenerateCode(secret, now)
+	require.NoError(t, err)
+	require.Len(t, code, Digits)
+
+	step, ok := Validate(secret, code, now)
+	require.True(t, ok)
+	require.Equal(t, Step(now), step)
+
+	// One step of clock drift is accepted
+	step, ok = Validate(secret, code, now.Add(Period))
+	require.True(t, ok)
+	require.Equal(t, Step(now), step)
+
+	_, ok = Validate(secret, code, now.Add(3*Period))
+	require.False(t, ok)
+
+	_, ok = Validate(secret, "12345", now)
+	require.False(t, ok)
+
+	_, ok = Validate("not base32!", code, now)
+	require.False(t, ok)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Personal Blog", "admin", "JBSWY3DPEHPK3PXP")
+
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", parsed.Scheme)
+	require.Equal(t, "totp", parsed.Host)
+	require.Equal(t, "/Personal Blog:admin", parsed.Path)
+	require.Equal(t, "JBSWY3DPEHPK3PXP", parsed.Query().Get("secret"))
+	require.Equal(t, "Personal Blog", parsed.Query().Get("issuer"))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(RecoveryCodeCount)
+	require.NoError(t, err)
+	require.Len(t, codes, RecoveryCodeCount)
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		require.Len(t, code, 11)
+		require.Equal(t, byte('-'), code[5])
+		require.False(t, seen[code])
+		seen[code] = true
+	}
+
+	require.Equal(t, "abcde-fghjk", NormalizeRecoveryCode(" ABCDE-FGHJK "))
+}
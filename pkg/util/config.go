@@ -9,19 +9,73 @@ import (
 // Config stores all configuration of the application
 // The values are read by viper from a config file or enviroment variable.
 type Config struct {
+	Environment          string        `mapstructure:"ENVIRONMENT"`
 	DBDriver             string        `mapstructure:"DB_DRIVER"`
 	DBSource             string        `mapstructure:"DB_SOURCE"`
 	ServerAddress        string        `mapstructure:"SERVER_ADDRESS"`
+	TrustedProxies       []string      `mapstructure:"TRUSTED_PROXIES"`
+	AdminAllowedCIDRs    []string      `mapstructure:"ADMIN_ALLOWED_CIDRS"`
+	AdminServerAddress   string        `mapstructure:"ADMIN_SERVER_ADDRESS"`
+	AdminTLSCertFile     string        `mapstructure:"ADMIN_TLS_CERT_FILE"`
+	AdminTLSKeyFile      string        `mapstructure:"ADMIN_TLS_KEY_FILE"`
+	AdminClientCAFile    string        `mapstructure:"ADMIN_CLIENT_CA_FILE"`
+	TokenType            string        `mapstructure:"TOKEN_TYPE"`
 	TokenSymmetricKey    string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	TokenPrivateKeyFile  string        `mapstructure:"TOKEN_PRIVATE_KEY_FILE"`
+	TokenKeyringFile     string        `mapstructure:"TOKEN_KEYRING_FILE"`
 	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	SessionSweepInterval time.Duration `mapstructure:"SESSION_SWEEP_INTERVAL"`
+	WebAuthnRPID         string        `mapstructure:"WEBAUTHN_RP_ID"`
+	WebAuthnRPOrigins    []string      `mapstructure:"WEBAUTHN_RP_ORIGINS"`
+	OIDCIssuerURL        string        `mapstructure:"OIDC_ISSUER_URL"`
+	OIDCClientID         string        `mapstructure:"OIDC_CLIENT_ID"`
+	OIDCClientSecret     string        `mapstructure:"OIDC_CLIENT_SECRET"`
+	OIDCRedirectURL      string        `mapstructure:"OIDC_REDIRECT_URL"`
+	OIDCDefaultRole      string        `mapstructure:"OIDC_DEFAULT_ROLE"`
+	OIDCSignupDomains    []string      `mapstructure:"OIDC_SIGNUP_DOMAINS"`
+	HighlightTheme       string        `mapstructure:"HIGHLIGHT_THEME"`
 	HostName             string        `mapstructure:"HOST_NAME"`
+	FrontendURL          string        `mapstructure:"FRONTEND_URL"`
+	SMTPHost             string        `mapstructure:"SMTP_HOST"`
+	SMTPPort             int           `mapstructure:"SMTP_PORT"`
+	SMTPUsername         string        `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword         string        `mapstructure:"SMTP_PASSWORD"`
+	SMTPAllowInsecure    bool          `mapstructure:"SMTP_ALLOW_INSECURE"`
+	MailFrom             string        `mapstructure:"MAIL_FROM"`
+	ResetTokenDuration   time.Duration `mapstructure:"RESET_TOKEN_DURATION"`
+	PasswordMinLength    int           `mapstructure:"PASSWORD_MIN_LENGTH"`
+	PasswordDenylist     string        `mapstructure:"PASSWORD_DENYLIST_FILE"`
+	Argon2Memory         uint32        `mapstructure:"ARGON2_MEMORY"`
+	Argon2Iterations     uint32        `mapstructure:"ARGON2_ITERATIONS"`
+	Argon2Parallelism    uint8         `mapstructure:"ARGON2_PARALLELISM"`
 	AwsRegion            string        `mapstructure:"AWS_REGION"`
 	AwsKey               string        `mapstructure:"AWS_ACCESS_KEY_ID"`
 	AwsSecret            string        `mapstructure:"AWS_SECRET_ACCESS_KEY"`
 	AwsBucket            string        `mapstructure:"AWS_BUCKET_NAME"`
 }
 
+// Argon2Params returns the configured argon2id parameters, the missing ones take the default value
+func (config Config) Argon2Params() Argon2Params {
+	return Argon2Params{
+		Memory:      config.Argon2Memory,
+		Iterations:  config.Argon2Iterations,
+		Parallelism: config.Argon2Parallelism,
+	}
+}
+
+// Environments where the services that are not configured are replaced by local stand-ins,
+// any other environment is treated as production
+const (
+	DevelopmentEnvironment = "development"
+	TestEnvironment        = "test"
+)
+
+// IsDevelopment reports whether the application runs in a development or test environment
+func (config Config) IsDevelopment() bool {
+	return config.Environment == DevelopmentEnvironment || config.Environment == TestEnvironment
+}
+
 // LoadConfig reads configuration from file or envioroment variables.
 func LoadConfig(path, name string) (config Config, err error) {
 	viper.AddConfigPath(path)
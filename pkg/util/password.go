@@ -1,21 +1,173 @@
 package util
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// HashPassword return the bcrypt hash of the password
-func HashPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
+const (
+	argon2idHashPrefix = "$argon2id$"
+	// argon2idHashFields is the number of fields of $argon2id$v=19$m=65536,t=3,p=2$salt$key split by $
+	argon2idHashFields = 6
+)
+
+var (
+	ErrMismatchedPassword  = errors.New("the password does not match")
+	ErrUnknownPasswordHash = errors.New("unknown password hash format")
+	ErrInvalidArgon2Params = errors.New("invalid argon2 parameters")
+)
+
+var (
+	argon2Encoding     = base64.RawStdEncoding
+	bcryptHashPrefixes = []string{"$2a$", "$2b$", "$2y$"}
+)
+
+// Argon2Params are the argon2id parameters used to hash new passwords
+type Argon2Params struct {
+	// Memory is the amount of memory used in KiB
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params follow the OWASP recommendation for argon2id
+var DefaultArgon2Params = Argon2Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// PasswordHasher hashes passwords with argon2id in the PHC string format,
+// it still verifies the bcrypt hashes created before argon2id was adopted
+type PasswordHasher struct {
+	params Argon2Params
+}
+
+// NewPasswordHasher creates a password hasher, the zero parameters take the default value
+func NewPasswordHasher(params Argon2Params) (*PasswordHasher, error) {
+	if params.Memory == 0 {
+		params.Memory = DefaultArgon2Params.Memory
+	}
+	if params.Iterations == 0 {
+		params.Iterations = DefaultArgon2Params.Iterations
+	}
+	if params.Parallelism == 0 {
+		params.Parallelism = DefaultArgon2Params.Parallelism
+	}
+	if params.SaltLength == 0 {
+		params.SaltLength = DefaultArgon2Params.SaltLength
+	}
+	if params.KeyLength == 0 {
+		params.KeyLength = DefaultArgon2Params.KeyLength
+	}
+
+	if params.Memory < 8*uint32(params.Parallelism) {
+		return nil, fmt.Errorf("%w: the memory must be at least 8 KiB per thread", ErrInvalidArgon2Params)
+	}
+	return &PasswordHasher{params: params}, nil
+}
+
+// Hash returns the argon2id hash of the password
+func (hasher *PasswordHasher) Hash(password string) (string, error) {
+	salt := make([]byte, hasher.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
 		return "", fmt.Errorf("failed to hash password: %w", err)
 	}
-	return string(hashedPassword), nil
+
+	key := argon2.IDKey([]byte(password), salt, hasher.params.Iterations, hasher.params.Memory, hasher.params.Parallelism, hasher.params.KeyLength)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, hasher.params.Memory, hasher.params.Iterations, hasher.params.Parallelism,
+		argon2Encoding.EncodeToString(salt), argon2Encoding.EncodeToString(key),
+	), nil
+}
+
+// Check returns ErrMismatchedPassword if the password does not match the argon2id or bcrypt hash
+func (hasher *PasswordHasher) Check(password string, hashedPassword string) error {
+	if isBcryptHash(hashedPassword) {
+		err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrMismatchedPassword
+		}
+		return err
+	}
+
+	params, salt, key, err := decodeArgon2Hash(hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	otherKey := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	if subtle.ConstantTimeCompare(key, otherKey) != 1 {
+		return ErrMismatchedPassword
+	}
+	return nil
+}
+
+// NeedsRehash reports whether a hash was created with bcrypt or with other argon2id parameters,
+// the password should be hashed again once it is known to be correct
+func (hasher *PasswordHasher) NeedsRehash(hashedPassword string) bool {
+	if isBcryptHash(hashedPassword) {
+		return true
+	}
+
+	params, _, _, err := decodeArgon2Hash(hashedPassword)
+	if err != nil {
+		return false
+	}
+	return params != hasher.params
+}
+
+func isBcryptHash(hashedPassword string) bool {
+	for _, prefix := range bcryptHashPrefixes {
+		if strings.HasPrefix(hashedPassword, prefix) {
+			return true
+		}
+	}
+	return false
 }
 
-// CheckPassword checks if the provided password is correct or not
-func CheckPassword(password string, hashedPassword string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+// decodeArgon2Hash parses the parameters, the salt and the key of an argon2id hash
+func decodeArgon2Hash(hashedPassword string) (Argon2Params, []byte, []byte, error) {
+	fields := strings.Split(hashedPassword, "$")
+	if !strings.HasPrefix(hashedPassword, argon2idHashPrefix) || len(fields) != argon2idHashFields {
+		return Argon2Params{}, nil, nil, ErrUnknownPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(fields[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Argon2Params{}, nil, nil, ErrUnknownPasswordHash
+	}
+
+	var params Argon2Params
+	_, err := fmt.Sscanf(fields[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism)
+	if err != nil {
+		return Argon2Params{}, nil, nil, ErrUnknownPasswordHash
+	}
+
+	salt, err := argon2Encoding.DecodeString(fields[4])
+	if err != nil {
+		return Argon2Params{}, nil, nil, ErrUnknownPasswordHash
+	}
+
+	key, err := argon2Encoding.DecodeString(fields[5])
+	if err != nil {
+		return Argon2Params{}, nil, nil, ErrUnknownPasswordHash
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
 }
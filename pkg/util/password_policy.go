@@ -0,0 +1,86 @@
+package util
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultPasswordMinLength is the minimum length of a password when it is not configured
+const DefaultPasswordMinLength = 8
+
+// ErrWeakPassword is wrapped by every password policy violation
+var ErrWeakPassword = errors.New("weak password")
+
+//go:embed common_passwords.txt
+var commonPasswords string
+
+// PasswordPolicy rejects passwords that are too short or appear on a denylist,
+// the denylist is compared without case
+type PasswordPolicy struct {
+	minLength int
+	denylist  map[string]struct{}
+}
+
+// NewPasswordPolicy creates a password policy with the built-in list of common passwords,
+// the passwords of denylistFile are added to it when the path is not empty
+func NewPasswordPolicy(minLength int, denylistFile string) (*PasswordPolicy, error) {
+	if minLength <= 0 {
+		minLength = DefaultPasswordMinLength
+	}
+
+	policy := &PasswordPolicy{
+		minLength: minLength,
+		denylist:  map[string]struct{}{},
+	}
+
+	err := policy.addDenylist(strings.NewReader(commonPasswords))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(denylistFile) > 0 {
+		file, err := os.Open(denylistFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open password denylist: %w", err)
+		}
+		defer file.Close()
+
+		err = policy.addDenylist(file)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read password denylist: %w", err)
+		}
+	}
+
+	return policy, nil
+}
+
+// addDenylist reads one password per line, the empty lines and the lines starting with # are skipped
+func (policy *PasswordPolicy) addDenylist(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		policy.denylist[strings.ToLower(line)] = struct{}{}
+	}
+	return scanner.Err()
+}
+
+// Validate returns an error wrapping ErrWeakPassword when the password breaks the policy
+func (policy *PasswordPolicy) Validate(password string) error {
+	if utf8.RuneCountInString(password) < policy.minLength {
+		return fmt.Errorf("%w: it must have at least %d characters", ErrWeakPassword, policy.minLength)
+	}
+
+	if _, ok := policy.denylist[strings.ToLower(password)]; ok {
+		return fmt.Errorf("%w: it is too common or appeared in a data breach", ErrWeakPassword)
+	}
+	return nil
+}
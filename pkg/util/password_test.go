@@ -0,0 +1,112 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testArgon2Params keep the tests fast
+var testArgon2Params = Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1}
+
+func TestPasswordHasher(t *testing.T) {
+	hasher, err := NewPasswordHasher(testArgon2Params)
+	require.NoError(t, err)
+
+	password := RandomString(12)
+	hashedPassword, err := hasher.Hash(password)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(hashedPassword, "$argon2id$v=19$m=64,t=1,p=1$"))
+
+	require.NoError(t, hasher.Check(password, hashedPassword))
+	require.ErrorIs(t, hasher.Check(RandomString(12), hashedPassword), ErrMismatchedPassword)
+	require.False(t, hasher.NeedsRehash(hashedPassword))
+
+	// Every hash has its own salt
+	otherHash, err := hasher.Hash(password)
+	require.NoError(t, err)
+	require.NotEqual(t, hashedPassword, otherHash)
+}
+
+func TestPasswordHasherBcrypt(t *testing.T) {
+	hasher, err := NewPasswordHasher(testArgon2Params)
+	require.NoError(t, err)
+
+	password := RandomString(12)
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	require.NoError(t, hasher.Check(password, string(bcryptHash)))
+	require.ErrorIs(t, hasher.Check(RandomString(12), string(bcryptHash)), ErrMismatchedPassword)
+	require.True(t, hasher.NeedsRehash(string(bcryptHash)))
+}
+
+func TestPasswordHasherRehash(t *testing.T) {
+	oldHasher, err := NewPasswordHasher(testArgon2Params)
+	require.NoError(t, err)
+
+	newParams := testArgon2Params
+	newParams.Iterations = 2
+	newHasher, err := NewPasswordHasher(newParams)
+	require.NoError(t, err)
+
+	password := RandomString(12)
+	hashedPassword, err := oldHasher.Hash(password)
+	require.NoError(t, err)
+
+	// The old parameters are read from the hash
+	require.NoError(t, newHasher.Check(password, hashedPassword))
+	require.True(t, newHasher.NeedsRehash(hashedPassword))
+}
+
+func TestPasswordHasherInvalidHash(t *testing.T) {
+	hasher, err := NewPasswordHasher(testArgon2Params)
+	require.NoError(t, err)
+
+	for _, hashedPassword := range []string{
+		"",
+		"plain",
+		"$argon2i$v=19$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$!!$a2V5",
+	} {
+		require.ErrorIs(t, hasher.Check("password", hashedPassword), ErrUnknownPasswordHash)
+		require.False(t, hasher.NeedsRehash(hashedPassword))
+	}
+}
+
+func TestNewPasswordHasherInvalidParams(t *testing.T) {
+	_, err := NewPasswordHasher(Argon2Params{Memory: 8, Parallelism: 4})
+	require.ErrorIs(t, err, ErrInvalidArgon2Params)
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	denylistFile := filepath.Join(t.TempDir(), "denylist.txt")
+	err := os.WriteFile(denylistFile, []byte("# breached\nCorrectHorse\n\n"), 0600)
+	require.NoError(t, err)
+
+	policy, err := NewPasswordPolicy(10, denylistFile)
+	require.NoError(t, err)
+
+	require.NoError(t, policy.Validate(RandomString(10)))
+	require.ErrorIs(t, policy.Validate(RandomString(9)), ErrWeakPassword)
+	require.ErrorIs(t, policy.Validate("correcthorse"), ErrWeakPassword)
+	require.ErrorIs(t, policy.Validate("Password123"), ErrWeakPassword)
+
+	// The length is counted in characters, not bytes
+	require.ErrorIs(t, policy.Validate("ñandúñand"), ErrWeakPassword)
+	require.NoError(t, policy.Validate("ñandúñandú"))
+
+	defaultPolicy, err := NewPasswordPolicy(0, "")
+	require.NoError(t, err)
+	require.ErrorIs(t, defaultPolicy.Validate(RandomString(DefaultPasswordMinLength-1)), ErrWeakPassword)
+	require.NoError(t, defaultPolicy.Validate("correcthorse"))
+
+	_, err = NewPasswordPolicy(0, filepath.Join(t.TempDir(), "missing.txt"))
+	require.Error(t, err)
+}
@@ -0,0 +1,29 @@
+package util
+
+// Roles that can be assigned to a user, from the most to the least privileged
+const (
+	AdminRole  = "admin"
+	EditorRole = "editor"
+	AuthorRole = "author"
+)
+
+var roleRanks = map[string]int{
+	AuthorRole: 1,
+	EditorRole: 2,
+	AdminRole:  3,
+}
+
+// IsSupportedRole returns true if the role is one of the known roles
+func IsSupportedRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// HasRole checks if the role grants at least the permissions of the required role
+func HasRole(role string, required string) bool {
+	rank, ok := roleRanks[role]
+	if !ok {
+		return false
+	}
+	return rank >= roleRanks[required]
+}
@@ -0,0 +1,23 @@
+package util
+
+// Scopes that can be granted to a personal access token
+const (
+	PostsReadScope       = "posts:read"
+	PostsWriteScope      = "posts:write"
+	TagsWriteScope       = "tags:write"
+	CategoriesWriteScope = "categories:write"
+	UsersAdminScope      = "users:admin"
+)
+
+var scopes = map[string]bool{
+	PostsReadScope:       true,
+	PostsWriteScope:      true,
+	TagsWriteScope:       true,
+	CategoriesWriteScope: true,
+	UsersAdminScope:      true,
+}
+
+// IsSupportedScope returns true if the scope is one of the known scopes
+func IsSupportedScope(scope string) bool {
+	return scopes[scope]
+}
@@ -0,0 +1,34 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gosimple/slug"
+)
+
+// SlugMaxLength is the maximum length of the slugs made by Slugify
+const SlugMaxLength = 100
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Slugify makes a URL slug of a text, the Unicode characters are transliterated to ASCII
+// and long texts are cut on a word boundary. It returns an empty string when nothing is left.
+func Slugify(text string) string {
+	result := slug.Make(text)
+	if len(result) <= SlugMaxLength {
+		return result
+	}
+
+	result = result[:SlugMaxLength]
+	if i := strings.LastIndexByte(result, '-'); i > 0 {
+		result = result[:i]
+	}
+	return strings.Trim(result, "-")
+}
+
+// IsSlug reports whether the text is a valid slug: lowercase ASCII letters and digits
+// separated by single hyphens, at most SlugMaxLength long
+func IsSlug(text string) bool {
+	return len(text) <= SlugMaxLength && slugPattern.MatchString(text)
+}
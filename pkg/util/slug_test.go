@@ -0,0 +1,52 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlugify(t *testing.T) {
+	testCases := []struct {
+		text string
+		slug string
+	}{
+		{text: "Hello World", slug: "hello-world"},
+		{text: "  Go: 10 tips & tricks!  ", slug: "go-10-tips-and-tricks"},
+		{text: "Ñandú en el café", slug: "nandu-en-el-cafe"},
+		{text: "Привет мир", slug: "privet-mir"},
+		{text: "¿?!", slug: ""},
+	}
+
+	for _, tc := range testCases {
+		slug := Slugify(tc.text)
+		require.Equal(t, tc.slug, slug)
+		if len(slug) > 0 {
+			require.True(t, IsSlug(slug))
+		}
+	}
+}
+
+func TestSlugifyLongText(t *testing.T) {
+	text := strings.Repeat("word ", 40)
+
+	slug := Slugify(text)
+	require.LessOrEqual(t, len(slug), SlugMaxLength)
+	require.True(t, IsSlug(slug))
+	require.True(t, strings.HasSuffix(slug, "-word"))
+}
+
+func TestIsSlug(t *testing.T) {
+	require.True(t, IsSlug("a"))
+	require.True(t, IsSlug("hello-world-2"))
+
+	require.False(t, IsSlug(""))
+	require.False(t, IsSlug("Hello-world"))
+	require.False(t, IsSlug("hello--world"))
+	require.False(t, IsSlug("-hello"))
+	require.False(t, IsSlug("hello-"))
+	require.False(t, IsSlug("hello_world"))
+	require.False(t, IsSlug("niño"))
+	require.False(t, IsSlug(strings.Repeat("a", SlugMaxLength+1)))
+}